@@ -2,103 +2,295 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	"net/netip"
 	"os"
+	"os/signal"
+	"playcorner-be/internal/auth"
+	"playcorner-be/internal/config"
 	"playcorner-be/internal/database"
-	"playcorner-be/internal/models"
+	"playcorner-be/internal/events"
+	"playcorner-be/internal/handlers"
+	"playcorner-be/internal/health"
+	"playcorner-be/internal/logging"
+	"playcorner-be/internal/metrics"
+	"playcorner-be/internal/middleware"
+	"playcorner-be/internal/ratelimit"
+	"playcorner-be/internal/repository"
+	"playcorner-be/internal/repository/memory"
+	"playcorner-be/internal/repository/postgres"
 	"playcorner-be/internal/routes"
-	"playcorner-be/internal/utils"
+	"playcorner-be/internal/scheduler"
+	"playcorner-be/internal/services"
+	"playcorner-be/internal/tracing"
+	"runtime/debug"
+	"syscall"
+	"time"
 
+	"github.com/gofiber/contrib/otelfiber/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"gorm.io/gorm"
 )
 
 func main() {
-	app := fiber.New()
-	app.Use(logger.New())
+	// Konfigurasi dimuat dan divalidasi sekali, lalu diteruskan ke setiap subsistem
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger := logging.Setup(cfg.Log)
+
+	// Subcommand dijalankan tanpa menyalakan server HTTP
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			runMigrate(cfg, os.Args[2:])
+			return
+		case "seed":
+			runSeed(cfg, os.Args[2:])
+			return
+		default:
+			logging.Fatal("Unknown command (available: migrate, seed)", fmt.Errorf("%q", os.Args[1]))
+		}
+	}
+
+	// Tracing dipasang paling awal agar span request mencakup semua middleware
+	tracer, err := tracing.Setup(context.Background(), cfg.Tracing, cfg.Env)
+	if err != nil {
+		logging.Fatal("Failed to set up tracing", err)
+	}
+
+	app := fiber.New(fiber.Config{
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+		ErrorHandler: handlers.ErrorHandler,
+	})
+	app.Use(otelfiber.Middleware(
+		otelfiber.WithTracerProvider(tracer),
+		otelfiber.WithPropagators(tracer.Propagator),
+	))
+	trusted, err := cfg.Server.TrustedProxyPrefixes()
+	if err != nil {
+		logging.Fatal("Invalid trusted proxies", err)
+	}
+	app.Use(logging.Middleware(logger, func(c *fiber.Ctx) string { return ratelimit.ClientIP(c, trusted) }))
+
+	// Metrik dicatat untuk semua request, termasuk yang ditolak CORS atau auth
+	appMetrics := metrics.New()
+	app.Use(appMetrics.Middleware())
+	// Panic di handler diubah menjadi 500 yang tercatat lengkap dengan stack trace
+	app.Use(recover.New(recover.Config{
+		EnableStackTrace: true,
+		StackTraceHandler: func(c *fiber.Ctx, e any) {
+			logging.ForRequest(c).Error("panic recovered", "panic", e, "stack", string(debug.Stack()))
+		},
+	}))
 	app.Use(cors.New(cors.Config{
-		AllowOrigins:     os.Getenv("CORS_ALLOWED_ORIGINS"),
+		AllowOrigins:     cfg.Server.CORSAllowedOrigins,
 		AllowCredentials: true,
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Request-ID, Idempotency-Key",
+		ExposeHeaders: "X-Request-ID, Idempotent-Replayed, Retry-After, " +
+			"RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy",
 	}))
 
-	database.ConnectDB()
+	db := database.ConnectDB(cfg.Database)
+	if sqlDB, err := db.DB(); err == nil {
+		appMetrics.RegisterDB(sqlDB)
+	}
+	if err := database.Instrument(db, tracer, cfg.Database.Name); err != nil {
+		logging.Fatal("Failed to instrument database", err)
+	}
 
-	// Menambahkan data awal ke database jika belum ada
-	seedDatabase()
+	// Menyusun dependensi: repository Postgres -> service -> handler
+	store := postgres.NewStore(db)
+	tokens := auth.NewManager(cfg.JWT)
+	authService := services.NewAuthService(store.Users(), tokens)
+	bookingService := services.NewBookingService(store, cfg.Booking)
 
-	routes.SetupRoutes(app)
-	log.Fatal(app.Listen(":3000"))
-}
+	// Pekerjaan latar belakang
+	jobs := scheduler.New()
+	jobs.Add(scheduler.Job{
+		Name:     "settle-reservations",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			result, err := bookingService.SettleReservations(ctx)
+			appMetrics.NoShows.Add(float64(result.NoShows))
+			for _, played := range result.PlayedMinutes {
+				appMetrics.SessionPlayedMinutes.Observe(float64(played))
+			}
+			return err
+		},
+	})
+	// Tawaran waitlist yang tidak diterima tepat waktu diteruskan ke antrean berikutnya
+	jobs.Add(scheduler.Job{
+		Name:     "waitlist-offers",
+		Interval: 15 * time.Second,
+		Run:      bookingService.ExpireOffers,
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "tv-utilization",
+		Interval: 30 * time.Second,
+		Run: func(ctx context.Context) error {
+			usage, err := bookingService.Utilization(ctx)
+			if err != nil {
+				return err
+			}
+			for _, u := range usage {
+				appMetrics.SetUtilization(u.LocationID, u.InUse, u.InService)
+			}
+			return nil
+		},
+	})
+	// Event ketersediaan dari semua instance diterima lewat LISTEN/NOTIFY;
+	// listener dijalankan ulang oleh scheduler jika koneksinya terputus
+	availability := events.NewHub(store.AvailabilityEvents())
+	jobs.Add(scheduler.Job{
+		Name:     "availability-listener",
+		Interval: 5 * time.Second,
+		Run:      availability.Listen,
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "purge-availability-events",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			_, err := store.AvailabilityEvents().DeleteBefore(ctx, time.Now().Add(-events.Retention))
+			return err
+		},
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "purge-idempotency-keys",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			_, err := store.IdempotencyKeys().DeleteBefore(ctx, time.Now().Add(-middleware.IdempotencyKeyTTL))
+			return err
+		},
+	})
 
-// seedDatabase membuat data awal untuk testing
-func seedDatabase() {
-	// Cek apakah data sudah ada untuk menghindari duplikasi
-	var tvCount int64
-	database.DB.Model(&models.TVInfo{}).Count(&tvCount)
-
-	if tvCount == 0 {
-		log.Println("Seeding database with fixed relationships...")
-
-		// Buat User
-		hashedPassword, _ := utils.HashPassword("password123")
-		user := models.User{
-			ID:             "235150207111062",
-			Name:           "Muhammad Rafly Ash Shiddiqi",
-			Faculty:        "FILKOM",
-			Major:          "Teknik Informatika",
-			CreditScore:    100,
-			ProfilePictURL: "https://i.pravatar.cc/150?u=235150207111062",
-			PasswordHash:   hashedPassword,
-		}
-		database.DB.Create(&user)
-
-		// Definisikan semua game sebagai pointer
-		gameFC24 := &models.Game{Title: "EA Sports FC 24", CoverPictURL: "https://placehold.co/200x300/3498DB/FFFFFF?text=FC+24"}
-		gameTekken8 := &models.Game{Title: "Tekken 8", CoverPictURL: "https://placehold.co/200x300/E74C3C/FFFFFF?text=Tekken+8"}
-		gameItTakesTwo := &models.Game{Title: "It Takes Two", CoverPictURL: "https://placehold.co/200x300/F1C40F/FFFFFF?text=It+Takes+Two"}
-		gameOvercooked := &models.Game{Title: "Overcooked! 2", CoverPictURL: "https://placehold.co/200x300/9B59B6/FFFFFF?text=Overcooked"}
-
-		// Buat semua game dari slice of pointers. GORM akan mengisi ID kembali ke variabel asli.
-		allGames := []*models.Game{gameFC24, gameTekken8, gameItTakesTwo, gameOvercooked}
-		if err := database.DB.Create(allGames).Error; err != nil {
-			log.Fatalf("Failed to seed games: %v", err)
-		}
+	limits, err := setupRateLimits(cfg, store, jobs, trusted)
+	if err != nil {
+		logging.Fatal("Failed to set up rate limiting", err)
+	}
 
-		// 3. Definisikan dan buat TV, sekarang mereferensikan game yang sudah memiliki ID
-		tvsToCreate := []models.TVInfo{
-			{
-				ID:          1,
-				ConsoleType: "PlayStation 5",
-				Games:       []*models.Game{gameFC24, gameTekken8}, // Sekarang gameFC24.ID bukan lagi 0
-			},
-			{
-				ID:          2,
-				ConsoleType: "PlayStation 5",
-				Games:       []*models.Game{gameFC24, gameItTakesTwo},
-			},
-			{
-				ID:          3,
-				ConsoleType: "Xbox Series X",
-				Games:       []*models.Game{gameFC24, gameOvercooked},
-			},
-			{
-				ID:          4,
-				ConsoleType: "Xbox Series X",
-				Games:       []*models.Game{gameFC24},
-			},
-			{
-				ID:          5,
-				ConsoleType: "PC",
-				Games:       []*models.Game{gameFC24, gameItTakesTwo, gameTekken8},
-			},
+	// Pemeriksaan readiness untuk /readyz
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		logging.Fatal("Failed to load migrations", err)
+	}
+	checker := health.NewChecker(2 * time.Second)
+	checker.Add("database", func(ctx context.Context) error { return database.Ping(ctx, db) })
+	checker.Add("migrations", migrator.CheckLatest)
+	checker.Add("scheduler", func(context.Context) error {
+		if !jobs.Running() {
+			return errors.New("scheduler is not running")
 		}
+		return nil
+	})
 
-		if err := database.DB.Create(&tvsToCreate).Error; err != nil {
-			log.Fatalf("Failed to seed tvs with associations: %v", err)
-		}
+	h := handlers.New(handlers.Dependencies{
+		Auth:        authService,
+		Users:       services.NewUserService(store),
+		Booking:     bookingService,
+		Maintenance: services.NewMaintenanceService(store),
+		Locations:   services.NewLocationService(store),
+		Devices:     services.NewDeviceService(store),
+		Kiosk:       services.NewKioskService(store),
+		Health:      checker,
+		Metrics:     appMetrics,
+
+		Availability:    availability,
+		IdempotencyKeys: store.IdempotencyKeys(),
+		UploadDir:       cfg.Server.UploadDir,
+		SecureCookies:   cfg.IsProduction(),
+	})
 
-		log.Println("Seeding complete.")
+	// /metrics hanya aktif jika METRICS_TOKEN diisi, dan diblokir Nginx dari publik
+	if cfg.Metrics.Token != "" {
+		app.Get("/metrics", appMetrics.Handler(cfg.Metrics.Token))
+	}
+	routes.SetupRoutes(app, h, tokens, authService, limits)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	jobs.Start(ctx)
+
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.Listen(fmt.Sprintf(":%d", cfg.Server.Port))
+	}()
+
+	select {
+	case err := <-listenErr:
+		// Server gagal dijalankan (misalnya port sudah dipakai)
+		slog.Error("Server stopped", "error", err)
+	case <-ctx.Done():
+		slog.Info("Shutdown signal received, draining in-flight requests")
+	}
+	stop()
+
+	// Readiness gagal lebih dulu agar load balancer berhenti mengarahkan trafik
+	checker.SetShuttingDown()
+	time.Sleep(cfg.Server.ShutdownDrainDelay)
+
+	shutdown(app, jobs, db, tracer, cfg.Server.ShutdownTimeout)
+}
+
+// setupRateLimits membuat middleware pembatas laju untuk setiap kelompok rute.
+// Bucket disimpan di memori proses, atau di Postgres jika API dijalankan
+// dengan beberapa instance agar batasnya berlaku bersama.
+func setupRateLimits(cfg *config.Config, store *postgres.Store, jobs *scheduler.Scheduler, trusted []netip.Prefix) (routes.RateLimits, error) {
+	if !cfg.RateLimit.Enabled {
+		return routes.RateLimits{}, nil
+	}
+
+	var buckets repository.RateLimitRepository
+	if cfg.RateLimit.Store == "postgres" {
+		buckets = store.RateLimits()
+	} else {
+		buckets = memory.New().RateLimits()
+	}
+	jobs.Add(scheduler.Job{
+		Name:     "purge-rate-limit-buckets",
+		Interval: 10 * time.Minute,
+		Run: func(ctx context.Context) error {
+			_, err := buckets.DeleteIdle(ctx, time.Now().Add(-cfg.RateLimit.MaxPeriod()))
+			return err
+		},
+	})
+
+	limiter := ratelimit.New(buckets, trusted)
+	return routes.RateLimits{
+		Default: limiter.Middleware(ratelimit.Policy{Name: "default", RateLimitPolicy: cfg.RateLimit.Default}),
+		Auth:    limiter.Middleware(ratelimit.Policy{Name: "auth", RateLimitPolicy: cfg.RateLimit.Auth}),
+		Booking: limiter.Middleware(ratelimit.Policy{Name: "booking", RateLimitPolicy: cfg.RateLimit.Booking}),
+	}, nil
+}
+
+// shutdown berhenti menerima koneksi baru, menunggu request yang sedang berjalan
+// selesai, menghentikan job latar belakang, menutup pool database, lalu mengirim
+// sisa span. Semua langkah berbagi satu batas waktu.
+func shutdown(app *fiber.App, jobs *scheduler.Scheduler, db *gorm.DB, tracer *tracing.Provider, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := app.ShutdownWithContext(ctx); err != nil {
+		slog.Error("HTTP server shutdown", "error", err)
+	}
+	if err := jobs.Stop(ctx); err != nil {
+		slog.Error("Background jobs did not stop in time", "error", err)
+	}
+	if err := database.Close(db); err != nil {
+		slog.Error("Closing database", "error", err)
+	}
+	if err := tracer.Shutdown(ctx); err != nil {
+		slog.Error("Flushing traces", "error", err)
 	}
+	slog.Info("Shutdown complete")
 }
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"playcorner-be/internal/config"
+	"playcorner-be/internal/database"
+	"playcorner-be/internal/logging"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = `Usage: main migrate <command>
+
+Commands:
+  up          Terapkan semua migrasi yang belum dijalankan
+  down        Batalkan satu migrasi terakhir
+  status      Tampilkan versi skema dan daftar migrasi
+  to <versi>  Naik atau turun ke versi tertentu (0 = kosongkan skema)`
+
+// runMigrate menjalankan subcommand `migrate` tanpa menyalakan server HTTP.
+func runMigrate(cfg *config.Config, args []string) {
+	if len(args) == 0 {
+		fmt.Println(migrateUsage)
+		os.Exit(2)
+	}
+
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		logging.Fatal("Failed to connect to database", err)
+	}
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		logging.Fatal("Failed to load migrations", err)
+	}
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		err = migrator.Down()
+	case "to":
+		if len(args) < 2 {
+			fmt.Println(migrateUsage)
+			os.Exit(2)
+		}
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil {
+			logging.Fatal("Invalid version", convErr)
+		}
+		err = migrator.To(version)
+	case "status":
+		err = printMigrationStatus(migrator)
+	default:
+		fmt.Println(migrateUsage)
+		os.Exit(2)
+	}
+	if err != nil {
+		logging.Fatal("Migration failed", err)
+	}
+
+	if args[0] != "status" {
+		current, _ := migrator.CurrentVersion()
+		slog.Info("Migration complete", "schema_version", current)
+	}
+	_ = database.Close(db)
+}
+
+func printMigrationStatus(migrator *database.Migrator) error {
+	current, err := migrator.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Current version: %d (latest known: %d)\n\n", current, migrator.Latest())
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, s := range statuses {
+		appliedAt := "pending"
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+	}
+	if current > migrator.Latest() {
+		fmt.Fprintf(w, "%d\t(unknown to this binary)\t-\n", current)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"log/slog"
+	"playcorner-be/internal/config"
+	"playcorner-be/internal/database"
+	"playcorner-be/internal/logging"
+	"playcorner-be/internal/seed"
+	"strings"
+)
+
+// runSeed menjalankan subcommand `seed` untuk memuat fixture ke database.
+// Seeding ditolak saat APP_ENV=production agar akun contoh tidak pernah
+// masuk ke database produksi.
+func runSeed(cfg *config.Config, args []string) {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	profile := fs.String("profile", "dev", "fixture profile bawaan: "+strings.Join(seed.Profiles, ", "))
+	file := fs.String("file", "", "path ke file fixture YAML/JSON (menggantikan --profile)")
+	_ = fs.Parse(args)
+
+	if cfg.IsProduction() {
+		logging.Fatal("Refusing to seed", errors.New("APP_ENV is production"))
+	}
+
+	var (
+		fixture *seed.Fixture
+		err     error
+	)
+	if *file != "" {
+		fixture, err = seed.LoadFile(*file)
+	} else {
+		fixture, err = seed.LoadProfile(*profile)
+	}
+	if err != nil {
+		logging.Fatal("Could not load fixture", err)
+	}
+
+	// Seeding memerlukan skema terbaru, sama seperti saat server dijalankan
+	db := database.ConnectDB(cfg.Database)
+
+	if err := seed.Apply(db, fixture); err != nil {
+		logging.Fatal("Seeding failed", err)
+	}
+	slog.Info("Seeding complete")
+	_ = database.Close(db)
+}
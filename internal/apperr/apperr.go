@@ -0,0 +1,81 @@
+// Package apperr
+package apperr
+
+import (
+	"errors"
+	"playcorner-be/internal/models"
+)
+
+// Kind mengelompokkan kesalahan dan menentukan status HTTP-nya.
+type Kind int
+
+// Jenis kesalahan yang dikenali ErrorHandler.
+const (
+	KindInternal Kind = iota
+	KindBadRequest
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindQuotaExceeded
+)
+
+// Error adalah kesalahan domain dengan kode yang stabil untuk klien. Msg aman
+// ditampilkan ke pengguna; Code tidak boleh berubah karena dipakai frontend.
+type Error struct {
+	Kind   Kind
+	Code   string
+	Msg    string
+	Fields []models.FieldError
+
+	cause error
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Msg + ": " + e.cause.Error()
+	}
+	return e.Msg
+}
+
+func (e *Error) Unwrap() error { return e.cause }
+
+func newError(kind Kind, code, msg string) *Error {
+	return &Error{Kind: kind, Code: code, Msg: msg}
+}
+
+// BadRequest membuat kesalahan untuk request yang tidak dapat dibaca sama sekali,
+// misalnya body JSON yang rusak.
+func BadRequest(code, msg string) *Error { return newError(KindBadRequest, code, msg) }
+
+// Validation membuat kesalahan input, opsional dengan detail per field.
+func Validation(code, msg string, fields ...models.FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: code, Msg: msg, Fields: fields}
+}
+
+// Konstruktor untuk jenis kesalahan lainnya.
+func Unauthorized(code, msg string) *Error  { return newError(KindUnauthorized, code, msg) }
+func Forbidden(code, msg string) *Error     { return newError(KindForbidden, code, msg) }
+func NotFound(code, msg string) *Error      { return newError(KindNotFound, code, msg) }
+func Conflict(code, msg string) *Error      { return newError(KindConflict, code, msg) }
+func QuotaExceeded(code, msg string) *Error { return newError(KindQuotaExceeded, code, msg) }
+
+// Field membuat kesalahan validasi untuk satu field.
+func Field(field, msg string) *Error {
+	return Validation("VALIDATION_FAILED", msg, models.FieldError{Field: field, Message: msg})
+}
+
+// Wrap mengembalikan err apa adanya jika sudah berupa *Error. Kesalahan lain
+// dibungkus sebagai internal error dengan pesan msg untuk klien, sedangkan
+// penyebab aslinya tetap tersedia untuk log.
+func Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return err
+	}
+	return &Error{Kind: KindInternal, Code: "INTERNAL_ERROR", Msg: msg, cause: err}
+}
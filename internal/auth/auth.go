@@ -2,29 +2,49 @@
 package auth
 
 import (
-	"os"
+	"playcorner-be/internal/config"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
-
 type Claims struct {
 	UserID string `json:"user_id"`
 	jwt.RegisteredClaims
 }
 
+// Manager membuat dan memvalidasi token JWT dengan secret dan masa berlaku dari konfigurasi.
+type Manager struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewManager membuat Manager dari konfigurasi JWT yang sudah divalidasi.
+func NewManager(cfg config.JWTConfig) *Manager {
+	return &Manager{
+		secret:     []byte(cfg.Secret),
+		accessTTL:  cfg.AccessTTL,
+		refreshTTL: cfg.RefreshTTL,
+	}
+}
+
+// AccessTTL adalah masa berlaku access token.
+func (m *Manager) AccessTTL() time.Duration { return m.accessTTL }
+
+// RefreshTTL adalah masa berlaku refresh token.
+func (m *Manager) RefreshTTL() time.Duration { return m.refreshTTL }
+
 // GenerateTokens membuat access token dan refresh token baru
-func GenerateTokens(userID string) (string, string, error) {
+func (m *Manager) GenerateTokens(userID string) (string, string, error) {
 	// Membuat access token (durasi pendek)
-	accessToken, err := generateAccessToken(userID)
+	accessToken, err := m.sign(userID, m.accessTTL)
 	if err != nil {
 		return "", "", err
 	}
 
 	// Membuat refresh token (durasi panjang)
-	refreshToken, err := generateRefreshToken(userID)
+	refreshToken, err := m.sign(userID, m.refreshTTL)
 	if err != nil {
 		return "", "", err
 	}
@@ -32,20 +52,8 @@ func GenerateTokens(userID string) (string, string, error) {
 	return accessToken, refreshToken, nil
 }
 
-func generateAccessToken(userID string) (string, error) {
-	expirationTime := time.Now().Add(15 * time.Minute) // Access token valid selama 15 menit
-	claims := &Claims{
-		UserID: userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-		},
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
-}
-
-func generateRefreshToken(userID string) (string, error) {
-	expirationTime := time.Now().Add(7 * 24 * time.Hour) // Refresh token valid selama 7 hari
+func (m *Manager) sign(userID string, ttl time.Duration) (string, error) {
+	expirationTime := time.Now().Add(ttl)
 	claims := &Claims{
 		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -53,15 +61,15 @@ func generateRefreshToken(userID string) (string, error) {
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return token.SignedString(m.secret)
 }
 
 // ValidateToken memvalidasi token JWT
-func ValidateToken(tokenString string) (*Claims, error) {
+func (m *Manager) ValidateToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,551 @@
+// Package config
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Lingkungan aplikasi yang dikenal.
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// Config adalah seluruh konfigurasi aplikasi. Nilainya dimuat oleh Load dan
+// diteruskan secara eksplisit ke setiap subsistem.
+type Config struct {
+	Env       string          `yaml:"env"`
+	Server    ServerConfig    `yaml:"server"`
+	Database  DatabaseConfig  `yaml:"database"`
+	JWT       JWTConfig       `yaml:"jwt"`
+	Booking   BookingConfig   `yaml:"booking"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	Log       LogConfig       `yaml:"log"`
+	RateLimit RateLimitConfig `yaml:"rateLimit"`
+}
+
+type ServerConfig struct {
+	Port               int    `yaml:"port"`
+	CORSAllowedOrigins string `yaml:"corsAllowedOrigins"`
+	UploadDir          string `yaml:"uploadDir"`
+
+	ReadTimeout  time.Duration `yaml:"readTimeout"`
+	WriteTimeout time.Duration `yaml:"writeTimeout"`
+	IdleTimeout  time.Duration `yaml:"idleTimeout"`
+	// ShutdownTimeout adalah batas waktu menunggu request yang sedang berjalan saat shutdown.
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+	// ShutdownDrainDelay adalah jeda antara /readyz mulai gagal dan server berhenti
+	// menerima koneksi, agar load balancer sempat berhenti mengarahkan trafik.
+	ShutdownDrainDelay time.Duration `yaml:"shutdownDrainDelay"`
+
+	// TrustedProxies adalah IP atau CIDR proxy (misalnya Nginx) yang header
+	// X-Forwarded-For-nya dipercaya untuk menentukan IP klien.
+	TrustedProxies []string `yaml:"trustedProxies"`
+}
+
+type DatabaseConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"sslMode"`
+	TimeZone string `yaml:"timeZone"`
+
+	// Pengaturan pool koneksi database/sql.
+	MaxOpenConns    int           `yaml:"maxOpenConns"`
+	MaxIdleConns    int           `yaml:"maxIdleConns"`
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"connMaxIdleTime"`
+	// StatementTimeout dikirim sebagai statement_timeout Postgres; 0 berarti tanpa batas.
+	StatementTimeout time.Duration `yaml:"statementTimeout"`
+	// SlowQueryThreshold: query yang lebih lama dari ini dicatat sebagai warning.
+	SlowQueryThreshold time.Duration `yaml:"slowQueryThreshold"`
+}
+
+type JWTConfig struct {
+	Secret     string        `yaml:"secret"`
+	AccessTTL  time.Duration `yaml:"accessTtl"`
+	RefreshTTL time.Duration `yaml:"refreshTtl"`
+}
+
+// BookingConfig berisi aturan check-in reservasi dan waitlist.
+type BookingConfig struct {
+	// CheckInOpensBefore adalah seberapa awal check-in dibuka sebelum slot dimulai.
+	CheckInOpensBefore time.Duration `yaml:"checkInOpensBefore"`
+	// NoShowGrace adalah batas toleransi setelah slot dimulai; reservasi yang belum
+	// check-in setelah batas ini ditandai no_show.
+	NoShowGrace time.Duration `yaml:"noShowGrace"`
+	// WaitlistOfferWindow adalah lama slot kosong ditahan untuk antrean waitlist
+	// berikutnya sebelum ditawarkan ke orang setelahnya.
+	WaitlistOfferWindow time.Duration `yaml:"waitlistOfferWindow"`
+	// MaxHoursPerDay adalah jumlah slot yang boleh dipegang seorang mahasiswa
+	// dalam satu hari (zona waktu lokasi), di luar reservasi series.
+	MaxHoursPerDay int `yaml:"maxHoursPerDay"`
+	// MaxAdvance adalah seberapa jauh ke depan sebuah slot boleh dipesan atau
+	// dijadwalkan ulang, dihitung dari waktu mulai slot.
+	MaxAdvance time.Duration `yaml:"maxAdvance"`
+	// MaxConsecutiveHours membatasi berapa jam berturut-turut seorang peminjam
+	// boleh memegang TV yang sama saat memperpanjang sesi. Ini hanya bawaan
+	// untuk lokasi yang batasnya belum diatur staff.
+	MaxConsecutiveHours int `yaml:"maxConsecutiveHours"`
+}
+
+// MetricsConfig mengatur endpoint /metrics.
+type MetricsConfig struct {
+	// Token wajib dikirim scraper sebagai "Authorization: Bearer <token>".
+	// Jika kosong, endpoint /metrics dinonaktifkan.
+	Token string `yaml:"token"`
+}
+
+// TracingConfig mengatur ekspor trace OpenTelemetry. Nama variabel environment
+// mengikuti konvensi OTEL_* agar sama dengan SDK lain.
+type TracingConfig struct {
+	// Exporter bernilai "none" (bawaan, trace tidak dikirim ke mana pun) atau "otlp".
+	Exporter    string  `yaml:"exporter"`
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`
+	ServiceName string  `yaml:"serviceName"`
+	SampleRatio float64 `yaml:"sampleRatio"`
+}
+
+// LogConfig mengatur logger slog aplikasi.
+type LogConfig struct {
+	// Level: debug, info, warn, atau error. Bawaan debug di development dan info di production.
+	Level string `yaml:"level"`
+	// Format: json (bawaan) atau text.
+	Format string `yaml:"format"`
+}
+
+// RateLimitConfig mengatur pembatasan laju request per pengguna atau per IP.
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Store bernilai "memory" (bawaan, per instance) atau "postgres" (dibagi
+	// antar instance).
+	Store string `yaml:"store"`
+
+	// Default berlaku untuk semua rute API, Auth untuk login dan refresh token,
+	// dan Booking untuk pembuatan reservasi (di samping Default).
+	Default RateLimitPolicy `yaml:"default"`
+	Auth    RateLimitPolicy `yaml:"auth"`
+	Booking RateLimitPolicy `yaml:"booking"`
+}
+
+// RateLimitPolicy mengizinkan Requests request per Per, dengan burst sebesar
+// Requests. Di environment variable ditulis sebagai "<requests>/<durasi>", misalnya "10/1m".
+type RateLimitPolicy struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+}
+
+func (p RateLimitPolicy) String() string {
+	return fmt.Sprintf("%d/%s", p.Requests, p.Per)
+}
+
+// MaxPeriod adalah periode terpanjang dari semua kebijakan; bucket yang tidak
+// dipakai selama itu sudah penuh kembali sehingga aman dihapus.
+func (r RateLimitConfig) MaxPeriod() time.Duration {
+	return max(r.Default.Per, r.Auth.Per, r.Booking.Per)
+}
+
+// IsProduction bernilai true jika APP_ENV=production.
+func (c *Config) IsProduction() bool {
+	return c.Env == EnvProduction
+}
+
+// TrustedProxyPrefixes mengubah TrustedProxies menjadi prefix jaringan; alamat
+// tunggal dianggap sebagai prefix /32 atau /128.
+func (s ServerConfig) TrustedProxyPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(s.TrustedProxies))
+	for _, raw := range s.TrustedProxies {
+		if strings.Contains(raw, "/") {
+			prefix, err := netip.ParsePrefix(raw)
+			if err != nil {
+				return nil, fmt.Errorf("contains invalid CIDR %q", raw)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(raw)
+		if err != nil {
+			return nil, fmt.Errorf("contains invalid IP %q", raw)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// DSN membangun Data Source Name Postgres dari konfigurasi database.
+func (d DatabaseConfig) DSN() string {
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=%s",
+		d.Host, d.User, d.Password, d.Name, d.Port, d.SSLMode, d.TimeZone)
+	if d.StatementTimeout > 0 {
+		// Parameter yang tidak dikenal pgx diteruskan sebagai runtime parameter sesi
+		dsn += fmt.Sprintf(" statement_timeout=%d", d.StatementTimeout.Milliseconds())
+	}
+	return dsn
+}
+
+// defaults adalah nilai bawaan untuk field yang tidak wajib diisi.
+func defaults() Config {
+	return Config{
+		Env: EnvDevelopment,
+		Server: ServerConfig{
+			Port:            3000,
+			UploadDir:       "./uploads",
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Port:             5432,
+			SSLMode:          "disable",
+			TimeZone:         "Asia/Jakarta",
+			MaxOpenConns:     25,
+			MaxIdleConns:     5,
+			ConnMaxLifetime:  30 * time.Minute,
+			ConnMaxIdleTime:  5 * time.Minute,
+			StatementTimeout: 30 * time.Second,
+
+			SlowQueryThreshold: 200 * time.Millisecond,
+		},
+		JWT: JWTConfig{
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 7 * 24 * time.Hour,
+		},
+		Booking: BookingConfig{
+			CheckInOpensBefore: 15 * time.Minute,
+			NoShowGrace:        15 * time.Minute,
+
+			WaitlistOfferWindow: 10 * time.Minute,
+			MaxHoursPerDay:      4,
+			MaxAdvance:          7 * 24 * time.Hour,
+			MaxConsecutiveHours: 3,
+		},
+		Log: LogConfig{
+			Format: "json",
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Store:   "memory",
+			Default: RateLimitPolicy{Requests: 300, Per: time.Minute},
+			Auth:    RateLimitPolicy{Requests: 20, Per: time.Minute},
+			Booking: RateLimitPolicy{Requests: 10, Per: time.Minute},
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			ServiceName: "playcorner-api",
+			SampleRatio: 1,
+		},
+	}
+}
+
+// Load memuat konfigurasi dengan urutan prioritas (yang terakhir menang):
+// nilai bawaan, file YAML pada CONFIG_FILE, file .env, lalu environment variable
+// proses. Konfigurasi divalidasi sebelum dikembalikan.
+func Load() (*Config, error) {
+	// godotenv tidak menimpa variabel yang sudah ada, sehingga environment proses tetap menang
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read .env: %w", err)
+	}
+
+	cfg := defaults()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	}
+
+	var problems []string
+	cfg.applyEnv(&problems)
+	if cfg.Log.Level == "" {
+		cfg.Log.Level = "debug"
+		if cfg.IsProduction() {
+			cfg.Log.Level = "info"
+		}
+	}
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, &Error{Problems: problems}
+	}
+	return &cfg, nil
+}
+
+// Error mengumpulkan semua masalah konfigurasi agar dapat diperbaiki sekaligus.
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+func (c *Config) applyEnv(problems *[]string) {
+	str := func(key string, dst *string) {
+		if v, ok := os.LookupEnv(key); ok && v != "" {
+			*dst = v
+		}
+	}
+	num := func(key string, dst *int) {
+		if v, ok := os.LookupEnv(key); ok && v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				*problems = append(*problems, fmt.Sprintf("%s must be an integer, got %q", key, v))
+				return
+			}
+			*dst = n
+		}
+	}
+	dur := func(key string, dst *time.Duration) {
+		if v, ok := os.LookupEnv(key); ok && v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				*problems = append(*problems, fmt.Sprintf("%s must be a duration such as 15m or 168h, got %q", key, v))
+				return
+			}
+			*dst = d
+		}
+	}
+	float := func(key string, dst *float64) {
+		if v, ok := os.LookupEnv(key); ok && v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				*problems = append(*problems, fmt.Sprintf("%s must be a number, got %q", key, v))
+				return
+			}
+			*dst = f
+		}
+	}
+	list := func(key string, dst *[]string) {
+		if v, ok := os.LookupEnv(key); ok && v != "" {
+			*dst = nil
+			for _, item := range strings.Split(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					*dst = append(*dst, item)
+				}
+			}
+		}
+	}
+	policy := func(key string, dst *RateLimitPolicy) {
+		if v, ok := os.LookupEnv(key); ok && v != "" {
+			requests, per, _ := strings.Cut(v, "/")
+			n, err := strconv.Atoi(requests)
+			d, derr := time.ParseDuration(per)
+			if err != nil || derr != nil {
+				*problems = append(*problems, fmt.Sprintf("%s must look like 10/1m, got %q", key, v))
+				return
+			}
+			*dst = RateLimitPolicy{Requests: n, Per: d}
+		}
+	}
+	boolean := func(key string, dst *bool) {
+		if v, ok := os.LookupEnv(key); ok && v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				*problems = append(*problems, fmt.Sprintf("%s must be true or false, got %q", key, v))
+				return
+			}
+			*dst = b
+		}
+	}
+
+	str("APP_ENV", &c.Env)
+
+	num("SERVER_PORT", &c.Server.Port)
+	str("CORS_ALLOWED_ORIGINS", &c.Server.CORSAllowedOrigins)
+	str("UPLOAD_DIR", &c.Server.UploadDir)
+	dur("SERVER_READ_TIMEOUT", &c.Server.ReadTimeout)
+	dur("SERVER_WRITE_TIMEOUT", &c.Server.WriteTimeout)
+	dur("SERVER_IDLE_TIMEOUT", &c.Server.IdleTimeout)
+	dur("SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+	dur("SHUTDOWN_DRAIN_DELAY", &c.Server.ShutdownDrainDelay)
+	list("TRUSTED_PROXIES", &c.Server.TrustedProxies)
+
+	str("DB_HOST", &c.Database.Host)
+	num("DB_PORT", &c.Database.Port)
+	str("DB_USER", &c.Database.User)
+	str("DB_PASSWORD", &c.Database.Password)
+	str("DB_NAME", &c.Database.Name)
+	str("DB_SSLMODE", &c.Database.SSLMode)
+	str("DB_TIMEZONE", &c.Database.TimeZone)
+	num("DB_MAX_OPEN_CONNS", &c.Database.MaxOpenConns)
+	num("DB_MAX_IDLE_CONNS", &c.Database.MaxIdleConns)
+	dur("DB_CONN_MAX_LIFETIME", &c.Database.ConnMaxLifetime)
+	dur("DB_CONN_MAX_IDLE_TIME", &c.Database.ConnMaxIdleTime)
+	dur("DB_STATEMENT_TIMEOUT", &c.Database.StatementTimeout)
+	dur("DB_SLOW_QUERY_THRESHOLD", &c.Database.SlowQueryThreshold)
+
+	str("JWT_SECRET", &c.JWT.Secret)
+	dur("JWT_ACCESS_TTL", &c.JWT.AccessTTL)
+	dur("JWT_REFRESH_TTL", &c.JWT.RefreshTTL)
+
+	dur("BOOKING_CHECK_IN_OPENS_BEFORE", &c.Booking.CheckInOpensBefore)
+	dur("BOOKING_NO_SHOW_GRACE", &c.Booking.NoShowGrace)
+	dur("BOOKING_WAITLIST_OFFER_WINDOW", &c.Booking.WaitlistOfferWindow)
+	num("BOOKING_MAX_HOURS_PER_DAY", &c.Booking.MaxHoursPerDay)
+	dur("BOOKING_MAX_ADVANCE", &c.Booking.MaxAdvance)
+	num("BOOKING_MAX_CONSECUTIVE_HOURS", &c.Booking.MaxConsecutiveHours)
+
+	str("METRICS_TOKEN", &c.Metrics.Token)
+
+	boolean("RATE_LIMIT_ENABLED", &c.RateLimit.Enabled)
+	str("RATE_LIMIT_STORE", &c.RateLimit.Store)
+	policy("RATE_LIMIT_DEFAULT", &c.RateLimit.Default)
+	policy("RATE_LIMIT_AUTH", &c.RateLimit.Auth)
+	policy("RATE_LIMIT_BOOKING", &c.RateLimit.Booking)
+
+	str("LOG_LEVEL", &c.Log.Level)
+	str("LOG_FORMAT", &c.Log.Format)
+
+	str("OTEL_TRACES_EXPORTER", &c.Tracing.Exporter)
+	str("OTEL_EXPORTER_OTLP_ENDPOINT", &c.Tracing.Endpoint)
+	boolean("OTEL_EXPORTER_OTLP_INSECURE", &c.Tracing.Insecure)
+	str("OTEL_SERVICE_NAME", &c.Tracing.ServiceName)
+	float("OTEL_TRACES_SAMPLER_ARG", &c.Tracing.SampleRatio)
+}
+
+func (c *Config) validate() []string {
+	var problems []string
+	required := func(key, value string) {
+		if strings.TrimSpace(value) == "" {
+			problems = append(problems, key+" is required")
+		}
+	}
+
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		problems = append(problems, fmt.Sprintf("APP_ENV must be %q or %q, got %q", EnvDevelopment, EnvProduction, c.Env))
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("SERVER_PORT must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	required("UPLOAD_DIR", c.Server.UploadDir)
+	if c.Server.ShutdownTimeout <= 0 {
+		problems = append(problems, "SHUTDOWN_TIMEOUT must be positive")
+	}
+	if c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		problems = append(problems, "SERVER_*_TIMEOUT values must not be negative")
+	}
+	if c.Server.ShutdownDrainDelay < 0 {
+		problems = append(problems, "SHUTDOWN_DRAIN_DELAY must not be negative")
+	}
+	if _, err := c.Server.TrustedProxyPrefixes(); err != nil {
+		problems = append(problems, "TRUSTED_PROXIES "+err.Error())
+	}
+	if c.IsProduction() && strings.TrimSpace(c.Server.CORSAllowedOrigins) == "*" {
+		problems = append(problems, "CORS_ALLOWED_ORIGINS must list explicit origins in production")
+	}
+
+	required("DB_HOST", c.Database.Host)
+	required("DB_USER", c.Database.User)
+	required("DB_NAME", c.Database.Name)
+	if c.Database.Port < 1 || c.Database.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("DB_PORT must be between 1 and 65535, got %d", c.Database.Port))
+	}
+	switch c.Database.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		problems = append(problems, fmt.Sprintf("DB_SSLMODE %q is not a valid Postgres sslmode", c.Database.SSLMode))
+	}
+	if c.Database.MaxOpenConns < 1 {
+		problems = append(problems, "DB_MAX_OPEN_CONNS must be at least 1")
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		problems = append(problems, "DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS")
+	}
+	if c.Database.ConnMaxLifetime < 0 || c.Database.ConnMaxIdleTime < 0 || c.Database.StatementTimeout < 0 {
+		problems = append(problems, "DB_CONN_MAX_LIFETIME, DB_CONN_MAX_IDLE_TIME and DB_STATEMENT_TIMEOUT must not be negative")
+	}
+	if _, err := time.LoadLocation(c.Database.TimeZone); err != nil {
+		problems = append(problems, fmt.Sprintf("DB_TIMEZONE %q is not a valid time zone", c.Database.TimeZone))
+	}
+
+	required("JWT_SECRET", c.JWT.Secret)
+	if c.IsProduction() && c.JWT.Secret != "" && len(c.JWT.Secret) < 32 {
+		problems = append(problems, "JWT_SECRET must be at least 32 characters in production")
+	}
+	if c.JWT.AccessTTL <= 0 {
+		problems = append(problems, "JWT_ACCESS_TTL must be positive")
+	}
+	if c.JWT.RefreshTTL < c.JWT.AccessTTL {
+		problems = append(problems, "JWT_REFRESH_TTL must not be shorter than JWT_ACCESS_TTL")
+	}
+
+	if c.Booking.CheckInOpensBefore < 0 {
+		problems = append(problems, "BOOKING_CHECK_IN_OPENS_BEFORE must not be negative")
+	}
+	if c.Booking.NoShowGrace <= 0 || c.Booking.NoShowGrace >= time.Hour {
+		problems = append(problems, "BOOKING_NO_SHOW_GRACE must be positive and shorter than one slot (1h)")
+	}
+	if c.Booking.WaitlistOfferWindow <= 0 {
+		problems = append(problems, "BOOKING_WAITLIST_OFFER_WINDOW must be positive")
+	}
+	if c.Booking.MaxHoursPerDay <= 0 {
+		problems = append(problems, "BOOKING_MAX_HOURS_PER_DAY must be positive")
+	}
+	if c.Booking.MaxAdvance <= 0 {
+		problems = append(problems, "BOOKING_MAX_ADVANCE must be positive")
+	}
+	if c.Booking.MaxConsecutiveHours <= 0 {
+		problems = append(problems, "BOOKING_MAX_CONSECUTIVE_HOURS must be positive")
+	}
+
+	if c.RateLimit.Enabled {
+		if c.RateLimit.Store != "memory" && c.RateLimit.Store != "postgres" {
+			problems = append(problems, fmt.Sprintf("RATE_LIMIT_STORE must be \"memory\" or \"postgres\", got %q", c.RateLimit.Store))
+		}
+		checkPolicy := func(key string, p RateLimitPolicy) {
+			if p.Requests < 1 || p.Per <= 0 {
+				problems = append(problems, fmt.Sprintf("%s must allow at least 1 request per positive duration, got %s", key, p))
+			}
+		}
+		checkPolicy("RATE_LIMIT_DEFAULT", c.RateLimit.Default)
+		checkPolicy("RATE_LIMIT_AUTH", c.RateLimit.Auth)
+		checkPolicy("RATE_LIMIT_BOOKING", c.RateLimit.Booking)
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		problems = append(problems, fmt.Sprintf("LOG_LEVEL must be debug, info, warn or error, got %q", c.Log.Level))
+	}
+	// Level debug mencatat setiap query SQL, terlalu boros dan terlalu rinci untuk production
+	if c.IsProduction() && strings.EqualFold(c.Log.Level, "debug") {
+		problems = append(problems, "LOG_LEVEL must not be debug in production")
+	}
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		problems = append(problems, fmt.Sprintf("LOG_FORMAT must be \"json\" or \"text\", got %q", c.Log.Format))
+	}
+
+	switch c.Tracing.Exporter {
+	case "none":
+	case "otlp":
+		required("OTEL_EXPORTER_OTLP_ENDPOINT", c.Tracing.Endpoint)
+	default:
+		problems = append(problems, fmt.Sprintf("OTEL_TRACES_EXPORTER must be \"none\" or \"otlp\", got %q", c.Tracing.Exporter))
+	}
+	required("OTEL_SERVICE_NAME", c.Tracing.ServiceName)
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		problems = append(problems, "OTEL_TRACES_SAMPLER_ARG must be between 0 and 1")
+	}
+
+	return problems
+}
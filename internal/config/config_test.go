@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validEnv adalah environment minimal yang lolos validasi.
+var validEnv = map[string]string{
+	"DB_HOST":    "localhost",
+	"DB_USER":    "playcorner",
+	"DB_NAME":    "playcorner",
+	"JWT_SECRET": "a-development-secret",
+}
+
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	for key, value := range validEnv {
+		t.Setenv(key, value)
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		// wantProblems adalah potongan pesan yang harus muncul; kosong berarti valid.
+		wantProblems []string
+	}{
+		{name: "accepts the minimal environment"},
+		{
+			name:         "reports every missing required value at once",
+			env:          map[string]string{"DB_HOST": "", "DB_USER": " ", "JWT_SECRET": ""},
+			wantProblems: []string{"DB_HOST is required", "DB_USER is required", "JWT_SECRET is required"},
+		},
+		{
+			name:         "rejects a malformed number",
+			env:          map[string]string{"SERVER_PORT": "http"},
+			wantProblems: []string{"SERVER_PORT must be an integer"},
+		},
+		{
+			name:         "rejects a port out of range",
+			env:          map[string]string{"DB_PORT": "70000"},
+			wantProblems: []string{"DB_PORT must be between 1 and 65535"},
+		},
+		{
+			name:         "rejects a malformed duration",
+			env:          map[string]string{"JWT_ACCESS_TTL": "15"},
+			wantProblems: []string{"JWT_ACCESS_TTL must be a duration"},
+		},
+		{
+			name:         "rejects a refresh TTL shorter than the access TTL",
+			env:          map[string]string{"JWT_ACCESS_TTL": "2h", "JWT_REFRESH_TTL": "1h"},
+			wantProblems: []string{"JWT_REFRESH_TTL must not be shorter than JWT_ACCESS_TTL"},
+		},
+		{
+			name:         "rejects an unknown environment",
+			env:          map[string]string{"APP_ENV": "staging"},
+			wantProblems: []string{"APP_ENV must be"},
+		},
+		{
+			name:         "rejects an invalid sslmode and time zone",
+			env:          map[string]string{"DB_SSLMODE": "on", "DB_TIMEZONE": "Mars/Olympus"},
+			wantProblems: []string{"DB_SSLMODE \"on\"", "DB_TIMEZONE \"Mars/Olympus\""},
+		},
+		{
+			name: "requires production hardening",
+			env: map[string]string{
+				"APP_ENV":              EnvProduction,
+				"JWT_SECRET":           "short",
+				"CORS_ALLOWED_ORIGINS": "*",
+			},
+			wantProblems: []string{
+				"JWT_SECRET must be at least 32 characters in production",
+				"CORS_ALLOWED_ORIGINS must list explicit origins in production",
+			},
+		},
+		{
+			name: "rejects debug logging in production",
+			env: map[string]string{
+				"APP_ENV":              EnvProduction,
+				"JWT_SECRET":           strings.Repeat("s", 32),
+				"CORS_ALLOWED_ORIGINS": "https://playcorner.example",
+				"LOG_LEVEL":            "DEBUG",
+			},
+			wantProblems: []string{"LOG_LEVEL must not be debug in production"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+
+			cfg, err := Load()
+			if len(tt.wantProblems) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if cfg.Server.Port != 3000 || cfg.JWT.AccessTTL != 15*time.Minute {
+					t.Fatalf("defaults not applied: %+v", cfg)
+				}
+				return
+			}
+
+			var cfgErr *Error
+			if !errors.As(err, &cfgErr) {
+				t.Fatalf("error = %v, want *config.Error", err)
+			}
+			if len(cfgErr.Problems) != len(tt.wantProblems) {
+				t.Fatalf("problems = %q, want %d", cfgErr.Problems, len(tt.wantProblems))
+			}
+			for _, want := range tt.wantProblems {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := "server:\n  port: 4000\n  uploadDir: /srv/uploads\njwt:\n  accessTtl: 30m\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	setEnv(t, map[string]string{"SERVER_PORT": "5000"})
+	t.Setenv("CONFIG_FILE", path)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Environment variable menang atas file YAML, dan YAML menang atas nilai bawaan
+	if cfg.Server.Port != 5000 {
+		t.Fatalf("port = %d, want 5000 from the environment", cfg.Server.Port)
+	}
+	if cfg.Server.UploadDir != "/srv/uploads" || cfg.JWT.AccessTTL != 30*time.Minute {
+		t.Fatalf("YAML values not applied: %+v", cfg)
+	}
+	if cfg.Database.Port != 5432 {
+		t.Fatalf("DB port = %d, want the default 5432", cfg.Database.Port)
+	}
+}
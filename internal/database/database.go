@@ -2,60 +2,99 @@
 package database
 
 import (
-	"fmt"
-	"log"
-	"os"
-	"playcorner-be/internal/models"
+	"context"
+	"log/slog"
+	"playcorner-be/internal/config"
+	"playcorner-be/internal/logging"
 
-	"github.com/joho/godotenv"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	"gorm.io/plugin/opentelemetry/tracing"
 )
 
-// DB adalah variabel global yang akan menampung instance koneksi GORM
-// dan akan diakses oleh package lain (seperti handlers).
-var DB *gorm.DB
+// Open membuka koneksi ke database tanpa menyentuh skema.
+// Dipakai langsung oleh subcommand `migrate`.
+func Open(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	// Membuka koneksi ke database menggunakan GORM.
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{
+		// Query dicatat lewat slog tanpa nilai parameter; hanya query lambat dan
+		// gagal yang muncul di atas level debug.
+		Logger: logging.NewGormLogger(cfg.SlowQueryThreshold),
+	})
+	if err != nil {
+		return nil, err
+	}
 
-// ConnectDB adalah satu-satunya fungsi yang perlu dipanggil dari main.go
-// untuk menginisialisasi koneksi dan skema database.
-func ConnectDB() {
-	// Memuat variabel environment dari file .env.
-	// Ini adalah praktik yang baik untuk menjaga kerahasiaan kredensial.
-	err := godotenv.Load()
+	// Mengatur pool koneksi sesuai konfigurasi
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Println("Warning: .env file not found, using environment variables from system")
+		return nil, err
 	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	// Membangun DSN (Data Source Name) dari environment variables.
-	// Pastikan file .env Anda menggunakan nama variabel ini.
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Jakarta",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
-	)
+	return db, nil
+}
 
-	// Membuka koneksi ke database menggunakan GORM.
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info), // Menampilkan query SQL di log untuk debugging.
-	})
+// Close menutup pool koneksi yang dibuka oleh Open atau ConnectDB.
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
 
+// ConnectDB adalah satu-satunya fungsi yang perlu dipanggil dari main.go
+// untuk menginisialisasi koneksi dan skema database. Koneksi yang dikembalikan
+// diteruskan ke repository, bukan disimpan di variabel global.
+func ConnectDB(cfg config.DatabaseConfig) *gorm.DB {
+	db, err := Open(cfg)
 	if err != nil {
-		log.Fatal("Failed to connect to database. \n", err)
+		logging.Fatal("Failed to connect to database", err)
 	}
-	log.Println("Database connection established")
+	slog.Info("Database connection established")
 
-	// Menjalankan AutoMigrate untuk membuat/memperbarui tabel database
-	// secara otomatis sesuai dengan struct yang didefinisikan di package models.
-	log.Println("Running Migrations")
-	err = db.AutoMigrate(&models.User{}, &models.TVInfo{}, &models.Game{}, &models.Reservation{})
+	// Menerapkan migrasi berversi yang belum dijalankan. Aplikasi menolak
+	// berjalan jika skema database lebih baru daripada yang dikenalnya.
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		logging.Fatal("Failed to load migrations", err)
+	}
+	current, err := migrator.CheckVersion()
 	if err != nil {
-		log.Fatal("Migration failed. \n", err)
+		logging.Fatal("Refusing to start", err)
 	}
-	log.Println("Migrations completed")
 
-	// Menetapkan instance database yang berhasil terhubung ke variabel global DB.
-	DB = db
+	slog.Info("Running migrations", "schema_version", current, "latest", migrator.Latest())
+	if err := migrator.Up(); err != nil {
+		logging.Fatal("Migration failed", err)
+	}
+	slog.Info("Migrations completed")
+
+	return db
+}
+
+// Ping memastikan database dapat dijangkau, dipakai oleh pemeriksaan readiness.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Instrument memasang plugin OpenTelemetry GORM sehingga setiap query menjadi
+// child span dari request yang sedang berjalan. Nilai parameter query tidak
+// dicatat agar data sensitif tidak ikut terkirim ke collector.
+func Instrument(db *gorm.DB, tp trace.TracerProvider, dbName string) error {
+	return db.Use(tracing.NewPlugin(
+		tracing.WithTracerProvider(tp),
+		tracing.WithDBName(dbName),
+		tracing.WithoutQueryVariables(),
+		tracing.WithoutMetrics(),
+	))
 }
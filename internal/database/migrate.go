@@ -0,0 +1,301 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationFiles berisi semua file migrasi SQL yang ikut ter-embed di dalam binary.
+// Format nama file: <versi>_<nama>.up.sql dan <versi>_<nama>.down.sql.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID adalah kunci advisory lock Postgres agar dua instance
+// tidak menjalankan migrasi secara bersamaan.
+const migrationLockID = 20250601
+
+// Migration adalah satu langkah perubahan skema beserta kebalikannya.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration adalah baris pada tabel schema_migrations.
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// MigrationStatus menggambarkan satu migrasi yang dikenal binary dan apakah sudah diterapkan.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrator menjalankan migrasi berversi terhadap database.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator memuat migrasi yang ter-embed dan memastikan tabel schema_migrations tersedia.
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    bigint PRIMARY KEY,
+		name       text NOT NULL,
+		applied_at timestamptz NOT NULL
+	)`).Error; err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		versionStr, label, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", name)
+		}
+
+		content, err := fs.ReadFile(fsys, "migrations/"+name)
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		} else if m.Name != label {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, label)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Latest mengembalikan versi migrasi tertinggi yang dikenal oleh binary ini.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// CurrentVersion mengembalikan versi skema yang sedang terpasang di database (0 jika kosong).
+func (m *Migrator) CurrentVersion() (int, error) {
+	var version int
+	err := m.db.Raw("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version).Error
+	return version, err
+}
+
+// CheckLatest mengembalikan error jika skema database belum berada di versi
+// migrasi terbaru yang dikenal binary ini. Dipakai oleh pemeriksaan readiness.
+func (m *Migrator) CheckLatest(ctx context.Context) error {
+	var version int
+	if err := m.db.WithContext(ctx).Raw("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version).Error; err != nil {
+		return err
+	}
+	if version != m.Latest() {
+		return fmt.Errorf("schema version %d, expected %d", version, m.Latest())
+	}
+	return nil
+}
+
+// Status mengembalikan daftar migrasi yang dikenal beserta waktu penerapannya.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	var applied []SchemaMigration
+	if err := m.db.Order("version").Find(&applied).Error; err != nil {
+		return nil, err
+	}
+
+	appliedAt := map[int]time.Time{}
+	for _, a := range applied {
+		appliedAt[a.Version] = a.AppliedAt
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if at, ok := appliedAt[mig.Version]; ok {
+			s.AppliedAt = &at
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// Up menerapkan semua migrasi yang belum diterapkan.
+func (m *Migrator) Up() error {
+	return m.To(m.Latest())
+}
+
+// Down membatalkan satu migrasi terakhir.
+func (m *Migrator) Down() error {
+	current, err := m.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	if current == 0 {
+		return nil
+	}
+
+	target := 0
+	for _, mig := range m.migrations {
+		if mig.Version < current {
+			target = mig.Version
+		}
+	}
+	return m.To(target)
+}
+
+// To memindahkan skema ke versi tertentu, naik atau turun sesuai kebutuhan.
+func (m *Migrator) To(target int) error {
+	if target != 0 && m.find(target) == nil {
+		return fmt.Errorf("unknown migration version %d", target)
+	}
+
+	current, err := m.CheckVersion()
+	if err != nil {
+		return err
+	}
+
+	if target >= current {
+		for _, mig := range m.migrations {
+			if mig.Version > current && mig.Version <= target {
+				if err := m.apply(mig, true); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mig := m.migrations[i]
+		if mig.Version <= current && mig.Version > target {
+			if err := m.apply(mig, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CheckVersion mengembalikan versi skema saat ini dan menolak database yang
+// skemanya lebih baru daripada migrasi yang dikenal binary ini.
+func (m *Migrator) CheckVersion() (int, error) {
+	current, err := m.CurrentVersion()
+	if err != nil {
+		return 0, err
+	}
+	if current > m.Latest() {
+		return current, fmt.Errorf("database schema version %d is newer than the latest known migration %d", current, m.Latest())
+	}
+	return current, nil
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// apply menjalankan satu migrasi di dalam transaksi, sekaligus mencatat atau
+// menghapus barisnya di schema_migrations.
+func (m *Migrator) apply(mig Migration, up bool) error {
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+			return err
+		}
+
+		// Periksa ulang setelah mendapat lock: instance lain mungkin sudah menjalankannya
+		var count int64
+		if err := tx.Model(&SchemaMigration{}).Where("version = ?", mig.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if (count > 0) == up {
+			return nil
+		}
+
+		if up {
+			if err := tx.Exec(mig.Up).Error; err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now()}).Error
+		}
+
+		if strings.TrimSpace(mig.Down) != "" {
+			if err := tx.Exec(mig.Down).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&SchemaMigration{}, mig.Version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("migration %d_%s (%s) failed: %w", mig.Version, mig.Name, direction, err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package database
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Model pada versi awal aplikasi, saat skema masih dibuat oleh GORM AutoMigrate.
+// Disalin apa adanya agar tes tidak ikut berubah ketika package models berubah.
+type (
+	baselineUser struct {
+		ID             string `gorm:"primaryKey"`
+		Name           string
+		Faculty        string
+		Major          string
+		CreditScore    int
+		ProfilePictURL string
+		PasswordHash   string
+		Reservations   []baselineReservation `gorm:"foreignKey:BorrowerID"`
+	}
+	baselineTVInfo struct {
+		ID           int `gorm:"primaryKey;autoIncrement"`
+		ConsoleType  string
+		Games        []*baselineGame       `gorm:"many2many:tv_info_games;joinForeignKey:TVInfoID;joinReferences:GameID"`
+		Reservations []baselineReservation `gorm:"foreignKey:TVID"`
+	}
+	baselineGame struct {
+		ID           int `gorm:"primaryKey;autoIncrement"`
+		Title        string
+		CoverPictURL string
+	}
+	baselineReservation struct {
+		gorm.Model
+		TVID       int
+		BorrowerID string
+		TimeSlot   string
+	}
+)
+
+func (baselineUser) TableName() string        { return "users" }
+func (baselineTVInfo) TableName() string      { return "tv_infos" }
+func (baselineGame) TableName() string        { return "games" }
+func (baselineReservation) TableName() string { return "reservations" }
+
+// testDB membuka database Postgres dari TEST_DATABASE_URL di schema baru yang
+// dihapus setelah tes selesai. Tes dilewati jika variabel tersebut kosong.
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := fmt.Sprintf("migrate_test_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		_ = Close(admin)
+	})
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	db, err := gorm.Open(postgres.Open(u.String()), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Close(db) })
+	return db
+}
+
+func TestMigrateAdoptsBaselineAutoMigrateSchema(t *testing.T) {
+	db := testDB(t)
+
+	// Database yang dibuat oleh versi awal, lengkap dengan data lama
+	if err := db.AutoMigrate(&baselineUser{}, &baselineTVInfo{}, &baselineGame{}, &baselineReservation{}); err != nil {
+		t.Fatal(err)
+	}
+	user := baselineUser{ID: "225150200111001", Name: "Budi"}
+	tv := baselineTVInfo{ConsoleType: "PS5"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&tv).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&baselineReservation{TVID: tv.ID, BorrowerID: user.ID, TimeSlot: "2025-06-02T10:00:00+07:00"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("migrate baseline schema: %v", err)
+	}
+	version, err := migrator.CurrentVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != migrator.Latest() {
+		t.Fatalf("schema version = %d, want %d", version, migrator.Latest())
+	}
+
+	// Kolom yang belum ada di skema awal diisi dengan nilai bawaannya
+	var role string
+	if err := db.Raw("SELECT role FROM users WHERE id = ?", user.ID).Scan(&role).Error; err != nil {
+		t.Fatal(err)
+	}
+	if role != "student" {
+		t.Fatalf("user role = %q, want student", role)
+	}
+	var tvRow struct {
+		LocationID   *int
+		OutOfService bool
+	}
+	if err := db.Raw("SELECT location_id, out_of_service FROM tv_infos WHERE id = ?", tv.ID).Scan(&tvRow).Error; err != nil {
+		t.Fatal(err)
+	}
+	if tvRow.LocationID == nil || tvRow.OutOfService {
+		t.Fatalf("TV = %+v, want a location and in service", tvRow)
+	}
+	var status string
+	if err := db.Raw("SELECT status FROM reservations WHERE borrower_id = ?", user.ID).Scan(&status).Error; err != nil {
+		t.Fatal(err)
+	}
+	if status != "booked" {
+		t.Fatalf("reservation status = %q, want booked", status)
+	}
+
+	// Menjalankan ulang tidak mengubah apa pun
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("second migrate: %v", err)
+	}
+}
+
+func TestMigrateUpDownFreshDatabase(t *testing.T) {
+	db := testDB(t)
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	// Setiap migrasi dapat dibatalkan sampai skema kosong, lalu diterapkan lagi
+	if err := migrator.To(0); err != nil {
+		t.Fatalf("migrate down to 0: %v", err)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("migrate up again: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		wantVersion []int
+		wantErr     string
+	}{
+		{
+			name: "pairs up and down scripts in version order",
+			files: map[string]string{
+				"migrations/0002_add_index.up.sql":   "CREATE INDEX",
+				"migrations/0002_add_index.down.sql": "DROP INDEX",
+				"migrations/0001_init.up.sql":        "CREATE TABLE",
+				"migrations/0010_later.up.sql":       "ALTER TABLE",
+				"migrations/README.md":               "ignored",
+			},
+			wantVersion: []int{1, 2, 10},
+		},
+		{
+			name:    "rejects a missing up script",
+			files:   map[string]string{"migrations/0001_init.down.sql": "DROP TABLE"},
+			wantErr: "has no up script",
+		},
+		{
+			name: "rejects conflicting names for one version",
+			files: map[string]string{
+				"migrations/0001_init.up.sql":  "CREATE TABLE",
+				"migrations/0001_other.up.sql": "CREATE TABLE",
+			},
+			wantErr: "conflicting names",
+		},
+		{
+			name:    "rejects a file name without a label",
+			files:   map[string]string{"migrations/0001.up.sql": "CREATE TABLE"},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name:    "rejects a non-numeric version",
+			files:   map[string]string{"migrations/abc_init.up.sql": "CREATE TABLE"},
+			wantErr: "invalid migration version",
+		},
+		{
+			name:    "rejects version zero",
+			files:   map[string]string{"migrations/0000_init.up.sql": "CREATE TABLE"},
+			wantErr: "invalid migration version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, content := range tt.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(content)}
+			}
+
+			migrations, err := loadMigrations(fsys)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(migrations) != len(tt.wantVersion) {
+				t.Fatalf("got %d migrations, want %d", len(migrations), len(tt.wantVersion))
+			}
+			for i, m := range migrations {
+				if m.Version != tt.wantVersion[i] {
+					t.Fatalf("migration %d has version %d, want %d", i, m.Version, tt.wantVersion[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Versi harus berurutan tanpa celah dan setiap migrasi dapat dibatalkan
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Fatalf("migration %d_%s breaks the sequence, want version %d", m.Version, m.Name, i+1)
+		}
+		if strings.TrimSpace(m.Down) == "" {
+			t.Fatalf("migration %d_%s has no down script", m.Version, m.Name)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS tv_issues;
+DROP TABLE IF EXISTS reservations;
+DROP TABLE IF EXISTS tv_info_games;
+DROP TABLE IF EXISTS games;
+DROP TABLE IF EXISTS tv_infos;
+DROP TABLE IF EXISTS staff_assignments;
+DROP TABLE IF EXISTS locations;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,95 @@
+-- Skema awal PlayCorner. Memakai IF NOT EXISTS agar database yang sebelumnya
+-- dibuat oleh GORM AutoMigrate dapat diadopsi. Database dari versi AutoMigrate
+-- paling awal belum memiliki sebagian kolom, sehingga kolom tersebut ditambahkan
+-- dengan nilai bawaan yang sama seperti pada CREATE TABLE.
+
+CREATE TABLE IF NOT EXISTS users (
+    id               text PRIMARY KEY,
+    name             text,
+    faculty          text,
+    major            text,
+    credit_score     bigint,
+    profile_pict_url text,
+    role             text DEFAULT 'student',
+    password_hash    text
+);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role text DEFAULT 'student';
+
+CREATE TABLE IF NOT EXISTS locations (
+    id         bigserial PRIMARY KEY,
+    name       text,
+    building   text,
+    timezone   text DEFAULT 'Asia/Jakarta',
+    open_hour  bigint DEFAULT 9,
+    close_hour bigint DEFAULT 18
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_locations_name ON locations (name);
+
+CREATE TABLE IF NOT EXISTS staff_assignments (
+    user_id     text,
+    location_id bigint,
+    PRIMARY KEY (user_id, location_id)
+);
+
+CREATE TABLE IF NOT EXISTS tv_infos (
+    id                    bigserial PRIMARY KEY,
+    console_type          text,
+    location_id           bigint REFERENCES locations (id),
+    out_of_service        boolean DEFAULT false,
+    out_of_service_reason text
+);
+ALTER TABLE tv_infos ADD COLUMN IF NOT EXISTS location_id bigint REFERENCES locations (id);
+ALTER TABLE tv_infos ADD COLUMN IF NOT EXISTS out_of_service boolean DEFAULT false;
+ALTER TABLE tv_infos ADD COLUMN IF NOT EXISTS out_of_service_reason text;
+CREATE INDEX IF NOT EXISTS idx_tv_infos_location_id ON tv_infos (location_id);
+
+CREATE TABLE IF NOT EXISTS games (
+    id             bigserial PRIMARY KEY,
+    title          text,
+    cover_pict_url text
+);
+
+CREATE TABLE IF NOT EXISTS tv_info_games (
+    tv_info_id bigint REFERENCES tv_infos (id),
+    game_id    bigint REFERENCES games (id),
+    PRIMARY KEY (tv_info_id, game_id)
+);
+
+CREATE TABLE IF NOT EXISTS reservations (
+    id          bigserial PRIMARY KEY,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    tv_id       bigint REFERENCES tv_infos (id),
+    borrower_id text REFERENCES users (id),
+    time_slot   text,
+    status      text DEFAULT 'booked'
+);
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS status text DEFAULT 'booked';
+CREATE INDEX IF NOT EXISTS idx_reservations_deleted_at ON reservations (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_reservations_status ON reservations (status);
+
+CREATE TABLE IF NOT EXISTS tv_issues (
+    id          bigserial PRIMARY KEY,
+    tv_id       bigint,
+    game_id     bigint,
+    reporter_id text,
+    description text,
+    photo_url   text,
+    status      text DEFAULT 'open',
+    staff_note  text,
+    resolved_at timestamptz,
+    created_at  timestamptz,
+    updated_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_tv_issues_tv_id ON tv_issues (tv_id);
+CREATE INDEX IF NOT EXISTS idx_tv_issues_status ON tv_issues (status);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id         bigserial PRIMARY KEY,
+    user_id    text,
+    message    text,
+    read_at    timestamptz,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id);
@@ -0,0 +1 @@
+ALTER TABLE tv_infos ALTER COLUMN location_id DROP NOT NULL;
@@ -0,0 +1,13 @@
+-- Setiap TV wajib berada di sebuah lokasi. TV lama yang belum memiliki lokasi
+-- dipindahkan ke lokasi pertama (dibuat sebagai FILKOM jika belum ada).
+
+INSERT INTO locations (name, building, timezone, open_hour, close_hour)
+SELECT 'FILKOM', 'Gedung F FILKOM', 'Asia/Jakarta', 9, 18
+WHERE NOT EXISTS (SELECT 1 FROM locations)
+  AND EXISTS (SELECT 1 FROM tv_infos WHERE location_id IS NULL);
+
+UPDATE tv_infos
+SET location_id = (SELECT min(id) FROM locations)
+WHERE location_id IS NULL;
+
+ALTER TABLE tv_infos ALTER COLUMN location_id SET NOT NULL;
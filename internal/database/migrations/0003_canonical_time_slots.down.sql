@@ -0,0 +1,2 @@
+-- Normalisasi time_slot tidak dapat dibalik; hanya indeks yang dihapus.
+DROP INDEX IF EXISTS idx_reservations_tv_slot;
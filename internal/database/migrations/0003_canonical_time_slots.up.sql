@@ -0,0 +1,10 @@
+-- Sebelum ada lokasi, time_slot disimpan persis seperti yang dikirim klien.
+-- Seragamkan semua nilai RFC3339 menjadi format kanonik UTC ("...Z") agar
+-- perbandingan string pada query ketersediaan tetap benar.
+
+UPDATE reservations
+SET time_slot = to_char(time_slot::timestamptz AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"')
+WHERE time_slot ~ '^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$'
+  AND time_slot !~ '^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$';
+
+CREATE INDEX IF NOT EXISTS idx_reservations_tv_slot ON reservations (tv_id, time_slot);
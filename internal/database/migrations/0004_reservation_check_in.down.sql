@@ -0,0 +1,3 @@
+UPDATE reservations SET status = 'booked' WHERE status = 'checked_in';
+
+ALTER TABLE reservations DROP COLUMN IF EXISTS checked_in_at;
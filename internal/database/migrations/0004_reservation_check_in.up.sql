@@ -0,0 +1,10 @@
+-- Waktu check-in oleh staff. Reservasi booked yang tidak di-check-in sampai
+-- batas toleransi ditandai no_show oleh job latar belakang.
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS checked_in_at timestamptz;
+
+-- Reservasi yang slotnya sudah berakhir dibuat sebelum ada check-in, jadi
+-- dianggap selesai agar tidak ikut ditandai no_show.
+UPDATE reservations
+SET status = 'completed'
+WHERE status = 'booked'
+  AND time_slot <= to_char((now() - interval '1 hour') AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"');
@@ -0,0 +1,14 @@
+-- Idempotency-Key per pengguna untuk request yang tidak boleh dijalankan dua
+-- kali, misalnya pembuatan reservasi. status_code 0 berarti request pertama
+-- masih diproses; setelah selesai status dan body responsnya disimpan untuk
+-- diputar ulang. Baris yang lebih tua dari 24 jam dihapus oleh job latar belakang.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id       text        NOT NULL,
+    key           text        NOT NULL,
+    request_hash  text        NOT NULL,
+    status_code   integer     NOT NULL DEFAULT 0,
+    response_body bytea,
+    created_at    timestamptz NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys (created_at);
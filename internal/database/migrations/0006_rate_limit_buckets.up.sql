@@ -0,0 +1,11 @@
+-- Token bucket per kunci rate limit (nama kebijakan + pengguna atau IP) untuk
+-- RATE_LIMIT_STORE=postgres, sehingga batasnya berlaku di semua instance.
+-- allowed menyimpan hasil pengambilan token terakhir agar isi ulang, pengambilan
+-- token, dan hasilnya cukup dilakukan dalam satu statement.
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key        text             PRIMARY KEY,
+    tokens     double precision NOT NULL,
+    allowed    boolean          NOT NULL,
+    updated_at timestamptz      NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_updated_at ON rate_limit_buckets (updated_at);
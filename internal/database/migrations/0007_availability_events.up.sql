@@ -0,0 +1,15 @@
+-- Log perubahan ketersediaan slot untuk stream SSE. Setiap baris juga dikirim
+-- lewat NOTIFY availability_events saat transaksinya di-commit, sehingga semua
+-- instance API dapat meneruskannya ke kliennya. Klien yang tersambung kembali
+-- memutar ulang baris dengan id > Last-Event-ID. Baris lama dihapus oleh job
+-- latar belakang.
+CREATE TABLE IF NOT EXISTS availability_events (
+    id          bigserial   PRIMARY KEY,
+    type        text        NOT NULL,
+    location_id integer     NOT NULL,
+    tv_id       integer     NOT NULL,
+    time_slot   text        NOT NULL DEFAULT '',
+    created_at  timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_availability_events_location_id ON availability_events (location_id, id);
+CREATE INDEX IF NOT EXISTS idx_availability_events_created_at ON availability_events (created_at);
@@ -0,0 +1,13 @@
+-- Perangkat kiosk (layar dinding di ruang game) yang tersambung ke WebSocket
+-- /api/kiosk/ws. Tokennya berumur panjang dan hanya hash SHA-256-nya yang
+-- disimpan; perangkat yang dicabut (revoked_at terisi) tidak dapat tersambung lagi.
+CREATE TABLE IF NOT EXISTS devices (
+    id           bigserial   PRIMARY KEY,
+    name         text        NOT NULL,
+    location_id  bigint      NOT NULL REFERENCES locations (id),
+    token_hash   text        NOT NULL UNIQUE,
+    created_at   timestamptz NOT NULL,
+    last_seen_at timestamptz,
+    revoked_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_devices_location_id ON devices (location_id);
@@ -0,0 +1,21 @@
+-- Waitlist untuk slot yang sudah penuh. tv_id NULL berarti TV mana pun dengan
+-- console_type yang sama di location_id. Saat slot kosong, entri waiting tertua
+-- menjadi offered dan slotnya ditahan sampai offer_expires_at.
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id               bigserial   PRIMARY KEY,
+    user_id          text        NOT NULL REFERENCES users (id),
+    location_id      bigint      NOT NULL REFERENCES locations (id),
+    console_type     text        NOT NULL,
+    tv_id            bigint      REFERENCES tv_infos (id),
+    time_slot        text        NOT NULL,
+    status           text        NOT NULL DEFAULT 'waiting',
+    offered_tv_id    bigint      REFERENCES tv_infos (id),
+    offer_expires_at timestamptz,
+    reservation_id   bigint      REFERENCES reservations (id),
+    created_at       timestamptz NOT NULL,
+    updated_at       timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_slot_status ON waitlist_entries (time_slot, status);
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_user_status ON waitlist_entries (user_id, status);
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_offer_expires_at ON waitlist_entries (offer_expires_at)
+    WHERE status = 'offered';
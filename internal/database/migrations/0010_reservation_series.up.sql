@@ -0,0 +1,27 @@
+-- Booking berulang yang disetujui staff, misalnya latihan klub esports. Aturan
+-- series (frekuensi, tanggal, jam lokal, pengecualian) disimpan di sini, sedangkan
+-- kejadiannya dijabarkan menjadi baris reservations dengan series_id.
+CREATE TABLE IF NOT EXISTS reservation_series (
+    id              bigserial   PRIMARY KEY,
+    organizer_id    text        NOT NULL REFERENCES users (id),
+    title           text        NOT NULL,
+    location_id     bigint      NOT NULL REFERENCES locations (id),
+    tv_ids          text        NOT NULL,
+    frequency       text        NOT NULL,
+    start_date      text        NOT NULL,
+    end_date        text        NOT NULL,
+    start_hour      bigint      NOT NULL,
+    end_hour        bigint      NOT NULL,
+    exception_dates text,
+    overrides       text,
+    status          text        NOT NULL DEFAULT 'pending',
+    reviewed_by     text        REFERENCES users (id),
+    reviewed_at     timestamptz,
+    created_at      timestamptz NOT NULL,
+    updated_at      timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_reservation_series_location_status ON reservation_series (location_id, status);
+CREATE INDEX IF NOT EXISTS idx_reservation_series_organizer_id ON reservation_series (organizer_id);
+
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS series_id bigint REFERENCES reservation_series (id);
+CREATE INDEX IF NOT EXISTS idx_reservations_series_id ON reservations (series_id);
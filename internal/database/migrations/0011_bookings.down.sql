@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_reservations_borrower_slot;
+ALTER TABLE reservations DROP COLUMN IF EXISTS booking_id;
+
+DROP TABLE IF EXISTS bookings;
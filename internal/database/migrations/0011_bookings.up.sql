@@ -0,0 +1,12 @@
+-- Booking mengelompokkan beberapa reservasi (beberapa slot dan/atau TV) yang
+-- dipesan dalam satu request, misalnya dua jam berturut-turut atau dua TV
+-- bersebelahan untuk turnamen.
+CREATE TABLE IF NOT EXISTS bookings (
+    id          bigserial   PRIMARY KEY,
+    borrower_id text        NOT NULL REFERENCES users (id),
+    created_at  timestamptz NOT NULL
+);
+
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS booking_id bigint REFERENCES bookings (id);
+CREATE INDEX IF NOT EXISTS idx_reservations_booking_id ON reservations (booking_id);
+CREATE INDEX IF NOT EXISTS idx_reservations_borrower_slot ON reservations (borrower_id, time_slot);
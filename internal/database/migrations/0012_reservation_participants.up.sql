@@ -0,0 +1,14 @@
+-- Pemain lain yang diundang peminjam ke reservasinya. Kehadirannya dicatat saat
+-- check-in agar poin kredit dapat diberikan ke semua pemain yang hadir.
+CREATE TABLE IF NOT EXISTS reservation_participants (
+    id             bigserial   PRIMARY KEY,
+    reservation_id bigint      NOT NULL REFERENCES reservations (id),
+    user_id        text        NOT NULL REFERENCES users (id),
+    status         text        NOT NULL DEFAULT 'invited',
+    attended       boolean,
+    responded_at   timestamptz,
+    created_at     timestamptz NOT NULL,
+    updated_at     timestamptz NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reservation_participants_reservation_user ON reservation_participants (reservation_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_reservation_participants_user_status ON reservation_participants (user_id, status);
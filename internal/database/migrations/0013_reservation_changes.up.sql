@@ -0,0 +1,13 @@
+-- Riwayat perpindahan reservasi ke slot dan/atau TV lain lewat PATCH
+-- /api/reservations/:id, agar staff dapat menelusuri siapa memindahkan apa.
+CREATE TABLE IF NOT EXISTS reservation_changes (
+    id             bigserial   PRIMARY KEY,
+    reservation_id bigint      NOT NULL REFERENCES reservations (id),
+    changed_by     text        NOT NULL REFERENCES users (id),
+    from_tv_id     integer     NOT NULL,
+    from_time_slot text        NOT NULL,
+    to_tv_id       integer     NOT NULL,
+    to_time_slot   text        NOT NULL,
+    created_at     timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_reservation_changes_reservation_id ON reservation_changes (reservation_id);
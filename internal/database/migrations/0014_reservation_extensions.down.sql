@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reservations_extends_id;
+ALTER TABLE reservations DROP COLUMN IF EXISTS extends_id;
@@ -0,0 +1,4 @@
+-- Perpanjangan sesi disimpan sebagai reservasi baru pada slot berikutnya yang
+-- menunjuk ke reservasi yang diperpanjang.
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS extends_id bigint REFERENCES reservations (id);
+CREATE INDEX IF NOT EXISTS idx_reservations_extends_id ON reservations (extends_id);
@@ -0,0 +1,2 @@
+ALTER TABLE reservations DROP COLUMN IF EXISTS played_minutes;
+ALTER TABLE reservations DROP COLUMN IF EXISTS checked_out_at;
@@ -0,0 +1,4 @@
+-- Check-out lebih awal dan lama bermain sebenarnya untuk statistik pemakaian,
+-- terpisah dari waktu yang dipesan.
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS checked_out_at timestamptz;
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS played_minutes integer;
@@ -0,0 +1,3 @@
+-- Sesi walk-in dicatat staff untuk mahasiswa yang datang langsung saat TV
+-- kosong, sehingga ikut terhitung di riwayat dan statistik pemakaian.
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS walk_in boolean NOT NULL DEFAULT false;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_reservations_active_slot;
@@ -0,0 +1,6 @@
+-- Satu slot pada satu TV hanya boleh dipegang satu reservasi aktif, sebagai
+-- pengaman terakhir jika dua transaksi lolos pemeriksaan ketersediaan
+-- bersamaan. Sesi yang sudah check-out lebih awal tidak lagi memegang slotnya
+-- sehingga sisa slot dapat dipakai walk-in.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reservations_active_slot ON reservations (tv_id, time_slot)
+    WHERE deleted_at IS NULL AND status NOT IN ('cancelled', 'no_show') AND checked_out_at IS NULL;
@@ -0,0 +1,4 @@
+-- Batas jam berturut-turut saat memperpanjang sesi diatur staff per lokasi.
+-- NULL berarti memakai BOOKING_MAX_CONSECUTIVE_HOURS dari konfigurasi.
+ALTER TABLE locations ADD COLUMN IF NOT EXISTS max_consecutive_hours integer
+    CHECK (max_consecutive_hours BETWEEN 1 AND 24);
@@ -0,0 +1,133 @@
+// Package events
+package events
+
+import (
+	"context"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"sync"
+	"time"
+)
+
+const (
+	// Retention adalah lama event disimpan untuk diputar ulang lewat Last-Event-ID.
+	Retention = time.Hour
+
+	// replayLimit membatasi jumlah event yang diputar ulang saat klien tersambung
+	// kembali; klien yang tertinggal lebih jauh diminta memuat ulang semuanya.
+	replayLimit = 500
+	// bufferSize adalah jumlah event yang boleh mengantre per subscriber sebelum
+	// subscriber tersebut dianggap terlalu lambat dan diputus.
+	bufferSize = 64
+)
+
+// Hub meneruskan event ketersediaan ke subscriber SSE di proses ini. Event dari
+// semua instance diterima lewat Listen, termasuk event yang dibuat instance ini.
+type Hub struct {
+	source repository.AvailabilityEventRepository
+
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// NewHub membuat Hub yang membaca dan mendengarkan event dari source.
+func NewHub(source repository.AvailabilityEventRepository) *Hub {
+	return &Hub{source: source, subs: map[*Subscription]struct{}{}}
+}
+
+// Subscription adalah satu klien yang menerima event, opsional hanya untuk satu lokasi.
+type Subscription struct {
+	hub        *Hub
+	locationID *int
+	events     chan models.AvailabilityEvent
+}
+
+// Events mengirim event baru secara berurutan. Channel ditutup jika subscriber
+// terlalu lambat, listener database terputus, atau Hub ditutup; klien harus
+// tersambung kembali dengan Last-Event-ID agar event yang terlewat diputar ulang.
+func (s *Subscription) Events() <-chan models.AvailabilityEvent {
+	return s.events
+}
+
+// Close berhenti berlangganan.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.drop(s)
+}
+
+// Subscribe mendaftarkan subscriber baru. locationID nil berarti semua lokasi.
+func (h *Hub) Subscribe(locationID *int) *Subscription {
+	sub := &Subscription{hub: h, locationID: locationID, events: make(chan models.AvailabilityEvent, bufferSize)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(sub.events)
+		return sub
+	}
+	h.subs[sub] = struct{}{}
+	return sub
+}
+
+// Since mengembalikan event setelah afterID untuk diputar ulang. complete
+// bernilai false jika sebagian event sudah tidak tersedia, sehingga klien
+// harus memuat ulang ketersediaan lewat GET /api/availability. afterID 0 berarti
+// klien belum pernah menerima event, jadi tidak ada yang perlu diputar ulang.
+func (h *Hub) Since(ctx context.Context, afterID int64, locationID *int) ([]models.AvailabilityEvent, bool, error) {
+	if afterID <= 0 {
+		return nil, true, nil
+	}
+	events, complete, err := h.source.Since(ctx, afterID, locationID, replayLimit)
+	if err != nil {
+		return nil, false, err
+	}
+	if !complete || len(events) == replayLimit {
+		return nil, false, nil
+	}
+	return events, true, nil
+}
+
+// Publish meneruskan e ke subscriber yang cocok tanpa menunggu subscriber yang lambat.
+func (h *Hub) Publish(e models.AvailabilityEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		if sub.locationID != nil && *sub.locationID != e.LocationID {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			h.drop(sub)
+		}
+	}
+}
+
+// Listen meneruskan event dari database ke subscriber sampai ctx selesai atau
+// koneksi terputus. Dijalankan ulang oleh scheduler jika gagal. Selama listener
+// tidak berjalan event bisa terlewat, jadi semua subscriber diputus agar
+// tersambung kembali dan memutar ulang dari Last-Event-ID. Saat ctx selesai
+// (shutdown), Hub ditutup sehingga semua stream berakhir.
+func (h *Hub) Listen(ctx context.Context) error {
+	err := h.source.Listen(ctx, h.Publish)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		h.drop(sub)
+	}
+	if ctx.Err() != nil {
+		h.closed = true
+	}
+	return err
+}
+
+// drop menutup subscriber; h.mu harus sudah dikunci.
+func (h *Hub) drop(sub *Subscription) {
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.events)
+	}
+}
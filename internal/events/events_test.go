@@ -0,0 +1,149 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"playcorner-be/internal/events"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository/memory"
+)
+
+func appendEvents(t *testing.T, store *memory.Store, locationIDs ...int) {
+	t.Helper()
+	for _, locationID := range locationIDs {
+		e := &models.AvailabilityEvent{Type: "reservation.created", LocationID: locationID, TVID: 1}
+		if err := store.AvailabilityEvents().Append(context.Background(), e); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func ids(events []models.AvailabilityEvent) []int64 {
+	out := make([]int64, len(events))
+	for i, e := range events {
+		out[i] = e.ID
+	}
+	return out
+}
+
+func TestSince(t *testing.T) {
+	store := memory.New()
+	appendEvents(t, store, 1, 2, 1, 2)
+	location := 1
+
+	tests := []struct {
+		name         string
+		afterID      int64
+		locationID   *int
+		want         []int64
+		wantComplete bool
+	}{
+		{name: "replays every later event", afterID: 2, want: []int64{3, 4}, wantComplete: true},
+		{name: "filters by location", afterID: 1, locationID: &location, want: []int64{3}, wantComplete: true},
+		{name: "returns nothing when the client is up to date", afterID: 4, want: []int64{}, wantComplete: true},
+		{name: "replays nothing to a client without Last-Event-ID", afterID: 0, want: []int64{}, wantComplete: true},
+		{name: "reports a gap when the last event is unknown", afterID: 9, want: []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, complete, err := events.NewHub(store.AvailabilityEvents()).Since(context.Background(), tt.afterID, tt.locationID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if complete != tt.wantComplete || len(got) != len(tt.want) {
+				t.Fatalf("Since(%d) = %v (complete %v), want %v (complete %v)", tt.afterID, ids(got), complete, tt.want, tt.wantComplete)
+			}
+			for i := range got {
+				if got[i].ID != tt.want[i] {
+					t.Fatalf("Since(%d) = %v, want %v", tt.afterID, ids(got), tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSinceTooFarBehind(t *testing.T) {
+	store := memory.New()
+	for range 600 {
+		appendEvents(t, store, 1)
+	}
+
+	// Klien yang tertinggal lebih dari batas replay diminta memuat ulang semuanya
+	got, complete, err := events.NewHub(store.AvailabilityEvents()).Since(context.Background(), 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if complete || got != nil {
+		t.Fatalf("got %d events (complete %v), want none and incomplete", len(got), complete)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	hub := events.NewHub(memory.New().AvailabilityEvents())
+	location := 2
+	all := hub.Subscribe(nil)
+	defer all.Close()
+	filtered := hub.Subscribe(&location)
+	defer filtered.Close()
+
+	hub.Publish(models.AvailabilityEvent{ID: 1, LocationID: 1})
+	hub.Publish(models.AvailabilityEvent{ID: 2, LocationID: 2})
+
+	for _, e := range []int64{1, 2} {
+		if got := <-all.Events(); got.ID != e {
+			t.Fatalf("unfiltered subscriber got event %d, want %d", got.ID, e)
+		}
+	}
+	if got := <-filtered.Events(); got.ID != 2 {
+		t.Fatalf("location subscriber got event %d, want 2", got.ID)
+	}
+	select {
+	case e := <-filtered.Events():
+		t.Fatalf("location subscriber got unexpected event %+v", e)
+	default:
+	}
+}
+
+func TestPublishDropsSlowSubscriber(t *testing.T) {
+	hub := events.NewHub(memory.New().AvailabilityEvents())
+	slow := hub.Subscribe(nil)
+
+	// Subscriber yang tidak membaca diputus setelah antreannya penuh, bukan memblokir Publish
+	for i := range 100 {
+		hub.Publish(models.AvailabilityEvent{ID: int64(i + 1)})
+	}
+
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	if received == 0 || received >= 100 {
+		t.Fatalf("slow subscriber received %d events before being dropped", received)
+	}
+	// Close setelah diputus tidak boleh panik karena channel sudah ditutup
+	slow.Close()
+}
+
+func TestListenClosesHubOnShutdown(t *testing.T) {
+	hub := events.NewHub(memory.New().AvailabilityEvents())
+	sub := hub.Subscribe(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- hub.Listen(ctx) }()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Listen did not return after shutdown")
+	}
+	if _, ok := <-sub.Events(); ok {
+		t.Fatal("existing subscription is still open after shutdown")
+	}
+	if _, ok := <-hub.Subscribe(nil).Events(); ok {
+		t.Fatal("new subscription is open after shutdown")
+	}
+}
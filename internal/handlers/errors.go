@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/logging"
+	"playcorner-be/internal/models"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errInvalidBody dikembalikan handler saat body request tidak dapat di-parse.
+var errInvalidBody = apperr.BadRequest("INVALID_BODY", "Cannot parse request body")
+
+// statusByKind memetakan jenis kesalahan domain ke status HTTP dan label envelope.
+var statusByKind = map[apperr.Kind]struct {
+	code   int
+	status string
+}{
+	apperr.KindBadRequest:    {fiber.StatusBadRequest, "BAD_REQUEST"},
+	apperr.KindValidation:    {fiber.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY"},
+	apperr.KindUnauthorized:  {fiber.StatusUnauthorized, "UNAUTHORIZED"},
+	apperr.KindForbidden:     {fiber.StatusForbidden, "FORBIDDEN"},
+	apperr.KindNotFound:      {fiber.StatusNotFound, "NOT_FOUND"},
+	apperr.KindConflict:      {fiber.StatusConflict, "CONFLICT"},
+	apperr.KindQuotaExceeded: {fiber.StatusTooManyRequests, "TOO_MANY_REQUESTS"},
+	apperr.KindInternal:      {fiber.StatusInternalServerError, "SERVER_ERROR"},
+}
+
+// fiberErrorCodes memberi kode stabil untuk kesalahan bawaan fiber (rute tidak
+// ditemukan, body terlalu besar, dan sebagainya).
+var fiberErrorCodes = map[int]string{
+	fiber.StatusBadRequest:            "BAD_REQUEST",
+	fiber.StatusNotFound:              "ROUTE_NOT_FOUND",
+	fiber.StatusMethodNotAllowed:      "METHOD_NOT_ALLOWED",
+	fiber.StatusRequestEntityTooLarge: "BODY_TOO_LARGE",
+	fiber.StatusUnsupportedMediaType:  "UNSUPPORTED_MEDIA_TYPE",
+	fiber.StatusRequestTimeout:        "REQUEST_TIMEOUT",
+	fiber.StatusTooManyRequests:       "TOO_MANY_REQUESTS",
+	fiber.StatusServiceUnavailable:    "SERVICE_UNAVAILABLE",
+}
+
+// ErrorHandler adalah fiber.Config.ErrorHandler aplikasi. Semua kesalahan yang
+// dikembalikan handler dan middleware diubah menjadi ErrorResponse dengan
+// errorCode yang stabil; kesalahan internal dicatat di log tanpa membocorkan
+// detailnya ke klien.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	code, status := fiber.StatusInternalServerError, "SERVER_ERROR"
+	data := models.ErrorData{ErrorMsg: "Internal server error", ErrorCode: "INTERNAL_ERROR"}
+
+	var (
+		appErr   *apperr.Error
+		fiberErr *fiber.Error
+	)
+	switch {
+	case errors.As(err, &appErr):
+		mapped := statusByKind[appErr.Kind]
+		code, status = mapped.code, mapped.status
+		data = models.ErrorData{ErrorMsg: appErr.Msg, ErrorCode: appErr.Code, Fields: appErr.Fields}
+		if appErr.Kind == apperr.KindInternal {
+			logging.ForRequest(c).Error("unhandled error", "error", err)
+		}
+	case errors.As(err, &fiberErr):
+		code = fiberErr.Code
+		status = statusText(code)
+		errorCode, ok := fiberErrorCodes[code]
+		if !ok {
+			errorCode = status
+		}
+		data = models.ErrorData{ErrorMsg: fiberErr.Message, ErrorCode: errorCode}
+		if code >= fiber.StatusInternalServerError {
+			logging.ForRequest(c).Error("unhandled error", "error", err)
+		}
+	default:
+		logging.ForRequest(c).Error("unhandled error", "error", err)
+	}
+
+	return c.Status(code).JSON(models.ErrorResponse{Code: code, Status: status, Data: data})
+}
+
+// statusText mengubah status HTTP menjadi label envelope, misalnya 404 menjadi NOT_FOUND.
+func statusText(code int) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_").Replace(http.StatusText(code)))
+}
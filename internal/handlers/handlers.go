@@ -3,58 +3,99 @@ package handlers
 
 import (
 	"errors"
-	"log"
-	"playcorner-be/internal/auth"
-	"playcorner-be/internal/database"
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/events"
+	"playcorner-be/internal/health"
+	"playcorner-be/internal/metrics"
 	"playcorner-be/internal/models"
-	"playcorner-be/internal/utils"
+	"playcorner-be/internal/repository"
+	"playcorner-be/internal/services"
+	"playcorner-be/internal/validation"
 	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"gorm.io/gorm"
 )
 
-// Login handles user login
-func Login(c *fiber.Ctx) error {
-	var body models.LoginBody
-	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
-			Code: 400, Status: "BAD_REQUEST", Data: models.ErrorData{ErrorMsg: "Cannot parse JSON"},
-		})
+// Dependencies berisi semua service yang dibutuhkan handler.
+type Dependencies struct {
+	Auth        *services.AuthService
+	Users       *services.UserService
+	Booking     *services.BookingService
+	Maintenance *services.MaintenanceService
+	Locations   *services.LocationService
+	Devices     *services.DeviceService
+	Kiosk       *services.KioskService
+	Health      *health.Checker
+	Metrics     *metrics.Metrics
+
+	// Availability meneruskan perubahan ketersediaan slot ke klien SSE.
+	Availability *events.Hub
+	// IdempotencyKeys menyimpan respons request yang dikirim dengan Idempotency-Key.
+	IdempotencyKeys repository.IdempotencyKeyRepository
+
+	// UploadDir adalah direktori penyimpanan foto laporan kerusakan.
+	UploadDir string
+	// SecureCookies menandai cookie refresh token sebagai Secure (aktif di produksi).
+	SecureCookies bool
+}
+
+// Handler menampung dependensi untuk semua handler HTTP.
+type Handler struct {
+	Dependencies
+}
+
+// New membuat Handler dari dependensinya.
+func New(deps Dependencies) *Handler {
+	return &Handler{Dependencies: deps}
+}
+
+// parseBody membaca body request ke out lalu memvalidasinya berdasarkan tag
+// `validate`. Body yang rusak menghasilkan 400, field yang tidak valid 422.
+func parseBody(c *fiber.Ctx, out any) error {
+	if err := c.BodyParser(out); err != nil {
+		return errInvalidBody
 	}
+	return validation.Struct(out)
+}
 
-	var user models.User
-	if err := database.DB.Where("id = ?", body.Identifier).First(&user).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
-				Code: 401, Status: "UNAUTHORIZED", Data: models.ErrorData{ErrorMsg: "Invalid identifier or password"},
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-			Code: 500, Status: "SERVER_ERROR", Data: models.ErrorData{ErrorMsg: "Database error"},
-		})
+// actorOf mengambil identitas dan peran pengguna yang diatur oleh middleware.
+func actorOf(c *fiber.Ctx) services.Actor {
+	userID, _ := c.Locals("userID").(string)
+	role, _ := c.Locals("role").(string)
+	return services.Actor{UserID: userID, Role: role}
+}
+
+// optionalIntQuery membaca query integer opsional; nilai kosong atau tidak valid menjadi nil.
+func optionalIntQuery(c *fiber.Ctx, key string) *int {
+	v, err := strconv.Atoi(c.Query(key))
+	if err != nil {
+		return nil
 	}
+	return &v
+}
 
-	if !utils.CheckPasswordHash(body.Password, user.PasswordHash) {
-		return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
-			Code: 401, Status: "UNAUTHORIZED", Data: models.ErrorData{ErrorMsg: "Invalid identifier or password"},
-		})
+// Login handles user login
+func (h *Handler) Login(c *fiber.Ctx) error {
+	var body models.LoginBody
+	if err := parseBody(c, &body); err != nil {
+		return err
 	}
 
-	accessToken, refreshToken, err := auth.GenerateTokens(user.ID)
+	tokens, err := h.Auth.Login(c.UserContext(), body.Identifier, body.Password)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-			Code: 500, Status: "SERVER_ERROR", Data: models.ErrorData{ErrorMsg: "Could not generate tokens"},
-		})
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			h.Metrics.LoginFailures.Inc()
+		}
+		return apperr.Wrap(err, "Could not generate tokens")
 	}
 
 	c.Cookie(&fiber.Cookie{
 		Name:     "refresh_token",
-		Value:    refreshToken,
-		Expires:  time.Now().Add(time.Hour * 24 * 7),
+		Value:    tokens.RefreshToken,
+		Expires:  tokens.RefreshExpiresAt,
 		HTTPOnly: true,
-		Secure:   false, // Set true in production with HTTPS
+		Secure:   h.SecureCookies,
 		SameSite: "Lax",
 	})
 
@@ -62,61 +103,41 @@ func Login(c *fiber.Ctx) error {
 		Code:   200,
 		Status: "OK",
 		Data: models.TokenCarrier{
-			AuthToken:  accessToken,
-			UserID:     user.ID,
-			ExpireDate: time.Now().Add(15 * time.Minute).Format(time.RFC3339),
+			AuthToken:  tokens.AccessToken,
+			UserID:     tokens.UserID,
+			ExpireDate: tokens.AccessExpiresAt.Format(time.RFC3339),
 		},
 	})
 }
 
 // RefreshToken handles generating a new access token
-func RefreshToken(c *fiber.Ctx) error {
+func (h *Handler) RefreshToken(c *fiber.Ctx) error {
 	refreshToken := c.Cookies("refresh_token")
 	if refreshToken == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
-			Code: 401, Status: "UNAUTHORIZED", Data: models.ErrorData{ErrorMsg: "Refresh token not found"},
-		})
+		return apperr.Unauthorized("MISSING_REFRESH_TOKEN", "Refresh token not found")
 	}
 
-	claims, err := auth.ValidateToken(refreshToken)
+	tokens, err := h.Auth.Refresh(refreshToken)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
-			Code: 401, Status: "UNAUTHORIZED", Data: models.ErrorData{ErrorMsg: "Invalid or expired refresh token"},
-		})
-	}
-
-	accessToken, _, err := auth.GenerateTokens(claims.UserID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-			Code: 500, Status: "SERVER_ERROR", Data: models.ErrorData{ErrorMsg: "Could not generate access token"},
-		})
+		return apperr.Wrap(err, "Could not generate access token")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(models.Response{
 		Code:   200,
 		Status: "OK",
 		Data: models.TokenCarrier{
-			AuthToken:  accessToken,
-			UserID:     claims.UserID,
-			ExpireDate: time.Now().Add(15 * time.Minute).Format(time.RFC3339),
+			AuthToken:  tokens.AccessToken,
+			UserID:     tokens.UserID,
+			ExpireDate: tokens.AccessExpiresAt.Format(time.RFC3339),
 		},
 	})
 }
 
 // GetUser retrieves a user's details
-func GetUser(c *fiber.Ctx) error {
-	userID := c.Params("userId")
-	var user models.User
-
-	if err := database.DB.Select("id", "name", "faculty", "major", "credit_score", "profile_pict_url").First(&user, "id = ?", userID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
-				Code: 404, Status: "NOT_FOUND", Data: models.ErrorData{ErrorMsg: "User not found"},
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-			Code: 500, Status: "SERVER_ERROR", Data: models.ErrorData{ErrorMsg: "Database error"},
-		})
+func (h *Handler) GetUser(c *fiber.Ctx) error {
+	user, err := h.Users.Get(c.UserContext(), c.Params("userId"))
+	if err != nil {
+		return apperr.Wrap(err, "Database error")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(models.Response{
@@ -127,8 +148,7 @@ func GetUser(c *fiber.Ctx) error {
 }
 
 // GetUserHistories retrieves a user's reservation history
-func GetUserHistories(c *fiber.Ctx) error {
-	userID := c.Params("userId")
+func (h *Handler) GetUserHistories(c *fiber.Ctx) error {
 	limitStr := c.Query("limit", "10")
 	offsetStr := c.Query("offset", "0")
 
@@ -141,173 +161,351 @@ func GetUserHistories(c *fiber.Ctx) error {
 		offset = 0
 	}
 
-	var reservations []models.Reservation
-	var total int64
+	pagedData, err := h.Users.Histories(c.UserContext(), c.Params("userId"), limit, offset)
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch user histories")
+	}
 
-	if err := database.DB.Model(&models.Reservation{}).Where("borrower_id = ?", userID).Count(&total).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-			Code:   500,
-			Status: "SERVER_ERROR",
-			Data:   models.ErrorData{ErrorMsg: "Could not count user histories"},
-		})
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   pagedData,
+	})
+}
+
+// GetAllTVs retrieves all available TVs and Games, optionally filtered by location
+func (h *Handler) GetAllTVs(c *fiber.Ctx) error {
+	tvs, err := h.Booking.ListTVs(c.UserContext(), optionalIntQuery(c, "locationId"))
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch TV list with games")
 	}
 
-	if err := database.DB.Where("borrower_id = ?", userID).Order("created_at desc").Limit(int(limit)).Offset(int(offset)).Find(&reservations).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-			Code:   500,
-			Status: "SERVER_ERROR",
-			Data:   models.ErrorData{ErrorMsg: "Could not fetch user histories"},
-		})
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   tvs,
+	})
+}
+
+// GetTVReservations retrieves the availability status for a specific TV
+func (h *Handler) GetTVReservations(c *fiber.Ctx) error {
+	tvID, err := c.ParamsInt("tvId")
+	if err != nil {
+		return services.ErrTVNotFound
 	}
 
-	histories := []models.History{}
-	for _, r := range reservations {
-		histories = append(histories, models.History{
-			ID:                  r.ID,
-			TVID:                r.TVID,
-			ReservationDateTime: r.TimeSlot,
-			TVPictURL:           "https://placehold.co/600x400/?text=TV+" + strconv.Itoa(r.TVID),
-		})
+	tvStatus, err := h.Booking.TVStatus(c.UserContext(), tvID)
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch reservations")
 	}
 
-	pagedData := models.PagedData{
-		Offset: offset,
-		Limit:  limit,
-		Total:  total,
-		Data:   histories,
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   tvStatus,
+	})
+}
+
+// GetAvailability retrieves today's slot availability for every TV, optionally filtered by location
+func (h *Handler) GetAvailability(c *fiber.Ctx) error {
+	statuses, err := h.Booking.Availability(c.UserContext(), optionalIntQuery(c, "locationId"))
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch reservations")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(models.Response{
 		Code:   200,
 		Status: "OK",
-		Data:   pagedData,
+		Data:   statuses,
+	})
+}
+
+// CreateReservation books a slot on the TV in the path for the authenticated user
+func (h *Handler) CreateReservation(c *fiber.Ctx) error {
+	tvID, err := c.ParamsInt("tvId")
+	if err != nil {
+		return services.ErrTVNotFound
+	}
+
+	var body models.ReservationBody
+	if err := c.BodyParser(&body); err != nil {
+		return errInvalidBody
+	}
+	// TV pada body hanya boleh mengulang TV pada path, bukan memesan TV lain
+	var mismatch []models.FieldError
+	if body.TVID != 0 && body.TVID != tvID {
+		mismatch = append(mismatch, models.FieldError{Field: "tvId", Message: "tvId must match the TV in the path"})
+	}
+	if err := validation.Struct(body, mismatch...); err != nil {
+		return err
+	}
+
+	// Ambil ID pengguna yang sudah diautentikasi dari context.
+	// Nilai ini diatur oleh AuthMiddleware dan merupakan sumber kebenaran.
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		// Ini seharusnya tidak terjadi jika middleware berjalan, tapi ini adalah penjaga yang baik.
+		return apperr.Unauthorized("INVALID_TOKEN", "User identity not found in token")
+	}
+
+	// Gunakan userID dari token, bukan dari body request.
+	if _, err := h.Booking.CreateReservation(c.UserContext(), userID, tvID, body.Timeslot); err != nil {
+		if errors.Is(err, services.ErrSlotTaken) {
+			h.Metrics.ReservationConflicts.Inc()
+		}
+		return apperr.Wrap(err, "Could not create reservation")
+	}
+	h.Metrics.ReservationsCreated.Inc()
+
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Code:   201,
+		Status: "CREATED",
+		Data:   nil,
 	})
 }
 
-// GetAllTVs retrieves all available TVs and Games
-func GetAllTVs(c *fiber.Ctx) error {
-	var tvs []models.TVInfo
+// CreateBooking books several TV and timeslot pairs at once, all or nothing
+func (h *Handler) CreateBooking(c *fiber.Ctx) error {
+	var body models.BookingBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	booking, reservations, err := h.Booking.CreateBooking(c.UserContext(), actorOf(c).UserID, body.Items)
+	if err != nil {
+		if errors.Is(err, services.ErrSlotTaken) {
+			h.Metrics.ReservationConflicts.Inc()
+		}
+		return apperr.Wrap(err, "Could not create booking")
+	}
+	h.Metrics.ReservationsCreated.Add(float64(len(reservations)))
 
-	if err := database.DB.Preload("Games").Find(&tvs).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-			Code:   500,
-			Status: "SERVER_ERROR",
-			Data:   models.ErrorData{ErrorMsg: "Could not fetch TV list with games"},
+	items := make([]fiber.Map, 0, len(reservations))
+	for _, r := range reservations {
+		items = append(items, fiber.Map{
+			"reservationId": r.ID,
+			"tvId":          r.TVID,
+			"timeslot":      r.TimeSlot,
+			"status":        r.Status,
 		})
 	}
 
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Code:   201,
+		Status: "CREATED",
+		Data: fiber.Map{
+			"bookingId":    booking.ID,
+			"reservations": items,
+		},
+	})
+}
+
+// GetNotifications retrieves the authenticated user's notifications
+func (h *Handler) GetNotifications(c *fiber.Ctx) error {
+	notifications, err := h.Users.Notifications(c.UserContext(), actorOf(c).UserID)
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch notifications")
+	}
+
 	return c.Status(fiber.StatusOK).JSON(models.Response{
 		Code:   200,
 		Status: "OK",
-		Data:   tvs,
+		Data:   notifications,
 	})
 }
 
-// GetTVReservations retrieves the availability status for a specific TV
-func GetTVReservations(c *fiber.Ctx) error {
-	tvID := c.Params("tvId")
+// CheckInReservation lets staff record that the borrower and their invited players showed up for their slot
+func (h *Handler) CheckInReservation(c *fiber.Ctx) error {
+	var body models.CheckInBody
+	if len(c.Body()) > 0 {
+		if err := parseBody(c, &body); err != nil {
+			return err
+		}
+	}
 
-	var tvInfo models.TVInfo
-	if err := database.DB.First(&tvInfo, tvID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
-			Code: 404, Status: "NOT_FOUND", Data: models.ErrorData{ErrorMsg: "TV not found"},
-		})
+	reservationID, err := c.ParamsInt("reservationId")
+	if err != nil {
+		return services.ErrReservationNotFound
 	}
 
-	var reservations []models.Reservation
-	today := time.Now().Format("2006-01-02")
-	database.DB.Where("tv_id = ? AND time_slot LIKE ?", tvID, today+"%").Find(&reservations)
+	reservation, participants, err := h.Booking.CheckIn(c.UserContext(), actorOf(c), uint(reservationID), body.Attendees)
+	if err != nil {
+		return apperr.Wrap(err, "Could not check in reservation")
+	}
 
-	reservedSlots := make(map[string]bool)
-	for _, r := range reservations {
-		reservedSlots[r.TimeSlot] = true
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data: fiber.Map{
+			"reservationId": reservation.ID,
+			"tvId":          reservation.TVID,
+			"timeslot":      reservation.TimeSlot,
+			"status":        reservation.Status,
+			"checkedInAt":   reservation.CheckedInAt,
+			"participants":  participants,
+		},
+	})
+}
+
+// CancelReservation cancels a booked reservation and offers the slot to the waitlist
+func (h *Handler) CancelReservation(c *fiber.Ctx) error {
+	reservationID, err := c.ParamsInt("reservationId")
+	if err != nil || reservationID <= 0 {
+		return services.ErrReservationNotFound
 	}
 
-	var timeSlots []models.TimeSlot
-	for i := 9; i <= 17; i++ {
-		startTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), i, 0, 0, 0, time.UTC)
-		endTime := startTime.Add(1 * time.Hour)
+	actor := actorOf(c)
+	reservation, err := h.Booking.CancelReservation(c.UserContext(), actor, uint(reservationID))
+	if err != nil {
+		return apperr.Wrap(err, "Could not cancel reservation")
+	}
+	reason := "user"
+	if actor.UserID != reservation.BorrowerID {
+		reason = "staff"
+	}
+	h.Metrics.ReservationsCancelled.WithLabelValues(reason).Inc()
 
-		slotString := startTime.Format(time.RFC3339)
-		availability := "available"
-		if reservedSlots[slotString] {
-			availability = "unavailable"
-		}
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data: fiber.Map{
+			"reservationId": reservation.ID,
+			"tvId":          reservation.TVID,
+			"timeslot":      reservation.TimeSlot,
+			"status":        reservation.Status,
+		},
+	})
+}
 
-		timeSlots = append(timeSlots, models.TimeSlot{
-			StartTime:    slotString,
-			EndTime:      endTime.Format(time.RFC3339),
-			Availability: availability,
-		})
+// RescheduleReservation moves a booked reservation to another timeslot and/or TV, keeping the original if the new slot is taken
+func (h *Handler) RescheduleReservation(c *fiber.Ctx) error {
+	var body models.RescheduleBody
+	if err := parseBody(c, &body); err != nil {
+		return err
 	}
 
-	tvStatus := models.TV{
-		ID:          tvInfo.ID,
-		ConsoleType: tvInfo.ConsoleType,
-		TimeSlots:   timeSlots,
+	reservationID, err := c.ParamsInt("reservationId")
+	if err != nil || reservationID <= 0 {
+		return services.ErrReservationNotFound
+	}
+
+	reservation, err := h.Booking.RescheduleReservation(c.UserContext(), actorOf(c), uint(reservationID), body)
+	if err != nil {
+		return apperr.Wrap(err, "Could not reschedule reservation")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(models.Response{
 		Code:   200,
 		Status: "OK",
-		Data:   tvStatus,
+		Data: fiber.Map{
+			"reservationId": reservation.ID,
+			"tvId":          reservation.TVID,
+			"timeslot":      reservation.TimeSlot,
+			"status":        reservation.Status,
+		},
 	})
 }
 
-func CreateReservation(c *fiber.Ctx) error {
-	var body models.ReservationBody
-	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
-			Code: 400, Status: "BAD_REQUEST", Data: models.ErrorData{ErrorMsg: "Cannot parse JSON"},
-		})
+// ExtendReservation extends an active session into the following slot on the same TV
+func (h *Handler) ExtendReservation(c *fiber.Ctx) error {
+	reservationID, err := c.ParamsInt("reservationId")
+	if err != nil || reservationID <= 0 {
+		return services.ErrReservationNotFound
 	}
 
-	// Ambil ID pengguna yang sudah diautentikasi dari context.
-	// Nilai ini diatur oleh AuthMiddleware dan merupakan sumber kebenaran.
-	userID, ok := c.Locals("userID").(string)
-	if !ok || userID == "" {
-		// Ini seharusnya tidak terjadi jika middleware berjalan, tapi ini adalah penjaga yang baik.
-		return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
-			Code: 401, Status: "UNAUTHORIZED", Data: models.ErrorData{ErrorMsg: "User identity not found in token"},
-		})
+	extension, err := h.Booking.ExtendReservation(c.UserContext(), actorOf(c), uint(reservationID))
+	if err != nil {
+		return apperr.Wrap(err, "Could not extend session")
 	}
 
-	// Cek apakah slot sudah dipesan
-	var existingReservation models.Reservation
-	if err := database.DB.Where("tv_id = ? AND time_slot = ?", body.TVID, body.Timeslot).First(&existingReservation).Error; err != nil {
-		// Pastikan error BUKAN karena data tidak ditemukan
-		if !errors.Is(err, gorm.ErrRecordNotFound) {
-			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-				Code: 500, Status: "SERVER_ERROR", Data: models.ErrorData{ErrorMsg: "Database error during availability check"},
-			})
-		}
-	} else {
-		// Jika tidak ada error, berarti data ditemukan dan slot sudah dipesan
-		return c.Status(fiber.StatusConflict).JSON(models.ErrorResponse{
-			Code: 409, Status: "CONFLICT", Data: models.ErrorData{ErrorMsg: "Timeslot is already booked"},
-		})
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Code:   201,
+		Status: "CREATED",
+		Data: fiber.Map{
+			"reservationId": extension.ID,
+			"extendsId":     extension.ExtendsID,
+			"tvId":          extension.TVID,
+			"timeslot":      extension.TimeSlot,
+			"status":        extension.Status,
+		},
+	})
+}
+
+// CheckOutReservation ends an active session early and frees the rest of the slot for walk-ins
+func (h *Handler) CheckOutReservation(c *fiber.Ctx) error {
+	reservationID, err := c.ParamsInt("reservationId")
+	if err != nil || reservationID <= 0 {
+		return services.ErrReservationNotFound
 	}
 
-	// Buat record reservasi baru
-	newReservation := models.Reservation{
-		TVID: body.TVID,
-		// Gunakan userID dari token, bukan dari body request.
-		BorrowerID: userID,
-		TimeSlot:   body.Timeslot,
+	reservation, err := h.Booking.CheckOut(c.UserContext(), actorOf(c), uint(reservationID))
+	if err != nil {
+		return apperr.Wrap(err, "Could not check out reservation")
 	}
+	h.Metrics.SessionPlayedMinutes.Observe(float64(*reservation.PlayedMinutes))
 
-	// Simpan ke database
-	if err := database.DB.Create(&newReservation).Error; err != nil {
-		// Log error yang sebenarnya untuk debugging di server
-		log.Printf("DATABASE ERROR on CreateReservation: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
-			Code: 500, Status: "SERVER_ERROR", Data: models.ErrorData{ErrorMsg: "Could not create reservation"},
-		})
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data: fiber.Map{
+			"reservationId": reservation.ID,
+			"tvId":          reservation.TVID,
+			"timeslot":      reservation.TimeSlot,
+			"status":        reservation.Status,
+			"checkedOutAt":  reservation.CheckedOutAt,
+			"playedMinutes": reservation.PlayedMinutes,
+		},
+	})
+}
+
+// StartWalkIn lets staff start a session right now for a student who walked up to a free TV
+func (h *Handler) StartWalkIn(c *fiber.Ctx) error {
+	var body models.WalkInBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	tvID, err := c.ParamsInt("tvId")
+	if err != nil || tvID <= 0 {
+		return services.ErrTVNotFound
+	}
+
+	session, err := h.Booking.StartWalkIn(c.UserContext(), actorOf(c), tvID, body.UserID)
+	if err != nil {
+		return apperr.Wrap(err, "Could not start walk-in session")
 	}
+	h.Metrics.WalkIns.Inc()
 
 	return c.Status(fiber.StatusCreated).JSON(models.Response{
 		Code:   201,
 		Status: "CREATED",
-		Data:   nil,
+		Data: fiber.Map{
+			"reservationId": session.ID,
+			"tvId":          session.TVID,
+			"userId":        session.BorrowerID,
+			"timeslot":      session.TimeSlot,
+			"status":        session.Status,
+			"checkedInAt":   session.CheckedInAt,
+			"walkIn":        session.WalkIn,
+		},
+	})
+}
+
+// GetReservationChanges retrieves the reschedule history of a reservation
+func (h *Handler) GetReservationChanges(c *fiber.Ctx) error {
+	reservationID, err := c.ParamsInt("reservationId")
+	if err != nil || reservationID <= 0 {
+		return services.ErrReservationNotFound
+	}
+
+	changes, err := h.Booking.ReservationChanges(c.UserContext(), actorOf(c), uint(reservationID))
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch reservation changes")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   changes,
 	})
 }
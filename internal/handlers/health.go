@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"playcorner-be/internal/health"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Healthz reports that the process is alive, without touching dependencies
+func (h *Handler) Healthz(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": health.StatusOK})
+}
+
+// Readyz reports whether the instance can serve traffic, with detail per dependency
+func (h *Handler) Readyz(c *fiber.Ctx) error {
+	report := h.Health.Ready(c.UserContext())
+	if report.Status != health.StatusOK {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(report)
+	}
+	return c.JSON(report)
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxPhotoSize membatasi ukuran foto laporan (5 MB).
+const maxPhotoSize = 5 << 20
+
+// photoExtensions memetakan tipe foto yang diterima ke ekstensi file yang
+// disimpan. Tipe ditentukan dari isi file, bukan dari nama atau header Content-Type
+// kiriman klien, karena file disajikan kembali lewat /uploads.
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// ReportIssue lets a student report a problem on a TV or one of its games
+func (h *Handler) ReportIssue(c *fiber.Ctx) error {
+	var body models.IssueBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	tvID, err := c.ParamsInt("tvId")
+	if err != nil {
+		return services.ErrTVNotFound
+	}
+	report := services.IssueReport{
+		GameID:      body.GameID,
+		Description: body.Description,
+		PhotoURL:    body.PhotoURL,
+	}
+	// Laporan divalidasi sebelum foto disimpan agar laporan yang ditolak tidak meninggalkan file
+	if err := h.Maintenance.CheckReport(c.UserContext(), tvID, report); err != nil {
+		return apperr.Wrap(err, "Could not create issue")
+	}
+
+	// Foto bersifat opsional: bisa berupa URL atau file multipart bernama "photo"
+	var photoPath string
+	if file, err := c.FormFile("photo"); err == nil {
+		filename, err := h.savePhoto(c, file, tvID)
+		if err != nil {
+			return err
+		}
+		photoPath = filepath.Join(h.UploadDir, filename)
+		report.PhotoURL = "/uploads/" + filename
+	}
+
+	issue, err := h.Maintenance.ReportIssue(c.UserContext(), actorOf(c).UserID, tvID, report)
+	if err != nil {
+		if photoPath != "" {
+			_ = os.Remove(photoPath)
+		}
+		return apperr.Wrap(err, "Could not create issue")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Code:   201,
+		Status: "CREATED",
+		Data:   issue,
+	})
+}
+
+// GetIssues lists damage tickets for the locations the staff member manages, optionally filtered by status
+func (h *Handler) GetIssues(c *fiber.Ctx) error {
+	issues, err := h.Maintenance.ListIssues(c.UserContext(), actorOf(c), c.Query("status"), optionalIntQuery(c, "tvId"))
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch issues")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   issues,
+	})
+}
+
+// UpdateIssueStatus moves a damage ticket through open, in_progress and resolved
+func (h *Handler) UpdateIssueStatus(c *fiber.Ctx) error {
+	var body models.IssueStatusBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	issueID, err := c.ParamsInt("issueId")
+	if err != nil || issueID <= 0 {
+		return services.ErrIssueNotFound
+	}
+
+	issue, err := h.Maintenance.UpdateIssueStatus(c.UserContext(), actorOf(c), uint(issueID), body.Status, body.StaffNote)
+	if err != nil {
+		return apperr.Wrap(err, "Could not update issue")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   issue,
+	})
+}
+
+// SetTVService marks a TV as out of service (or back in service). Taking a TV out
+// of service cancels its upcoming bookings and notifies the affected borrowers.
+func (h *Handler) SetTVService(c *fiber.Ctx) error {
+	var body models.TVServiceBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	tvID, err := c.ParamsInt("tvId")
+	if err != nil {
+		return services.ErrTVNotFound
+	}
+
+	cancelled, err := h.Maintenance.SetTVService(c.UserContext(), actorOf(c), tvID, body.OutOfService, body.Reason)
+	if err != nil {
+		return apperr.Wrap(err, "Could not update TV service status")
+	}
+	h.Metrics.ReservationsCancelled.WithLabelValues("tv_out_of_service").Add(float64(cancelled))
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data: fiber.Map{
+			"tvId":                  tvID,
+			"outOfService":          body.OutOfService,
+			"cancelledReservations": cancelled,
+		},
+	})
+}
+
+// savePhoto menyimpan foto laporan ke UploadDir dan mengembalikan nama filenya.
+func (h *Handler) savePhoto(c *fiber.Ctx, file *multipart.FileHeader, tvID int) (string, error) {
+	if file.Size > maxPhotoSize {
+		return "", apperr.Field("photo", "Photo must not exceed 5 MB")
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return "", apperr.Wrap(err, "Could not read photo")
+	}
+	defer f.Close()
+	// DetectContentType hanya membaca 512 byte pertama
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", apperr.Wrap(err, "Could not read photo")
+	}
+	ext, ok := photoExtensions[http.DetectContentType(head[:n])]
+	if !ok {
+		return "", apperr.Field("photo", "Photo must be a JPEG, PNG or WebP image")
+	}
+
+	if err := os.MkdirAll(h.UploadDir, 0o755); err != nil {
+		return "", apperr.Wrap(err, "Could not store photo")
+	}
+	filename := fmt.Sprintf("tv%d-%d%s", tvID, time.Now().UnixNano(), ext)
+	if err := c.SaveFile(file, filepath.Join(h.UploadDir, filename)); err != nil {
+		return "", apperr.Wrap(err, "Could not store photo")
+	}
+	return filename, nil
+}
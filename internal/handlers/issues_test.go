@@ -0,0 +1,131 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"playcorner-be/internal/handlers"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"playcorner-be/internal/repository/memory"
+	"playcorner-be/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Awal file yang cukup untuk dikenali http.DetectContentType.
+var (
+	pngPhoto  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpegPhoto = []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+	webpPhoto = []byte("RIFF\x00\x00\x00\x00WEBPVP8 ")
+	htmlPhoto = []byte("<html><script>alert(document.cookie)</script></html>")
+)
+
+func TestReportIssuePhoto(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		filename    string
+		contentType string
+		data        []byte
+		wantStatus  int
+		// wantExt adalah ekstensi file yang tersimpan; kosong berarti tidak ada file.
+		wantExt string
+	}{
+		{name: "stores a PNG", description: "Broken HDMI", filename: "tv.png", contentType: "image/png", data: pngPhoto, wantStatus: 201, wantExt: ".png"},
+		{name: "stores a JPEG", description: "Broken HDMI", filename: "tv.jpeg", contentType: "image/jpeg", data: jpegPhoto, wantStatus: 201, wantExt: ".jpg"},
+		{name: "stores a WebP", description: "Broken HDMI", filename: "tv.webp", contentType: "image/webp", data: webpPhoto, wantStatus: 201, wantExt: ".webp"},
+		{
+			name: "takes the extension from the content", description: "Broken HDMI",
+			filename: "tv.html", contentType: "text/html", data: pngPhoto, wantStatus: 201, wantExt: ".png",
+		},
+		{
+			name: "rejects HTML disguised as an image", description: "Broken HDMI",
+			filename: "tv.png", contentType: "image/png", data: htmlPhoto, wantStatus: 422,
+		},
+		{
+			name: "rejects an image type that is not allowed", description: "Broken HDMI",
+			filename: "tv.gif", contentType: "image/gif", data: []byte("GIF89a\x01\x00\x01\x00"), wantStatus: 422,
+		},
+		{
+			name: "leaves no file when the description is invalid", description: "   ",
+			filename: "tv.png", contentType: "image/png", data: pngPhoto, wantStatus: 422,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := memory.New()
+			store.AddTV(models.TVInfo{ID: 1, ConsoleType: "PS5"})
+			uploadDir := t.TempDir()
+			h := handlers.New(handlers.Dependencies{
+				Maintenance: services.NewMaintenanceService(store),
+				UploadDir:   uploadDir,
+			})
+			app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+			app.Post("/tvs/:tvId/issues", func(c *fiber.Ctx) error {
+				c.Locals("userID", "u1")
+				return c.Next()
+			}, h.ReportIssue)
+
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			if err := form.WriteField("description", tt.description); err != nil {
+				t.Fatal(err)
+			}
+			part, err := form.CreatePart(map[string][]string{
+				"Content-Disposition": {`form-data; name="photo"; filename="` + tt.filename + `"`},
+				"Content-Type":        {tt.contentType},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := part.Write(tt.data); err != nil {
+				t.Fatal(err)
+			}
+			if err := form.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(fiber.MethodPost, "/tvs/1/issues", &body)
+			req.Header.Set(fiber.HeaderContentType, form.FormDataContentType())
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			respBody, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.wantStatus, respBody)
+			}
+
+			files, err := os.ReadDir(uploadDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantExt == "" {
+				if len(files) != 0 {
+					t.Fatalf("upload dir has %d files, want none", len(files))
+				}
+				return
+			}
+			if len(files) != 1 || filepath.Ext(files[0].Name()) != tt.wantExt {
+				t.Fatalf("upload dir = %v, want one %s file", files, tt.wantExt)
+			}
+			issues, err := store.Issues().List(context.Background(), repository.IssueFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(issues) != 1 || !strings.HasSuffix(issues[0].PhotoURL, files[0].Name()) {
+				t.Fatalf("issues = %+v, want one issue pointing at %s", issues, files[0].Name())
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/logging"
+	"playcorner-be/internal/middleware"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+	"reflect"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// kioskRefreshMax adalah jeda terlama antara dua pemeriksaan keadaan TV,
+	// untuk perubahan yang tidak memicu event ketersediaan (misalnya no-show).
+	kioskRefreshMax = time.Minute
+	// kioskPingInterval adalah jeda ping WebSocket sekaligus pemeriksaan ulang token perangkat.
+	kioskPingInterval = 30 * time.Second
+	kioskWriteTimeout = 10 * time.Second
+)
+
+// Jenis pesan yang dikirim ke layar kiosk.
+const (
+	kioskSnapshot      = "snapshot"
+	kioskTVChanged     = "tv"
+	kioskSessionEnding = "session_ending"
+)
+
+// kioskMessage adalah amplop setiap pesan WebSocket kiosk. ServerTime dipakai
+// layar untuk menghitung sisa waktu tanpa bergantung pada jam perangkat.
+type kioskMessage struct {
+	Type       string    `json:"type"`
+	ServerTime time.Time `json:"serverTime"`
+	Data       any       `json:"data"`
+}
+
+// sessionEnding adalah isi pesan session_ending.
+type sessionEnding struct {
+	TVID int `json:"tvId"`
+	models.KioskSession
+}
+
+// KioskSocket streams the board of the device's location to a kiosk display over a WebSocket
+func (h *Handler) KioskSocket(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+
+	kiosk := &kioskConn{
+		h:          h,
+		deviceID:   c.Locals("deviceID").(uint),
+		locationID: c.Locals("locationID").(int),
+		logger:     logging.ForRequest(c),
+		warned:     map[uint]time.Time{},
+	}
+	// Hanya KioskSubprotocol yang dipilih, sehingga token perangkat yang ikut
+	// ditawarkan sebagai subprotokol tidak dikembalikan ke klien
+	return websocket.New(kiosk.run, websocket.Config{
+		Subprotocols: []string{middleware.KioskSubprotocol},
+	})(c)
+}
+
+// kioskConn adalah satu layar kiosk yang tersambung.
+type kioskConn struct {
+	h          *Handler
+	deviceID   uint
+	locationID int
+	logger     *slog.Logger
+
+	conn  *websocket.Conn
+	board map[int]models.KioskTV
+	// warned mencatat akhir sesi yang sudah diberi peringatan per reservasi,
+	// sehingga sesi yang diperpanjang mendapat peringatan baru.
+	warned map[uint]time.Time
+}
+
+// run mengirim snapshot lengkap saat tersambung, lalu hanya TV yang berubah.
+// Keadaan dihitung ulang saat ada event ketersediaan di lokasi ini dan pada
+// setiap batas sesi, sehingga peringatan session_ending dikirim tepat
+// services.SessionEndingWarning sebelum sesi berakhir.
+func (k *kioskConn) run(conn *websocket.Conn) {
+	k.conn = conn
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Berlangganan sebelum snapshot agar perubahan di antaranya tidak terlewat
+	sub := k.h.Availability.Subscribe(&k.locationID)
+	defer sub.Close()
+
+	// Pesan dari layar tidak dipakai; pembacaan hanya untuk mendeteksi koneksi putus
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	board, err := k.h.Kiosk.Board(ctx, k.locationID)
+	if err != nil {
+		k.logger.Error("could not build kiosk board", "error", err)
+		k.close(websocket.CloseInternalServerErr, "Could not load board")
+		return
+	}
+	if !k.send(kioskSnapshot, board) {
+		return
+	}
+	k.remember(board)
+	if !k.warn(board) {
+		return
+	}
+
+	refresh := time.NewTimer(k.nextRefresh(board))
+	defer refresh.Stop()
+	ping := time.NewTicker(kioskPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case _, ok := <-sub.Events():
+			if !ok {
+				// Hub berhenti atau layar tertinggal; layar tersambung kembali dan menerima snapshot baru
+				k.close(websocket.CloseGoingAway, "Reconnect for a fresh snapshot")
+				return
+			}
+		case <-refresh.C:
+		case <-ping.C:
+			active, err := k.h.Devices.Active(ctx, k.deviceID)
+			if err == nil && !active {
+				k.close(websocket.ClosePolicyViolation, "Device token revoked")
+				return
+			}
+			if conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(kioskWriteTimeout)) != nil {
+				return
+			}
+			continue
+		}
+
+		board, err := k.h.Kiosk.Board(ctx, k.locationID)
+		if err != nil {
+			k.logger.Error("could not build kiosk board", "error", err)
+			refresh.Reset(kioskRefreshMax)
+			continue
+		}
+		if !k.sendChanges(board) || !k.warn(board) {
+			return
+		}
+		refresh.Reset(k.nextRefresh(board))
+	}
+}
+
+// sendChanges mengirim TV yang keadaannya berbeda dari kiriman sebelumnya.
+func (k *kioskConn) sendChanges(board *models.KioskBoard) bool {
+	for _, tv := range board.TVs {
+		if prev, ok := k.board[tv.ID]; ok && reflect.DeepEqual(prev, tv) {
+			continue
+		}
+		if !k.send(kioskTVChanged, tv) {
+			return false
+		}
+	}
+	k.remember(board)
+	return true
+}
+
+func (k *kioskConn) remember(board *models.KioskBoard) {
+	k.board = make(map[int]models.KioskTV, len(board.TVs))
+	for _, tv := range board.TVs {
+		k.board[tv.ID] = tv
+	}
+}
+
+// warn mengirim session_ending untuk sesi yang berakhir dalam SessionEndingWarning.
+func (k *kioskConn) warn(board *models.KioskBoard) bool {
+	now := k.h.Kiosk.Now()
+	for _, tv := range board.TVs {
+		current := tv.Current
+		if current == nil || now.Before(current.EndsAt.Add(-services.SessionEndingWarning)) {
+			continue
+		}
+		if k.warned[current.ReservationID].Equal(current.EndsAt) {
+			continue
+		}
+		if !k.send(kioskSessionEnding, sessionEnding{TVID: tv.ID, KioskSession: *current}) {
+			return false
+		}
+		k.warned[current.ReservationID] = current.EndsAt
+	}
+	return true
+}
+
+// nextRefresh menghitung jeda sampai batas sesi berikutnya: saat peringatan
+// jatuh tempo, saat sesi berakhir, atau saat booking berikutnya dimulai.
+func (k *kioskConn) nextRefresh(board *models.KioskBoard) time.Duration {
+	now := k.h.Kiosk.Now()
+	next := now.Add(kioskRefreshMax)
+	consider := func(t time.Time) {
+		if t.After(now) && t.Before(next) {
+			next = t
+		}
+	}
+	for _, tv := range board.TVs {
+		if tv.Current != nil {
+			consider(tv.Current.EndsAt.Add(-services.SessionEndingWarning))
+			consider(tv.Current.EndsAt)
+		}
+		if tv.Next != nil {
+			consider(tv.Next.StartsAt)
+		}
+	}
+	// Sedikit lewat batas agar perhitungan ulang sudah melihat keadaan barunya
+	return next.Sub(now) + 100*time.Millisecond
+}
+
+func (k *kioskConn) send(kind string, data any) bool {
+	_ = k.conn.SetWriteDeadline(time.Now().Add(kioskWriteTimeout))
+	err := k.conn.WriteJSON(kioskMessage{Type: kind, ServerTime: k.h.Kiosk.Now().UTC(), Data: data})
+	return err == nil
+}
+
+func (k *kioskConn) close(code int, reason string) {
+	_ = k.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(kioskWriteTimeout))
+}
+
+// RegisterDevice registers a kiosk device for a location and returns its token once (admin only)
+func (h *Handler) RegisterDevice(c *fiber.Ctx) error {
+	var body models.DeviceBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	device, err := h.Devices.Register(c.UserContext(), body.Name, body.LocationID)
+	if err != nil {
+		return apperr.Wrap(err, "Could not register device")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Code:   201,
+		Status: "CREATED",
+		Data:   device,
+	})
+}
+
+// GetDevices lists registered kiosk devices (admin only)
+func (h *Handler) GetDevices(c *fiber.Ctx) error {
+	devices, err := h.Devices.List(c.UserContext())
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch devices")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   devices,
+	})
+}
+
+// RevokeDevice revokes a kiosk device's token and disconnects it (admin only)
+func (h *Handler) RevokeDevice(c *fiber.Ctx) error {
+	deviceID, err := c.ParamsInt("deviceId")
+	if err != nil || deviceID <= 0 {
+		return services.ErrDeviceNotFound
+	}
+
+	if err := h.Devices.Revoke(c.UserContext(), uint(deviceID)); err != nil {
+		return apperr.Wrap(err, "Could not revoke device")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   nil,
+	})
+}
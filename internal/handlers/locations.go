@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetLocations retrieves all PlayCorner locations
+func (h *Handler) GetLocations(c *fiber.Ctx) error {
+	locations, err := h.Locations.List(c.UserContext())
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch locations")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   locations,
+	})
+}
+
+// CreateLocation registers a new location (admin only)
+func (h *Handler) CreateLocation(c *fiber.Ctx) error {
+	var body models.LocationBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	location, err := h.Locations.Create(c.UserContext(), services.LocationInput(body))
+	if err != nil {
+		return apperr.Wrap(err, "Could not create location")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Code:   201,
+		Status: "CREATED",
+		Data:   location,
+	})
+}
+
+// UpdateLocation changes a location's details and opening hours (admin only)
+func (h *Handler) UpdateLocation(c *fiber.Ctx) error {
+	locationID, err := c.ParamsInt("locationId")
+	if err != nil {
+		return services.ErrLocationNotFound
+	}
+
+	location, err := h.Locations.Get(c.UserContext(), locationID)
+	if err != nil {
+		return apperr.Wrap(err, "Database error")
+	}
+
+	// Field yang tidak dikirim tetap memakai nilai lama
+	body := models.LocationBody{
+		Name:      location.Name,
+		Building:  location.Building,
+		Timezone:  location.Timezone,
+		OpenHour:  location.OpenHour,
+		CloseHour: location.CloseHour,
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	location, err = h.Locations.Update(c.UserContext(), locationID, services.LocationInput(body))
+	if err != nil {
+		return apperr.Wrap(err, "Could not update location")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   location,
+	})
+}
+
+// UpdateBookingPolicy sets a location's booking limits (staff of the location or admin)
+func (h *Handler) UpdateBookingPolicy(c *fiber.Ctx) error {
+	locationID, err := c.ParamsInt("locationId")
+	if err != nil {
+		return services.ErrLocationNotFound
+	}
+
+	var body models.BookingPolicyBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	location, err := h.Locations.SetBookingPolicy(c.UserContext(), actorOf(c), locationID, body.MaxConsecutiveHours)
+	if err != nil {
+		return apperr.Wrap(err, "Could not update booking policy")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   location,
+	})
+}
+
+// AssignStaff grants a staff member management rights on a location (admin only)
+func (h *Handler) AssignStaff(c *fiber.Ctx) error {
+	locationID, err := c.ParamsInt("locationId")
+	if err != nil {
+		return services.ErrLocationNotFound
+	}
+
+	assignment, err := h.Locations.AssignStaff(c.UserContext(), locationID, c.Params("userId"))
+	if err != nil {
+		return apperr.Wrap(err, "Could not assign staff")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   assignment,
+	})
+}
+
+// UnassignStaff revokes a staff member's rights on a location (admin only)
+func (h *Handler) UnassignStaff(c *fiber.Ctx) error {
+	locationID, err := c.ParamsInt("locationId")
+	if err != nil {
+		return services.ErrLocationNotFound
+	}
+
+	if err := h.Locations.UnassignStaff(c.UserContext(), locationID, c.Params("userId")); err != nil {
+		return apperr.Wrap(err, "Could not unassign staff")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   nil,
+	})
+}
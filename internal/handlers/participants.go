@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// InviteParticipant lets the borrower invite another student to play in their reservation
+func (h *Handler) InviteParticipant(c *fiber.Ctx) error {
+	var body models.InvitationBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	reservationID, err := c.ParamsInt("reservationId")
+	if err != nil || reservationID <= 0 {
+		return services.ErrReservationNotFound
+	}
+
+	participant, err := h.Booking.InviteParticipant(c.UserContext(), actorOf(c).UserID, uint(reservationID), body.UserID)
+	if err != nil {
+		return apperr.Wrap(err, "Could not invite player")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Code:   201,
+		Status: "CREATED",
+		Data:   participant,
+	})
+}
+
+// GetParticipants retrieves the invited players of a reservation
+func (h *Handler) GetParticipants(c *fiber.Ctx) error {
+	reservationID, err := c.ParamsInt("reservationId")
+	if err != nil || reservationID <= 0 {
+		return services.ErrReservationNotFound
+	}
+
+	participants, err := h.Booking.Participants(c.UserContext(), actorOf(c), uint(reservationID))
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch players")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   participants,
+	})
+}
+
+// RemoveParticipant withdraws an invitation, or lets an invited player leave the reservation
+func (h *Handler) RemoveParticipant(c *fiber.Ctx) error {
+	reservationID, err := c.ParamsInt("reservationId")
+	if err != nil || reservationID <= 0 {
+		return services.ErrReservationNotFound
+	}
+
+	err = h.Booking.RemoveParticipant(c.UserContext(), actorOf(c).UserID, uint(reservationID), c.Params("userId"))
+	if err != nil {
+		return apperr.Wrap(err, "Could not remove player")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   nil,
+	})
+}
+
+// GetInvitations retrieves the authenticated user's unanswered invitations
+func (h *Handler) GetInvitations(c *fiber.Ctx) error {
+	invitations, err := h.Booking.Invitations(c.UserContext(), actorOf(c).UserID)
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch invitations")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   invitations,
+	})
+}
+
+// AcceptInvitation joins the authenticated user to the reservation they were invited to
+func (h *Handler) AcceptInvitation(c *fiber.Ctx) error {
+	return h.respondInvitation(c, true)
+}
+
+// DeclineInvitation declines an invitation to play in a reservation
+func (h *Handler) DeclineInvitation(c *fiber.Ctx) error {
+	return h.respondInvitation(c, false)
+}
+
+func (h *Handler) respondInvitation(c *fiber.Ctx, accept bool) error {
+	invitationID, err := c.ParamsInt("invitationId")
+	if err != nil || invitationID <= 0 {
+		return services.ErrInvitationNotFound
+	}
+
+	participant, err := h.Booking.RespondInvitation(c.UserContext(), actorOf(c).UserID, uint(invitationID), accept)
+	if err != nil {
+		return apperr.Wrap(err, "Could not answer invitation")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   participant,
+	})
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// seriesID membaca :seriesId dari path.
+func seriesID(c *fiber.Ctx) (uint, error) {
+	id, err := c.ParamsInt("seriesId")
+	if err != nil || id <= 0 {
+		return 0, services.ErrSeriesNotFound
+	}
+	return uint(id), nil
+}
+
+// seriesResult adalah body respons perubahan series beserta slot yang bentrok.
+func seriesResult(series *models.ReservationSeries, conflicts []models.SeriesConflict) fiber.Map {
+	return fiber.Map{"series": series, "conflicts": conflicts}
+}
+
+// CreateSeries submits a recurring booking for staff approval and reports conflicting slots
+func (h *Handler) CreateSeries(c *fiber.Ctx) error {
+	var body models.SeriesBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	series, conflicts, err := h.Booking.CreateSeries(c.UserContext(), actorOf(c), body)
+	if err != nil {
+		return apperr.Wrap(err, "Could not create series")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Code:   201,
+		Status: "CREATED",
+		Data:   seriesResult(series, conflicts),
+	})
+}
+
+// GetSeriesList retrieves series the user organizes, or series at the locations a staff member manages
+func (h *Handler) GetSeriesList(c *fiber.Ctx) error {
+	series, err := h.Booking.ListSeries(c.UserContext(), actorOf(c), c.Query("status"))
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch series")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   series,
+	})
+}
+
+// GetSeries retrieves a series with its reservations and upcoming conflicts
+func (h *Handler) GetSeries(c *fiber.Ctx) error {
+	id, err := seriesID(c)
+	if err != nil {
+		return err
+	}
+
+	detail, err := h.Booking.GetSeries(c.UserContext(), actorOf(c), id)
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch series")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   detail,
+	})
+}
+
+// ApproveSeries approves a pending series and books its upcoming occurrences
+func (h *Handler) ApproveSeries(c *fiber.Ctx) error {
+	var body models.SeriesApprovalBody
+	if len(c.Body()) > 0 {
+		if err := parseBody(c, &body); err != nil {
+			return err
+		}
+	}
+
+	id, err := seriesID(c)
+	if err != nil {
+		return err
+	}
+
+	series, conflicts, err := h.Booking.ApproveSeries(c.UserContext(), actorOf(c), id, body.SkipConflicts)
+	if err != nil {
+		return apperr.Wrap(err, "Could not approve series")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   seriesResult(series, conflicts),
+	})
+}
+
+// RejectSeries rejects a pending series
+func (h *Handler) RejectSeries(c *fiber.Ctx) error {
+	id, err := seriesID(c)
+	if err != nil {
+		return err
+	}
+
+	series, err := h.Booking.RejectSeries(c.UserContext(), actorOf(c), id)
+	if err != nil {
+		return apperr.Wrap(err, "Could not reject series")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   series,
+	})
+}
+
+// UpdateSeries changes the rule of a whole series and reschedules its upcoming occurrences
+func (h *Handler) UpdateSeries(c *fiber.Ctx) error {
+	var body models.SeriesUpdateBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	id, err := seriesID(c)
+	if err != nil {
+		return err
+	}
+
+	series, conflicts, err := h.Booking.UpdateSeries(c.UserContext(), actorOf(c), id, body)
+	if err != nil {
+		return apperr.Wrap(err, "Could not update series")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   seriesResult(series, conflicts),
+	})
+}
+
+// UpdateSeriesOccurrence cancels or moves a single occurrence of a series
+func (h *Handler) UpdateSeriesOccurrence(c *fiber.Ctx) error {
+	var body models.OccurrenceBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	id, err := seriesID(c)
+	if err != nil {
+		return err
+	}
+	// Params menunjuk ke buffer request yang dipakai ulang fiber, sedangkan tanggal ini ikut disimpan
+	date := utils.CopyString(c.Params("date"))
+	if _, err := time.Parse(time.DateOnly, date); err != nil {
+		return apperr.Field("date", "date must be a date (YYYY-MM-DD)")
+	}
+
+	series, conflicts, err := h.Booking.UpdateOccurrence(c.UserContext(), actorOf(c), id, date, body)
+	if err != nil {
+		return apperr.Wrap(err, "Could not update occurrence")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   seriesResult(series, conflicts),
+	})
+}
+
+// CancelSeries cancels a series and releases its upcoming occurrences
+func (h *Handler) CancelSeries(c *fiber.Ctx) error {
+	id, err := seriesID(c)
+	if err != nil {
+		return err
+	}
+
+	series, err := h.Booking.CancelSeries(c.UserContext(), actorOf(c), id)
+	if err != nil {
+		return apperr.Wrap(err, "Could not cancel series")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   series,
+	})
+}
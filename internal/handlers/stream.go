@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// streamHeartbeat menjaga koneksi SSE tetap terbuka melewati proxy dan
+	// mendeteksi klien yang sudah pergi.
+	streamHeartbeat = 15 * time.Second
+	// streamRetry adalah jeda (ms) yang disarankan ke EventSource sebelum tersambung kembali.
+	streamRetry = 3000
+)
+
+// StreamAvailability streams slot availability changes as Server-Sent Events, optionally filtered by location
+func (h *Handler) StreamAvailability(c *fiber.Ctx) error {
+	locationID := optionalIntQuery(c, "locationId")
+	// EventSource mengirim Last-Event-ID sendiri saat tersambung kembali;
+	// query lastEventId dipakai klien yang ingin melanjutkan dari awal koneksi
+	lastID, _ := strconv.ParseInt(c.Get("Last-Event-ID", c.Query("lastEventId")), 10, 64)
+
+	// Berlangganan sebelum memutar ulang agar tidak ada event yang terlewat di antaranya.
+	// Koneksi pertama tanpa Last-Event-ID hanya menerima event baru
+	sub := h.Availability.Subscribe(locationID)
+	var backlog []models.AvailabilityEvent
+	complete := true
+	if lastID > 0 {
+		var err error
+		backlog, complete, err = h.Availability.Since(c.UserContext(), lastID, locationID)
+		if err != nil {
+			sub.Close()
+			return apperr.Wrap(err, "Could not fetch availability events")
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	// Nginx tidak boleh menahan event di buffer
+	c.Set("X-Accel-Buffering", "no")
+
+	conn := c.Context().Conn()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+
+		flush := func() bool {
+			// Batas waktu tulis server berlaku sekali per respons, jadi diperpanjang tiap kali menulis
+			_ = conn.SetWriteDeadline(time.Now().Add(2 * streamHeartbeat))
+			return w.Flush() == nil
+		}
+
+		fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+		if !complete {
+			// Sebagian event sudah tidak tersedia; klien harus memuat ulang GET /api/availability
+			fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+		}
+		replayed := make(map[int64]bool, len(backlog))
+		for _, e := range backlog {
+			writeEvent(w, e)
+			replayed[e.ID] = true
+		}
+		if !flush() {
+			return
+		}
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case e, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+				// Event yang sudah terkirim lewat pemutaran ulang dilewati. ID tidak
+				// dibandingkan dengan urutan karena transaksi bisa commit tidak berurutan
+				if replayed[e.ID] {
+					continue
+				}
+				writeEvent(w, e)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+			if !flush() {
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// writeEvent menulis satu event ketersediaan dalam format SSE.
+func writeEvent(w *bufio.Writer, e models.AvailabilityEvent) {
+	data, _ := json.Marshal(e)
+	fmt.Fprintf(w, "id: %d\nevent: availability\ndata: %s\n\n", e.ID, data)
+}
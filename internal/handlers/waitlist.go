@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JoinWaitlist queues the authenticated user for a fully booked slot on a TV, or on any TV of the same console type
+func (h *Handler) JoinWaitlist(c *fiber.Ctx) error {
+	var body models.WaitlistBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+
+	tvID, err := c.ParamsInt("tvId")
+	if err != nil {
+		return services.ErrTVNotFound
+	}
+
+	entry, err := h.Booking.JoinWaitlist(c.UserContext(), actorOf(c).UserID, tvID, body.Timeslot, body.AnyTV)
+	if err != nil {
+		return apperr.Wrap(err, "Could not join waitlist")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Code:   201,
+		Status: "CREATED",
+		Data:   entry,
+	})
+}
+
+// GetWaitlist retrieves the authenticated user's waiting and offered waitlist entries
+func (h *Handler) GetWaitlist(c *fiber.Ctx) error {
+	entries, err := h.Booking.Waitlist(c.UserContext(), actorOf(c).UserID)
+	if err != nil {
+		return apperr.Wrap(err, "Could not fetch waitlist")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   entries,
+	})
+}
+
+// AcceptWaitlistOffer books the slot offered to the authenticated user from the waitlist
+func (h *Handler) AcceptWaitlistOffer(c *fiber.Ctx) error {
+	entryID, err := c.ParamsInt("entryId")
+	if err != nil || entryID <= 0 {
+		return services.ErrWaitlistNotFound
+	}
+
+	reservation, err := h.Booking.AcceptOffer(c.UserContext(), actorOf(c).UserID, uint(entryID))
+	if err != nil {
+		return apperr.Wrap(err, "Could not accept waitlist offer")
+	}
+	h.Metrics.ReservationsCreated.Inc()
+
+	return c.Status(fiber.StatusCreated).JSON(models.Response{
+		Code:   201,
+		Status: "CREATED",
+		Data: fiber.Map{
+			"reservationId": reservation.ID,
+			"tvId":          reservation.TVID,
+			"timeslot":      reservation.TimeSlot,
+			"status":        reservation.Status,
+		},
+	})
+}
+
+// LeaveWaitlist removes the authenticated user from a waitlist, passing any pending offer to the next person
+func (h *Handler) LeaveWaitlist(c *fiber.Ctx) error {
+	entryID, err := c.ParamsInt("entryId")
+	if err != nil || entryID <= 0 {
+		return services.ErrWaitlistNotFound
+	}
+
+	if err := h.Booking.LeaveWaitlist(c.UserContext(), actorOf(c).UserID, uint(entryID)); err != nil {
+		return apperr.Wrap(err, "Could not leave waitlist")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{
+		Code:   200,
+		Status: "OK",
+		Data:   nil,
+	})
+}
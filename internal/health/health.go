@@ -0,0 +1,101 @@
+// Package health
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status sebuah pemeriksaan atau laporan secara keseluruhan.
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Check memeriksa satu dependensi dan mengembalikan error jika tidak siap.
+type Check func(ctx context.Context) error
+
+// CheckResult adalah hasil satu pemeriksaan.
+type CheckResult struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report adalah isi respons /readyz.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker menjalankan semua pemeriksaan kesiapan secara paralel dan menyimpan
+// status shutdown agar readiness langsung gagal saat server sedang berhenti.
+type Checker struct {
+	checks       []namedCheck
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+// NewChecker membuat Checker dengan batas waktu untuk setiap pemeriksaan.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add mendaftarkan pemeriksaan baru. Harus dipanggil sebelum server menerima request.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// SetShuttingDown membuat readiness gagal sejak graceful shutdown dimulai.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready menjalankan semua pemeriksaan dan mengembalikan laporannya.
+func (c *Checker) Ready(ctx context.Context) Report {
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(c.checks)+1)}
+
+	if c.shuttingDown.Load() {
+		report.Status = StatusUnavailable
+		report.Checks["shutdown"] = CheckResult{Status: StatusUnavailable, Error: "server is shutting down"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func(nc namedCheck) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := nc.check(ctx)
+			result := CheckResult{Status: StatusOK, LatencyMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				result.Status = StatusUnavailable
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[nc.name] = result
+			if err != nil {
+				report.Status = StatusUnavailable
+			}
+		}(nc)
+	}
+	wg.Wait()
+
+	return report
+}
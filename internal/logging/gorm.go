@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// GormLogger meneruskan log GORM ke slog. Query yang gagal dicatat sebagai
+// error, query yang melebihi SlowThreshold sebagai warning, dan query lain
+// hanya pada level debug. Nilai parameter query tidak pernah dicatat.
+type GormLogger struct {
+	SlowThreshold time.Duration
+}
+
+var (
+	_ logger.Interface  = GormLogger{}
+	_ gorm.ParamsFilter = GormLogger{}
+)
+
+// NewGormLogger membuat GormLogger dengan ambang batas query lambat.
+func NewGormLogger(slowThreshold time.Duration) GormLogger {
+	return GormLogger{SlowThreshold: slowThreshold}
+}
+
+// LogMode diabaikan; level log ditentukan oleh logger slog.
+func (l GormLogger) LogMode(logger.LogLevel) logger.Interface { return l }
+
+func (l GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	FromContext(ctx).InfoContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	FromContext(ctx).WarnContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	FromContext(ctx).ErrorContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+// ParamsFilter membuang nilai parameter sehingga SQL yang dicatat hanya berisi
+// placeholder ($1, $2, ...), termasuk pada query pencarian password hash.
+func (l GormLogger) ParamsFilter(_ context.Context, sql string, _ ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
+
+func (l GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	log := FromContext(ctx)
+	elapsed := time.Since(begin)
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && !errors.Is(err, context.Canceled):
+		if !log.Enabled(ctx, slog.LevelError) {
+			return
+		}
+		sql, rows := fc()
+		log.ErrorContext(ctx, "query failed", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds(), "error", err)
+	case l.SlowThreshold > 0 && elapsed > l.SlowThreshold:
+		if !log.Enabled(ctx, slog.LevelWarn) {
+			return
+		}
+		sql, rows := fc()
+		log.WarnContext(ctx, "slow query", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds(), "threshold_ms", l.SlowThreshold.Milliseconds())
+	default:
+		if !log.Enabled(ctx, slog.LevelDebug) {
+			return
+		}
+		sql, rows := fc()
+		log.DebugContext(ctx, "query", "sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds())
+	}
+}
@@ -0,0 +1,64 @@
+// Package logging
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"playcorner-be/internal/config"
+	"strings"
+)
+
+// Setup membuat logger slog sesuai konfigurasi dan memasangnya sebagai logger
+// default, sehingga paket log standar juga ikut menulis dalam format yang sama.
+func Setup(cfg config.LogConfig) *slog.Logger {
+	logger := slog.New(newHandler(cfg, os.Stdout))
+	slog.SetDefault(logger)
+	return logger
+}
+
+func newHandler(cfg config.LogConfig, w io.Writer) slog.Handler {
+	opts := &slog.HandlerOptions{Level: ParseLevel(cfg.Level)}
+	if cfg.Format == "text" {
+		return slog.NewTextHandler(w, opts)
+	}
+	return slog.NewJSONHandler(w, opts)
+}
+
+// ParseLevel menerjemahkan debug/info/warn/error menjadi slog.Level (bawaan info).
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+type loggerKey struct{}
+
+// WithLogger menyimpan logger di context agar dapat dipakai lapisan di bawah handler.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext mengembalikan logger milik request, atau logger default jika tidak ada.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// Fatal mencatat kesalahan yang membuat aplikasi tidak dapat berjalan lalu keluar dengan kode 1.
+func Fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
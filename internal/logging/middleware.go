@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderRequestID adalah header yang dibaca dan dikembalikan oleh Middleware.
+const HeaderRequestID = "X-Request-ID"
+
+// Middleware memberi setiap request sebuah request ID (memakai X-Request-ID dari
+// klien atau Nginx jika valid), menyimpan logger per request di user context,
+// dan menulis satu baris access log setelah request selesai. clientIP menentukan
+// IP yang dicatat agar X-Forwarded-For hanya dipercaya dari proxy tepercaya.
+// Harus dipasang setelah middleware tracing agar trace ID ikut tercatat.
+func Middleware(base *slog.Logger, clientIP func(*fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		requestID := c.Get(HeaderRequestID)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		c.Set(HeaderRequestID, requestID)
+		c.Locals("requestID", requestID)
+
+		logger := base.With("request_id", requestID)
+		if sc := trace.SpanContextFromContext(c.UserContext()); sc.HasTraceID() {
+			logger = logger.With("trace_id", sc.TraceID().String())
+		}
+		c.SetUserContext(WithLogger(c.UserContext(), logger))
+
+		err := c.Next()
+
+		if err != nil {
+			// Respons error ditulis di sini agar status yang dicatat sama dengan
+			// yang diterima klien
+			if herr := c.App().ErrorHandler(c, err); herr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		status := c.Response().StatusCode()
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		// Logger diambil ulang dari context karena AuthMiddleware menambahkan user_id
+		FromContext(c.UserContext()).LogAttrs(c.UserContext(), level, "request completed",
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.String("route", c.Route().Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", len(c.Response().Body())),
+			slog.String("ip", clientIP(c)),
+		)
+		return nil
+	}
+}
+
+// ForRequest mengembalikan logger request yang sudah dilengkapi template rute.
+func ForRequest(c *fiber.Ctx) *slog.Logger {
+	return FromContext(c.UserContext()).With("method", c.Method(), "route", c.Route().Path)
+}
+
+// validRequestID menolak ID yang terlalu panjang atau berisi karakter di luar
+// [A-Za-z0-9._-] agar nilai dari klien tidak dapat merusak log.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,163 @@
+// Package metrics
+package metrics
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"playcorner-be/internal/apperr"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "playcorner"
+
+// Metrics menampung registry Prometheus aplikasi beserta semua metrik HTTP dan domain.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	ReservationsCreated   prometheus.Counter
+	ReservationsCancelled *prometheus.CounterVec
+	ReservationConflicts  prometheus.Counter
+	NoShows               prometheus.Counter
+	WalkIns               prometheus.Counter
+	SessionPlayedMinutes  prometheus.Histogram
+	LoginFailures         prometheus.Counter
+	TVUtilization         *prometheus.GaugeVec
+}
+
+// New membuat Metrics dengan registry sendiri, termasuk metrik proses dan runtime Go.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route template.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+
+		ReservationsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reservations_created_total",
+			Help:      "Reservations successfully created.",
+		}),
+		ReservationsCancelled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reservations_cancelled_total",
+			Help:      "Reservations cancelled, by reason.",
+		}, []string{"reason"}),
+		ReservationConflicts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reservation_conflicts_total",
+			Help:      "Reservation attempts rejected because the slot was already taken.",
+		}),
+		NoShows: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reservation_no_shows_total",
+			Help:      "Reservations marked as no-show after the check-in grace period.",
+		}),
+		WalkIns: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "walk_ins_total",
+			Help:      "Walk-in sessions started by staff on a free TV.",
+		}),
+		SessionPlayedMinutes: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "session_played_minutes",
+			Help:      "Actual play time of finished sessions, whether checked out early or run to the end of the slot.",
+			Buckets:   []float64{10, 20, 30, 40, 50, 60},
+		}),
+		LoginFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "login_failures_total",
+			Help:      "Login attempts rejected because of invalid credentials.",
+		}),
+		TVUtilization: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "tv_utilization_ratio",
+			Help:      "Share of in-service TVs with a checked-in session in the current slot, by location.",
+		}, []string{"location_id"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewGoCollector(),
+		m.httpRequests, m.httpDuration,
+		m.ReservationsCreated, m.ReservationsCancelled, m.ReservationConflicts,
+		m.NoShows, m.WalkIns, m.SessionPlayedMinutes, m.LoginFailures, m.TVUtilization,
+	)
+	return m
+}
+
+// RegisterDB menambahkan statistik pool koneksi database/sql.
+func (m *Metrics) RegisterDB(db *sql.DB) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// SetUtilization memperbarui gauge utilisasi TV untuk satu lokasi.
+func (m *Metrics) SetUtilization(locationID, inUse, inService int) {
+	ratio := 0.0
+	if inService > 0 {
+		ratio = float64(inUse) / float64(inService)
+	}
+	m.TVUtilization.WithLabelValues(strconv.Itoa(locationID)).Set(ratio)
+}
+
+// Middleware mencatat jumlah dan latensi request per template rute (misalnya
+// /api/tvs/:tvId/reservations), bukan per path, agar kardinalitas label tetap kecil.
+func (m *Metrics) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		route := c.Route().Path
+		if err != nil {
+			var fe *fiber.Error
+			if errors.As(err, &fe) && fe.Code == fiber.StatusNotFound {
+				// Request yang tidak cocok dengan rute mana pun dikelompokkan jadi satu
+				route = "unmatched"
+			}
+			// Tulis respons error sekarang agar status yang tercatat sama dengan
+			// yang diterima klien
+			if herr := c.App().ErrorHandler(c, err); herr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+			err = nil
+		}
+		status := c.Response().StatusCode()
+
+		m.httpRequests.WithLabelValues(c.Method(), route, strconv.Itoa(status)).Inc()
+		m.httpDuration.WithLabelValues(c.Method(), route).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// Handler menyajikan metrik dalam format Prometheus. Scraper wajib mengirim
+// token sebagai bearer token; request tanpa token yang cocok ditolak.
+func (m *Metrics) Handler(token string) fiber.Handler {
+	serve := adaptor.HTTPHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	expected := []byte("Bearer " + token)
+
+	return func(c *fiber.Ctx) error {
+		if subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), expected) != 1 {
+			return apperr.Unauthorized("INVALID_METRICS_TOKEN", "Invalid metrics token")
+		}
+		return serve(c)
+	}
+}
@@ -2,45 +2,88 @@
 package middleware
 
 import (
+	"context"
+	"playcorner-be/internal/apperr"
 	"playcorner-be/internal/auth"
+	"playcorner-be/internal/logging"
 	"playcorner-be/internal/models"
+	"slices"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func AuthMiddleware() fiber.Handler {
+// AuthMiddleware memvalidasi access token pada header Authorization menggunakan tokens.
+func AuthMiddleware(tokens *auth.Manager) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
-				Code:   401,
-				Status: "UNAUTHORIZED",
-				Data:   models.ErrorData{ErrorMsg: "Missing authorization header"},
-			})
+			return apperr.Unauthorized("MISSING_AUTH_HEADER", "Missing authorization header")
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
-				Code:   401,
-				Status: "UNAUTHORIZED",
-				Data:   models.ErrorData{ErrorMsg: "Invalid authorization header format"},
-			})
+			return apperr.Unauthorized("INVALID_AUTH_HEADER", "Invalid authorization header format")
 		}
 
 		tokenString := parts[1]
-		claims, err := auth.ValidateToken(tokenString)
+		claims, err := tokens.ValidateToken(tokenString)
 		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
-				Code:   401,
-				Status: "UNAUTHORIZED",
-				Data:   models.ErrorData{ErrorMsg: "Invalid or expired token"},
-			})
+			return apperr.Unauthorized("INVALID_TOKEN", "Invalid or expired token")
 		}
 
 		// Menyimpan user ID dari token ke dalam context untuk digunakan oleh handler selanjutnya
 		c.Locals("userID", claims.UserID)
+		c.SetUserContext(logging.WithLogger(c.UserContext(),
+			logging.FromContext(c.UserContext()).With("user_id", claims.UserID)))
+		return c.Next()
+	}
+}
+
+// RoleResolver mengambil peran seorang pengguna, diimplementasikan oleh services.AuthService.
+type RoleResolver interface {
+	Role(ctx context.Context, userID string) (string, error)
+}
+
+// StaffOnly membatasi akses rute hanya untuk pengguna dengan peran staff atau admin.
+// Harus dipasang setelah AuthMiddleware. Pembatasan per lokasi dilakukan di service.
+func StaffOnly(roles RoleResolver) fiber.Handler {
+	return requireRole(roles, apperr.Forbidden("STAFF_REQUIRED", "Staff access required"), models.RoleStaff, models.RoleAdmin)
+}
+
+// AdminOnly membatasi akses rute hanya untuk admin. Harus dipasang setelah AuthMiddleware.
+func AdminOnly(roles RoleResolver) fiber.Handler {
+	return requireRole(roles, apperr.Forbidden("ADMIN_REQUIRED", "Admin access required"), models.RoleAdmin)
+}
+
+// WithRole menyimpan peran pengguna tanpa membatasi akses, untuk rute yang
+// terbuka bagi semua pengguna tetapi memberi staff wewenang tambahan di lokasinya.
+// Harus dipasang setelah AuthMiddleware.
+func WithRole(roles RoleResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, _ := c.Locals("userID").(string)
+
+		role, err := roles.Role(c.UserContext(), userID)
+		if err != nil {
+			return apperr.Wrap(err, "Could not resolve user role")
+		}
+
+		c.Locals("role", role)
+		return c.Next()
+	}
+}
+
+func requireRole(roles RoleResolver, denied error, allowed ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, _ := c.Locals("userID").(string)
+
+		role, err := roles.Role(c.UserContext(), userID)
+		if err != nil || !slices.Contains(allowed, role) {
+			return denied
+		}
+
+		// Peran disimpan agar service dapat memeriksa cakupan lokasi staff
+		c.Locals("role", role)
 		return c.Next()
 	}
 }
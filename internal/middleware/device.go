@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/logging"
+	"playcorner-be/internal/models"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeviceAuthenticator memvalidasi token perangkat kiosk, diimplementasikan oleh services.DeviceService.
+type DeviceAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*models.Device, error)
+}
+
+// KioskSubprotocol adalah subprotokol WebSocket layar kiosk. Browser tidak dapat
+// mengatur header Authorization pada WebSocket, jadi token perangkat dikirim
+// sebagai subprotokol kedua: new WebSocket(url, [KioskSubprotocol, token]).
+// Server hanya membalas dengan KioskSubprotocol sehingga token tidak dikembalikan.
+const KioskSubprotocol = "playcorner.kiosk.v1"
+
+// DeviceAuth mengautentikasi perangkat kiosk dengan token perangkat, bukan JWT
+// pengguna. Token dibaca dari header Authorization: Bearer, atau dari header
+// Sec-WebSocket-Protocol (lihat KioskSubprotocol). Token tidak pernah dibaca
+// dari query string karena URL tercatat di trace dan log akses proxy.
+func DeviceAuth(devices DeviceAuthenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var token string
+		if header := c.Get("Authorization"); header != "" {
+			scheme, value, ok := strings.Cut(header, " ")
+			if !ok || scheme != "Bearer" {
+				return apperr.Unauthorized("INVALID_AUTH_HEADER", "Invalid authorization header format")
+			}
+			token = value
+		} else {
+			token = subprotocolToken(c.Get(fiber.HeaderSecWebSocketProtocol))
+		}
+		if token == "" {
+			return apperr.Unauthorized("MISSING_DEVICE_TOKEN", "Missing device token")
+		}
+
+		device, err := devices.Authenticate(c.UserContext(), token)
+		if err != nil {
+			return err
+		}
+
+		c.Locals("deviceID", device.ID)
+		c.Locals("locationID", device.LocationID)
+		c.SetUserContext(logging.WithLogger(c.UserContext(),
+			logging.FromContext(c.UserContext()).With("device_id", device.ID)))
+		return c.Next()
+	}
+}
+
+// subprotocolToken mengambil token perangkat dari daftar subprotokol WebSocket
+// yang ditawarkan klien. Token hanya diterima jika KioskSubprotocol juga ditawarkan.
+func subprotocolToken(header string) string {
+	var token string
+	kiosk := false
+	for _, protocol := range strings.Split(header, ",") {
+		switch protocol = strings.TrimSpace(protocol); protocol {
+		case "":
+		case KioskSubprotocol:
+			kiosk = true
+		default:
+			token = protocol
+		}
+	}
+	if !kiosk {
+		return ""
+	}
+	return token
+}
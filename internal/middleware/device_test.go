@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"playcorner-be/internal/handlers"
+	"playcorner-be/internal/middleware"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// devices menerima satu token perangkat yang valid.
+type devices struct{}
+
+func (devices) Authenticate(_ context.Context, token string) (*models.Device, error) {
+	if token != "pcd_valid" {
+		return nil, services.ErrInvalidDeviceToken
+	}
+	return &models.Device{ID: 7, LocationID: 1}, nil
+}
+
+func TestDeviceAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		header     map[string]string
+		wantStatus int
+	}{
+		{name: "accepts a bearer token", target: "/", header: map[string]string{"Authorization": "Bearer pcd_valid"}, wantStatus: 204},
+		{
+			name: "accepts a token offered as a WebSocket subprotocol", target: "/",
+			header:     map[string]string{"Sec-WebSocket-Protocol": middleware.KioskSubprotocol + ", pcd_valid"},
+			wantStatus: 204,
+		},
+		{
+			name: "ignores a subprotocol token without the kiosk subprotocol", target: "/",
+			header:     map[string]string{"Sec-WebSocket-Protocol": "pcd_valid"},
+			wantStatus: 401,
+		},
+		{name: "ignores a token in the query string", target: "/?token=pcd_valid", wantStatus: 401},
+		{name: "rejects an unknown token", target: "/", header: map[string]string{"Authorization": "Bearer pcd_other"}, wantStatus: 401},
+		{name: "rejects another scheme", target: "/", header: map[string]string{"Authorization": "Basic pcd_valid"}, wantStatus: 401},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+			app.Get("/", middleware.DeviceAuth(devices{}), func(c *fiber.Ctx) error {
+				if c.Locals("deviceID") != uint(7) {
+					t.Errorf("deviceID = %v, want 7", c.Locals("deviceID"))
+				}
+				return c.SendStatus(fiber.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, tt.target, nil)
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/logging"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// HeaderIdempotencyKey adalah header yang dibaca oleh Idempotency.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed ditambahkan pada respons yang diputar ulang.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// IdempotencyKeyTTL adalah lama sebuah Idempotency-Key diingat.
+	IdempotencyKeyTTL = 24 * time.Hour
+
+	maxIdempotencyKeyLength = 255
+)
+
+var (
+	errIdempotencyKeyTooLong = apperr.Field(HeaderIdempotencyKey, "Idempotency-Key must be at most 255 characters")
+	errIdempotencyKeyReused  = apperr.Validation("IDEMPOTENCY_KEY_REUSED",
+		"Idempotency-Key was already used for a different request")
+	errIdempotencyInProgress = apperr.Conflict("IDEMPOTENCY_IN_PROGRESS",
+		"A request with this Idempotency-Key is still being processed")
+)
+
+// Idempotency memutar ulang respons asli (status dan body) jika pengguna yang
+// sama mengirim ulang Idempotency-Key dalam IdempotencyKeyTTL, tanpa menjalankan
+// handler lagi. Kunci yang sama dengan body berbeda ditolak dengan 422. Request
+// tanpa header diproses seperti biasa. Harus dipasang setelah AuthMiddleware.
+func Idempotency(keys repository.IdempotencyKeyRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return errIdempotencyKeyTooLong
+		}
+
+		ctx := c.UserContext()
+		userID, _ := c.Locals("userID").(string)
+		now := time.Now()
+		record := &models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash(c),
+			CreatedAt:   now,
+		}
+
+		reserved, err := keys.Reserve(ctx, record, now.Add(-IdempotencyKeyTTL))
+		if err != nil {
+			return err
+		}
+		if !reserved {
+			return replay(c, keys, record)
+		}
+
+		defer func() {
+			// Handler yang panic tidak pernah menyelesaikan kunci, jadi kunci dilepas
+			// agar klien dapat mencoba lagi, lalu panic diteruskan ke middleware recover
+			if r := recover(); r != nil {
+				if err := keys.Delete(ctx, userID, key); err != nil {
+					logging.ForRequest(c).Error("could not release idempotency key", "error", err)
+				}
+				panic(r)
+			}
+		}()
+
+		if err := c.Next(); err != nil {
+			// Tulis respons error sekarang agar hasilnya dapat disimpan
+			if herr := c.App().ErrorHandler(c, err); herr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			// Kegagalan server tidak disimpan agar klien dapat mencoba lagi dengan kunci yang sama
+			err = keys.Delete(ctx, userID, key)
+		} else {
+			err = keys.Complete(ctx, userID, key, status, bytes.Clone(c.Response().Body()))
+		}
+		if err != nil {
+			logging.ForRequest(c).Error("could not store idempotency key", "error", err)
+		}
+		return nil
+	}
+}
+
+// replay mengembalikan respons yang tersimpan untuk kunci yang sudah dipakai.
+func replay(c *fiber.Ctx, keys repository.IdempotencyKeyRepository, record *models.IdempotencyKey) error {
+	existing, err := keys.Find(c.UserContext(), record.UserID, record.Key)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Request pertama gagal dan kuncinya baru saja dilepas
+		return errIdempotencyInProgress
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case existing.RequestHash != record.RequestHash:
+		return errIdempotencyKeyReused
+	case existing.StatusCode == 0:
+		return errIdempotencyInProgress
+	}
+
+	c.Set(HeaderIdempotentReplayed, "true")
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(existing.StatusCode).Send(existing.ResponseBody)
+}
+
+// requestHash mengidentifikasi request dari method, path, dan body-nya.
+func requestHash(c *fiber.Ctx) string {
+	h := sha256.New()
+	h.Write([]byte(c.Method() + " " + c.Path() + "\n"))
+	h.Write(c.Body())
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,202 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"playcorner-be/internal/handlers"
+	"playcorner-be/internal/middleware"
+	"playcorner-be/internal/repository/memory"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+)
+
+// idempotencyApp memasang Idempotency di depan handler yang menghitung
+// pemanggilannya dan membalas dengan status yang dapat diatur tes.
+func idempotencyApp(store *memory.Store, status *int, calls *int) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	app.Post("/reservations", func(c *fiber.Ctx) error {
+		// c.Get memakai buffer request yang dipakai ulang, jadi nilainya disalin
+		c.Locals("userID", strings.Clone(c.Get("X-User")))
+		return c.Next()
+	}, middleware.Idempotency(store.IdempotencyKeys()), func(c *fiber.Ctx) error {
+		*calls++
+		return c.Status(*status).JSON(fiber.Map{"call": *calls})
+	})
+	return app
+}
+
+type response struct {
+	status   int
+	body     string
+	replayed bool
+}
+
+func post(t *testing.T, app *fiber.App, user, key, body string) response {
+	t.Helper()
+	req := httptest.NewRequest(fiber.MethodPost, "/reservations", strings.NewReader(body))
+	req.Header.Set("X-User", user)
+	if key != "" {
+		req.Header.Set(middleware.HeaderIdempotencyKey, key)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response{
+		status:   resp.StatusCode,
+		body:     string(data),
+		replayed: resp.Header.Get(middleware.HeaderIdempotentReplayed) == "true",
+	}
+}
+
+func TestIdempotency(t *testing.T) {
+	type request struct {
+		user, key, body string
+		// Status yang dibalas handler jika request sampai ke handler.
+		handlerStatus int
+		want          response
+	}
+	tests := []struct {
+		name      string
+		requests  []request
+		wantCalls int
+	}{
+		{
+			name: "replays the first response for the same key and body",
+			requests: []request{
+				{user: "u1", key: "k1", body: `{"slot":1}`, handlerStatus: 201, want: response{status: 201, body: `{"call":1}`}},
+				{user: "u1", key: "k1", body: `{"slot":1}`, handlerStatus: 201, want: response{status: 201, body: `{"call":1}`, replayed: true}},
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "replays client errors too",
+			requests: []request{
+				{user: "u1", key: "k1", body: `{}`, handlerStatus: 409, want: response{status: 409, body: `{"call":1}`}},
+				{user: "u1", key: "k1", body: `{}`, handlerStatus: 201, want: response{status: 409, body: `{"call":1}`, replayed: true}},
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "rejects a reused key with a different body",
+			requests: []request{
+				{user: "u1", key: "k1", body: `{"slot":1}`, handlerStatus: 201, want: response{status: 201, body: `{"call":1}`}},
+				{user: "u1", key: "k1", body: `{"slot":2}`, handlerStatus: 201, want: response{status: 422}},
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "does not store server errors",
+			requests: []request{
+				{user: "u1", key: "k1", body: `{}`, handlerStatus: 503, want: response{status: 503, body: `{"call":1}`}},
+				{user: "u1", key: "k1", body: `{}`, handlerStatus: 201, want: response{status: 201, body: `{"call":2}`}},
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "scopes keys per user",
+			requests: []request{
+				{user: "u1", key: "k1", body: `{}`, handlerStatus: 201, want: response{status: 201, body: `{"call":1}`}},
+				{user: "u2", key: "k1", body: `{}`, handlerStatus: 201, want: response{status: 201, body: `{"call":2}`}},
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "passes requests without a key through",
+			requests: []request{
+				{user: "u1", body: `{}`, handlerStatus: 201, want: response{status: 201, body: `{"call":1}`}},
+				{user: "u1", body: `{}`, handlerStatus: 201, want: response{status: 201, body: `{"call":2}`}},
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "rejects an overlong key",
+			requests: []request{
+				{user: "u1", key: strings.Repeat("k", 256), body: `{}`, handlerStatus: 201, want: response{status: 422}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status, calls int
+			app := idempotencyApp(memory.New(), &status, &calls)
+			for i, req := range tt.requests {
+				status = req.handlerStatus
+				got := post(t, app, req.user, req.key, req.body)
+				if got.status != req.want.status || got.replayed != req.want.replayed {
+					t.Fatalf("request %d: status %d (replayed %v), want %d (replayed %v): %s",
+						i+1, got.status, got.replayed, req.want.status, req.want.replayed, got.body)
+				}
+				if req.want.body != "" && got.body != req.want.body {
+					t.Fatalf("request %d: body %s, want %s", i+1, got.body, req.want.body)
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Fatalf("handler ran %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	store := memory.New()
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	var nested response
+	calls := 0
+	app.Post("/reservations", func(c *fiber.Ctx) error {
+		c.Locals("userID", strings.Clone(c.Get("X-User")))
+		return c.Next()
+	}, middleware.Idempotency(store.IdempotencyKeys()), func(c *fiber.Ctx) error {
+		calls++
+		if calls == 1 {
+			// Request kedua tiba saat request pertama masih diproses
+			nested = post(t, app, "u1", "k1", `{}`)
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls})
+	})
+
+	if got := post(t, app, "u1", "k1", `{}`); got.status != fiber.StatusCreated {
+		t.Fatalf("first request: status %d: %s", got.status, got.body)
+	}
+	if nested.status != fiber.StatusConflict || !strings.Contains(nested.body, "IDEMPOTENCY_IN_PROGRESS") {
+		t.Fatalf("concurrent request: status %d: %s, want 409 IDEMPOTENCY_IN_PROGRESS", nested.status, nested.body)
+	}
+	if calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls)
+	}
+}
+
+func TestIdempotencyReleasesKeyOnPanic(t *testing.T) {
+	store := memory.New()
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	app.Use(recover.New())
+	calls := 0
+	app.Post("/reservations", func(c *fiber.Ctx) error {
+		c.Locals("userID", strings.Clone(c.Get("X-User")))
+		return c.Next()
+	}, middleware.Idempotency(store.IdempotencyKeys()), func(c *fiber.Ctx) error {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls})
+	})
+
+	if got := post(t, app, "u1", "k1", `{}`); got.status != fiber.StatusInternalServerError {
+		t.Fatalf("panicking request: status %d: %s, want 500", got.status, got.body)
+	}
+	// Kunci dilepas, sehingga percobaan ulang dijalankan alih-alih ditolak sebagai in progress
+	if got := post(t, app, "u1", "k1", `{}`); got.status != fiber.StatusCreated || got.replayed {
+		t.Fatalf("retry: status %d (replayed %v): %s, want a fresh 201", got.status, got.replayed, got.body)
+	}
+}
@@ -1,7 +1,80 @@
 // Package models
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Peran pengguna. Staff dapat mengelola tiket kerusakan dan status layanan TV
+// pada lokasi yang ditugaskan kepadanya, sedangkan admin berlaku untuk semua lokasi.
+const (
+	RoleStudent = "student"
+	RoleStaff   = "staff"
+	RoleAdmin   = "admin"
+)
+
+// Status reservasi.
+const (
+	ReservationBooked    = "booked"
+	ReservationCheckedIn = "checked_in"
+	ReservationCancelled = "cancelled"
+	// ReservationCompleted dan ReservationNoShow diberikan oleh job latar belakang:
+	// reservasi yang sudah check-in menjadi completed setelah slot berakhir,
+	// sedangkan yang tidak check-in sampai batas toleransi menjadi no_show.
+	ReservationCompleted = "completed"
+	ReservationNoShow    = "no_show"
+)
+
+// Status tiket kerusakan TV.
+const (
+	IssueOpen       = "open"
+	IssueInProgress = "in_progress"
+	IssueResolved   = "resolved"
+)
+
+// Status entri waitlist. Entri waiting menunggu slot kosong; saat slot kosong,
+// entri tertua menjadi offered dan slotnya ditahan untuknya sampai batas
+// waktu tawaran, lalu berakhir sebagai accepted, expired, atau cancelled.
+const (
+	WaitlistWaiting   = "waiting"
+	WaitlistOffered   = "offered"
+	WaitlistAccepted  = "accepted"
+	WaitlistExpired   = "expired"
+	WaitlistCancelled = "cancelled"
+)
+
+// Status undangan ReservationParticipant. Undangan invited dijawab pemain yang
+// diundang dengan accepted atau declined.
+const (
+	ParticipantInvited  = "invited"
+	ParticipantAccepted = "accepted"
+	ParticipantDeclined = "declined"
+)
+
+// Status dan frekuensi ReservationSeries. Series dibuat berstatus pending dan
+// baru menjadi reservasi setelah disetujui staff lokasinya.
+const (
+	SeriesPending   = "pending"
+	SeriesApproved  = "approved"
+	SeriesRejected  = "rejected"
+	SeriesCancelled = "cancelled"
+
+	SeriesWeekly   = "weekly"
+	SeriesBiweekly = "biweekly"
+)
+
+// Jenis AvailabilityEvent. Event TV berarti ketersediaan semua slot TV tersebut
+// berubah sehingga klien perlu memuat ulang statusnya.
+const (
+	AvailabilityBooked       = "booked"
+	AvailabilityCancelled    = "cancelled"
+	AvailabilityCheckedIn    = "checked_in"
+	AvailabilityCheckedOut   = "checked_out"
+	AvailabilityOutOfService = "tv_out_of_service"
+	AvailabilityInService    = "tv_in_service"
+)
 
 // --- STRUCT UNTUK DATABASE ---
 
@@ -12,15 +85,40 @@ type User struct {
 	Major          string        `json:"major"`
 	CreditScore    int           `json:"creditScore"`
 	ProfilePictURL string        `json:"profilePictUrl"`
+	Role           string        `gorm:"default:student" json:"role"`
 	PasswordHash   string        `json:"-"` // Tidak akan pernah dikirim dalam JSON
 	Reservations   []Reservation `gorm:"foreignKey:BorrowerID" json:"-"`
 }
 
+// Location adalah ruangan game corner di sebuah fakultas/gedung. Jam buka
+// (OpenHour sampai CloseHour) dinyatakan dalam zona waktu lokasi tersebut.
+type Location struct {
+	ID        int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string `gorm:"uniqueIndex" json:"name"`
+	Building  string `json:"building"`
+	Timezone  string `gorm:"default:Asia/Jakarta" json:"timezone"`
+	OpenHour  int    `gorm:"default:9" json:"openHour"`
+	CloseHour int    `gorm:"default:18" json:"closeHour"`
+	// MaxConsecutiveHours diatur staff lokasi; nil berarti memakai
+	// BOOKING_MAX_CONSECUTIVE_HOURS dari konfigurasi.
+	MaxConsecutiveHours *int `json:"maxConsecutiveHours"`
+}
+
+// StaffAssignment memberikan hak staff untuk mengelola satu lokasi.
+type StaffAssignment struct {
+	UserID     string `gorm:"primaryKey" json:"userId"`
+	LocationID int    `gorm:"primaryKey" json:"locationId"`
+}
+
 type TVInfo struct {
-	ID           int           `gorm:"primaryKey;autoIncrement" json:"id"`
-	ConsoleType  string        `json:"consoleType"`
-	Games        []*Game       `gorm:"many2many:tv_info_games;" json:"gameList"`
-	Reservations []Reservation `gorm:"foreignKey:TVID" json:"-"`
+	ID                 int           `gorm:"primaryKey;autoIncrement" json:"id"`
+	ConsoleType        string        `json:"consoleType"`
+	LocationID         int           `gorm:"index" json:"locationId"`
+	Location           *Location     `json:"location,omitempty"`
+	OutOfService       bool          `gorm:"default:false" json:"outOfService"`
+	OutOfServiceReason string        `json:"outOfServiceReason,omitempty"`
+	Games              []*Game       `gorm:"many2many:tv_info_games;" json:"gameList"`
+	Reservations       []Reservation `gorm:"foreignKey:TVID" json:"-"`
 }
 
 type Game struct {
@@ -32,9 +130,183 @@ type Game struct {
 
 type Reservation struct {
 	gorm.Model
-	TVID       int
-	BorrowerID string
-	TimeSlot   string
+	TVID        int
+	BorrowerID  string
+	TimeSlot    string
+	Status      string `gorm:"default:booked;index"`
+	CheckedInAt *time.Time
+	// CheckedOutAt terisi jika sesi diakhiri lebih awal lewat check-out.
+	CheckedOutAt *time.Time
+	// PlayedMinutes adalah lama bermain sebenarnya, terisi saat sesi selesai
+	// (check-out atau slot berakhir), terpisah dari waktu yang dipesan.
+	PlayedMinutes *int
+	// SeriesID terisi jika reservasi adalah salah satu kejadian ReservationSeries.
+	SeriesID *uint `gorm:"index"`
+	// BookingID mengelompokkan reservasi yang dipesan bersama dalam satu Booking.
+	BookingID *uint `gorm:"index"`
+	// ExtendsID terisi jika reservasi adalah perpanjangan sesi reservasi lain ke slot berikutnya.
+	ExtendsID *uint `gorm:"index"`
+	// WalkIn menandai sesi yang dimulai staff untuk mahasiswa yang datang langsung.
+	WalkIn bool `gorm:"default:false"`
+}
+
+// ReservationChange mencatat satu perpindahan reservasi ke slot dan/atau TV lain.
+type ReservationChange struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ReservationID uint      `gorm:"index" json:"reservationId"`
+	ChangedBy     string    `json:"changedBy"`
+	FromTVID      int       `gorm:"column:from_tv_id" json:"fromTvId"`
+	FromTimeSlot  string    `json:"fromTimeslot"`
+	ToTVID        int       `gorm:"column:to_tv_id" json:"toTvId"`
+	ToTimeSlot    string    `json:"toTimeslot"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// Booking mengelompokkan beberapa reservasi (beberapa slot dan/atau TV) yang
+// dipesan dalam satu request dan dibuat sekaligus.
+type Booking struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	BorrowerID string    `json:"borrowerId"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TVIssue adalah tiket laporan kerusakan pada TV atau game tertentu.
+type TVIssue struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	TVID        int        `gorm:"index" json:"tvId"`
+	GameID      *int       `json:"gameId"`
+	ReporterID  string     `json:"reporterId"`
+	Description string     `json:"description"`
+	PhotoURL    string     `json:"photoUrl"`
+	Status      string     `gorm:"default:open;index" json:"status"`
+	StaffNote   string     `json:"staffNote"`
+	ResolvedAt  *time.Time `json:"resolvedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// Notification adalah pesan untuk pengguna, misalnya saat reservasinya dibatalkan.
+type Notification struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    string     `gorm:"index" json:"userId"`
+	Message   string     `json:"message"`
+	ReadAt    *time.Time `json:"readAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// IdempotencyKey menyimpan hasil request yang dikirim dengan header
+// Idempotency-Key agar pengulangan request yang sama memutar ulang responsnya.
+// StatusCode 0 berarti request pertama masih diproses.
+type IdempotencyKey struct {
+	UserID       string `gorm:"primaryKey"`
+	Key          string `gorm:"primaryKey"`
+	RequestHash  string
+	StatusCode   int
+	ResponseBody []byte
+	CreatedAt    time.Time
+}
+
+// AvailabilityEvent mencatat perubahan ketersediaan slot untuk dikirim ke klien
+// lewat GET /api/availability/stream. ID naik terus dan dipakai sebagai
+// Last-Event-ID saat klien tersambung kembali.
+type AvailabilityEvent struct {
+	ID         int64     `gorm:"primaryKey" json:"id"`
+	Type       string    `json:"type"`
+	LocationID int       `json:"locationId"`
+	TVID       int       `json:"tvId"`
+	TimeSlot   string    `json:"timeslot,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Device adalah perangkat kiosk yang diautentikasi dengan token berumur panjang,
+// bukan JWT mahasiswa. Hanya hash tokennya yang disimpan.
+type Device struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Name       string     `json:"name"`
+	LocationID int        `json:"locationId"`
+	TokenHash  string     `json:"-"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastSeenAt *time.Time `json:"lastSeenAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+}
+
+// WaitlistEntry adalah antrean seorang mahasiswa untuk slot yang sudah penuh,
+// pada satu TV atau (TVID nil) TV mana pun dengan ConsoleType yang sama di
+// lokasi tersebut. OfferedTVID adalah TV yang sedang atau pernah ditawarkan.
+type WaitlistEntry struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	UserID         string     `json:"userId"`
+	LocationID     int        `json:"locationId"`
+	ConsoleType    string     `json:"consoleType"`
+	TVID           *int       `json:"tvId"`
+	TimeSlot       string     `json:"timeslot"`
+	Status         string     `gorm:"default:waiting" json:"status"`
+	OfferedTVID    *int       `json:"offeredTvId"`
+	OfferExpiresAt *time.Time `json:"offerExpiresAt"`
+	ReservationID  *uint      `json:"reservationId"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// ReservationParticipant adalah pemain lain yang diundang peminjam ke
+// reservasinya. Attended diisi saat check-in dan bernilai nil sebelumnya.
+type ReservationParticipant struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	ReservationID uint       `json:"reservationId"`
+	UserID        string     `json:"userId"`
+	Status        string     `gorm:"default:invited" json:"status"`
+	Attended      *bool      `json:"attended"`
+	RespondedAt   *time.Time `json:"respondedAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// ReservationSeries adalah booking berulang, misalnya latihan klub esports setiap
+// Selasa 15:00–17:00 pada beberapa TV. Tanggal dan jam dinyatakan dalam zona
+// waktu lokasi; kejadian pertama jatuh pada StartDate dan berulang setiap
+// minggu atau dua minggu sampai EndDate, kecuali ExceptionDates. Overrides
+// mengganti jam untuk satu tanggal saja.
+type ReservationSeries struct {
+	ID             uint             `gorm:"primaryKey" json:"id"`
+	OrganizerID    string           `json:"organizerId"`
+	Title          string           `json:"title"`
+	LocationID     int              `json:"locationId"`
+	TVIDs          []int            `gorm:"column:tv_ids;serializer:json" json:"tvIds"`
+	Frequency      string           `json:"frequency"`
+	StartDate      string           `json:"startDate"`
+	EndDate        string           `json:"endDate"`
+	StartHour      int              `json:"startHour"`
+	EndHour        int              `json:"endHour"`
+	ExceptionDates []string         `gorm:"serializer:json" json:"exceptionDates"`
+	Overrides      []SeriesOverride `gorm:"serializer:json" json:"overrides"`
+	Status         string           `gorm:"default:pending" json:"status"`
+	ReviewedBy     *string          `json:"reviewedBy"`
+	ReviewedAt     *time.Time       `json:"reviewedAt"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	UpdatedAt      time.Time        `json:"updatedAt"`
+}
+
+// SeriesOverride mengganti jam satu kejadian series pada tanggal Date.
+type SeriesOverride struct {
+	Date      string `json:"date"`
+	StartHour int    `json:"startHour"`
+	EndHour   int    `json:"endHour"`
+}
+
+// SeriesConflict adalah slot series yang bentrok dengan booking lain atau TV rusak.
+type SeriesConflict struct {
+	TVID     int    `json:"tvId"`
+	TimeSlot string `json:"timeslot"`
+	// Reason bernilai "booked" (sudah dipesan atau ditawarkan ke waitlist) atau "out_of_service".
+	Reason string `json:"reason"`
+}
+
+// SeriesOccurrence adalah satu reservasi yang dibuat dari ReservationSeries.
+type SeriesOccurrence struct {
+	ReservationID uint   `json:"reservationId"`
+	TVID          int    `json:"tvId"`
+	TimeSlot      string `json:"timeslot"`
+	Status        string `json:"status"`
 }
 
 // --- STRUCT UNTUK REQUEST & RESPONSE BODY ---
@@ -47,6 +319,15 @@ type Response struct {
 
 type ErrorData struct {
 	ErrorMsg string `json:"errorMsg"`
+	// ErrorCode adalah kode kesalahan yang stabil untuk diproses klien, misalnya SLOT_TAKEN.
+	ErrorCode string       `json:"errorCode"`
+	Fields    []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError menjelaskan kesalahan validasi pada satu field request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 type ErrorResponse struct {
@@ -56,8 +337,8 @@ type ErrorResponse struct {
 }
 
 type LoginBody struct {
-	Identifier string `json:"identifier"`
-	Password   string `json:"password"`
+	Identifier string `json:"identifier" validate:"required"`
+	Password   string `json:"password" validate:"required"`
 }
 
 type TokenCarrier struct {
@@ -78,22 +359,168 @@ type History struct {
 	TVID                int    `json:"tvId"`
 	ReservationDateTime string `json:"reservationDateTime"`
 	TVPictURL           string `json:"tvPictUrl"`
+	Status              string `json:"status"`
+	BookingID           *uint  `json:"bookingId"`
+	// Role bernilai "borrower" atau "participant" (undangan pemain lain yang diterima).
+	Role string `json:"role"`
+	// PlayedMinutes kosong selama sesi belum selesai.
+	PlayedMinutes *int `json:"playedMinutes"`
+	WalkIn        bool `json:"walkIn"`
 }
 
 type TimeSlot struct {
 	StartTime    string `json:"startTime"`
 	EndTime      string `json:"endTime"`
 	Availability string `json:"availability"`
+	// WalkInFrom terisi jika sesi di slot ini sudah check-out lebih awal; sisa
+	// slot sejak waktu tersebut dapat dipakai walk-in.
+	WalkInFrom *string `json:"walkInFrom,omitempty"`
 }
 
 type TV struct {
-	ID          int        `json:"id"`
-	ConsoleType string     `json:"consoleType"`
-	TimeSlots   []TimeSlot `json:"timeSlots"`
+	ID           int        `json:"id"`
+	ConsoleType  string     `json:"consoleType"`
+	LocationID   int        `json:"locationId"`
+	OutOfService bool       `json:"outOfService"`
+	TimeSlots    []TimeSlot `json:"timeSlots"`
 }
 
 type ReservationBody struct {
-	TVID       int    `json:"tvId"`
+	// TVID opsional; jika diisi harus sama dengan :tvId pada path.
+	TVID       int    `json:"tvId" validate:"omitempty,gt=0"`
 	BorrowerID string `json:"borrowerId"`
-	Timeslot   string `json:"timeslot"`
+	Timeslot   string `json:"timeslot" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// BookingBody memesan beberapa pasangan TV dan slot sekaligus; semuanya berhasil
+// atau tidak ada yang dipesan.
+type BookingBody struct {
+	Items []BookingItem `json:"items" validate:"required,min=1,max=8,dive"`
+}
+
+type BookingItem struct {
+	TVID     int    `json:"tvId" validate:"required,gt=0"`
+	Timeslot string `json:"timeslot" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// RescheduleBody memindahkan reservasi; field yang kosong tidak diubah, tetapi
+// paling tidak satu harus diisi.
+type RescheduleBody struct {
+	TVID     int    `json:"tvId" validate:"omitempty,gt=0"`
+	Timeslot string `json:"timeslot" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+type WalkInBody struct {
+	UserID string `json:"userId" validate:"required,max=50"`
+}
+
+type InvitationBody struct {
+	UserID string `json:"userId" validate:"required,max=50"`
+}
+
+// CheckInBody opsional saat check-in. Attendees adalah pemain undangan yang
+// hadir; jika tidak diisi, semua undangan yang sudah diterima dianggap hadir.
+type CheckInBody struct {
+	Attendees *[]string `json:"attendees" validate:"omitempty,max=10,dive,required"`
+}
+
+type WaitlistBody struct {
+	Timeslot string `json:"timeslot" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	// AnyTV memasukkan antrean untuk TV mana pun dengan jenis konsol yang sama di lokasi TV pada path.
+	AnyTV bool `json:"anyTv"`
+}
+
+type SeriesBody struct {
+	Title          string   `json:"title" validate:"required,max=100"`
+	TVIDs          []int    `json:"tvIds" validate:"required,min=1,max=20,dive,gt=0"`
+	Frequency      string   `json:"frequency" validate:"required,oneof=weekly biweekly"`
+	StartDate      string   `json:"startDate" validate:"required,datetime=2006-01-02"`
+	EndDate        string   `json:"endDate" validate:"required,datetime=2006-01-02"`
+	StartHour      int      `json:"startHour" validate:"min=0,max=23"`
+	EndHour        int      `json:"endHour" validate:"min=1,max=24,gtfield=StartHour"`
+	ExceptionDates []string `json:"exceptionDates" validate:"max=100,dive,datetime=2006-01-02"`
+}
+
+// SeriesUpdateBody mengubah seluruh series. Field yang kosong tidak diubah.
+type SeriesUpdateBody struct {
+	Title          *string   `json:"title" validate:"omitempty,max=100"`
+	TVIDs          *[]int    `json:"tvIds" validate:"omitempty,min=1,max=20,dive,gt=0"`
+	EndDate        *string   `json:"endDate" validate:"omitempty,datetime=2006-01-02"`
+	StartHour      *int      `json:"startHour" validate:"omitempty,min=0,max=23"`
+	EndHour        *int      `json:"endHour" validate:"omitempty,min=1,max=24"`
+	ExceptionDates *[]string `json:"exceptionDates" validate:"omitempty,max=100,dive,datetime=2006-01-02"`
+	// SkipConflicts melewati slot yang bentrok alih-alih menolak perubahan.
+	SkipConflicts bool `json:"skipConflicts"`
+}
+
+// OccurrenceBody mengubah satu kejadian series: membatalkannya, atau memindahkan
+// jamnya pada tanggal yang sama.
+type OccurrenceBody struct {
+	Cancelled     bool `json:"cancelled"`
+	StartHour     *int `json:"startHour" validate:"required_without=Cancelled,omitempty,min=0,max=23"`
+	EndHour       *int `json:"endHour" validate:"required_without=Cancelled,omitempty,min=1,max=24"`
+	SkipConflicts bool `json:"skipConflicts"`
+}
+
+type SeriesApprovalBody struct {
+	SkipConflicts bool `json:"skipConflicts"`
+}
+
+type IssueBody struct {
+	GameID      *int   `json:"gameId" form:"gameId" validate:"omitempty,gt=0"`
+	Description string `json:"description" form:"description" validate:"required,max=1000"`
+	PhotoURL    string `json:"photoUrl" form:"photoUrl" validate:"omitempty,url,max=500"`
+}
+
+type IssueStatusBody struct {
+	Status    string `json:"status" validate:"required,oneof=open in_progress resolved"`
+	StaffNote string `json:"staffNote" validate:"max=1000"`
+}
+
+type TVServiceBody struct {
+	OutOfService bool   `json:"outOfService"`
+	Reason       string `json:"reason" validate:"max=500"`
+}
+
+// KioskBoard adalah keadaan semua TV di satu lokasi untuk layar kiosk.
+type KioskBoard struct {
+	LocationID int       `json:"locationId"`
+	TVs        []KioskTV `json:"tvs"`
+}
+
+// KioskTV adalah pemain saat ini dan booking berikutnya pada satu TV.
+type KioskTV struct {
+	ID           int           `json:"id"`
+	ConsoleType  string        `json:"consoleType"`
+	OutOfService bool          `json:"outOfService"`
+	Current      *KioskSession `json:"current"`
+	Next         *KioskSession `json:"next"`
+}
+
+// KioskSession adalah satu sesi bermain di layar kiosk. Sisa waktu dihitung
+// klien dari EndsAt dan serverTime pada pesan WebSocket.
+type KioskSession struct {
+	ReservationID uint      `json:"reservationId"`
+	Player        string    `json:"player"`
+	StartsAt      time.Time `json:"startsAt"`
+	EndsAt        time.Time `json:"endsAt"`
+}
+
+type DeviceBody struct {
+	Name       string `json:"name" validate:"required,max=100"`
+	LocationID int    `json:"locationId" validate:"required,gt=0"`
+}
+
+// BookingPolicyBody mengganti kebijakan booking sebuah lokasi. Nilai null
+// mengembalikan batas ke bawaan konfigurasi.
+type BookingPolicyBody struct {
+	MaxConsecutiveHours *int `json:"maxConsecutiveHours" validate:"omitempty,min=1,max=24"`
+}
+
+type LocationBody struct {
+	Name      string `json:"name" validate:"required,max=100"`
+	Building  string `json:"building" validate:"max=100"`
+	Timezone  string `json:"timezone" validate:"required,timezone"`
+	OpenHour  int    `json:"openHour" validate:"min=0,max=23"`
+	CloseHour int    `json:"closeHour" validate:"min=1,max=24,gtfield=OpenHour"`
 }
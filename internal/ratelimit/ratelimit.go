@@ -0,0 +1,138 @@
+// Package ratelimit
+package ratelimit
+
+import (
+	"math"
+	"net/netip"
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/config"
+	"playcorner-be/internal/logging"
+	"playcorner-be/internal/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Header RateLimit-* mengikuti draft IETF "RateLimit header fields for HTTP".
+const (
+	HeaderLimit     = "RateLimit-Limit"
+	HeaderRemaining = "RateLimit-Remaining"
+	HeaderReset     = "RateLimit-Reset"
+	HeaderPolicy    = "RateLimit-Policy"
+)
+
+var errRateLimited = apperr.QuotaExceeded("RATE_LIMITED", "Too many requests, please try again later")
+
+// Policy adalah token bucket bernama: Requests token yang terisi penuh kembali
+// dalam Per. Nama membedakan bucket kebijakan yang berbeda untuk klien yang sama.
+type Policy struct {
+	Name string
+	config.RateLimitPolicy
+}
+
+// rate adalah jumlah token yang terisi per detik.
+func (p Policy) rate() float64 {
+	return float64(p.Requests) / p.Per.Seconds()
+}
+
+// Limiter membatasi laju request dengan token bucket yang disimpan di buckets.
+type Limiter struct {
+	buckets repository.RateLimitRepository
+	trusted []netip.Prefix
+
+	// Now dapat diganti saat pengujian.
+	Now func() time.Time
+}
+
+// New membuat Limiter. trustedProxies adalah jaringan proxy yang header
+// X-Forwarded-For-nya dipercaya oleh ClientIP.
+func New(buckets repository.RateLimitRepository, trustedProxies []netip.Prefix) *Limiter {
+	return &Limiter{buckets: buckets, trusted: trustedProxies, Now: time.Now}
+}
+
+// Middleware membatasi rute dengan policy, per pengguna jika request sudah
+// diautentikasi (dipasang setelah AuthMiddleware) atau per IP klien jika belum.
+// Setiap respons membawa header RateLimit-*; request yang melebihi batas
+// mendapat 429 RATE_LIMITED dengan Retry-After. Jika penyimpanan bucket gagal,
+// request tetap dilayani agar gangguan database tidak memblokir seluruh API.
+func (l *Limiter) Middleware(policy Policy) fiber.Handler {
+	capacity, rate := float64(policy.Requests), policy.rate()
+	policyHeader := strconv.Itoa(policy.Requests) + ";w=" + strconv.Itoa(int(policy.Per.Seconds()))
+
+	return func(c *fiber.Ctx) error {
+		remaining, allowed, err := l.buckets.Take(c.UserContext(), policy.Name+":"+l.clientKey(c), capacity, rate, l.Now())
+		if err != nil {
+			logging.ForRequest(c).Warn("rate limiter unavailable", "policy", policy.Name, "error", err)
+			return c.Next()
+		}
+
+		c.Set(HeaderLimit, strconv.Itoa(policy.Requests))
+		c.Set(HeaderRemaining, strconv.Itoa(int(math.Floor(remaining))))
+		c.Set(HeaderReset, strconv.Itoa(seconds((capacity-remaining)/rate)))
+		c.Set(HeaderPolicy, policyHeader)
+
+		if !allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds((1-remaining)/rate)))
+			return errRateLimited
+		}
+		return c.Next()
+	}
+}
+
+// clientKey memilih identitas bucket: pengguna yang diautentikasi atau IP klien.
+func (l *Limiter) clientKey(c *fiber.Ctx) string {
+	if userID, _ := c.Locals("userID").(string); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + l.ClientIP(c)
+}
+
+// ClientIP mengembalikan IP klien menurut proxy tepercaya Limiter.
+func (l *Limiter) ClientIP(c *fiber.Ctx) string {
+	return ClientIP(c, l.trusted)
+}
+
+// ClientIP mengembalikan IP klien. X-Forwarded-For hanya dipakai jika koneksi
+// datang dari proxy tepercaya (trusted), dan dibaca dari kanan: alamat pertama
+// yang bukan proxy tepercaya adalah klien, sehingga nilai palsu yang dikirim
+// klien di awal header diabaikan.
+func ClientIP(c *fiber.Ctx, trusted []netip.Prefix) string {
+	remote := c.Context().RemoteIP().String()
+	if !isTrusted(remote, trusted) {
+		return remote
+	}
+
+	hops := strings.Split(c.Get(fiber.HeaderXForwardedFor), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if _, err := netip.ParseAddr(hop); err != nil {
+			break
+		}
+		if !isTrusted(hop, trusted) {
+			return hop
+		}
+		remote = hop
+	}
+	return remote
+}
+
+func isTrusted(ip string, trusted []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// seconds membulatkan durasi (dalam detik) ke atas untuk header.
+func seconds(s float64) int {
+	return int(math.Ceil(max(s, 0)))
+}
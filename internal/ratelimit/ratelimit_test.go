@@ -0,0 +1,128 @@
+package ratelimit_test
+
+import (
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"playcorner-be/internal/config"
+	"playcorner-be/internal/handlers"
+	"playcorner-be/internal/ratelimit"
+	"playcorner-be/internal/repository/memory"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// policy mengizinkan 2 request per 10 detik, yaitu satu token setiap 5 detik.
+var policy = ratelimit.Policy{Name: "test", RateLimitPolicy: config.RateLimitPolicy{Requests: 2, Per: 10 * time.Second}}
+
+func TestMiddleware(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 9, 30, 0, 0, time.UTC)
+	limiter := ratelimit.New(memory.New().RateLimits(), nil)
+	limiter.Now = func() time.Time { return now }
+
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	app.Get("/", func(c *fiber.Ctx) error {
+		if user := c.Get("X-User"); user != "" {
+			c.Locals("userID", user)
+		}
+		return c.Next()
+	}, limiter.Middleware(policy), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	tests := []struct {
+		name           string
+		user           string
+		advance        time.Duration
+		wantStatus     int
+		wantRemaining  string
+		wantRetryAfter string
+	}{
+		{name: "allows the first request", user: "u1", wantStatus: 204, wantRemaining: "1"},
+		{name: "allows the burst", user: "u1", wantStatus: 204, wantRemaining: "0"},
+		{name: "rejects once the bucket is empty", user: "u1", wantStatus: 429, wantRemaining: "0", wantRetryAfter: "5"},
+		{name: "keeps a separate bucket per user", user: "u2", wantStatus: 204, wantRemaining: "1"},
+		{name: "keeps a separate bucket for anonymous clients", wantStatus: 204, wantRemaining: "1"},
+		{name: "refills at the policy rate", user: "u1", advance: 5 * time.Second, wantStatus: 204, wantRemaining: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.advance)
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.user != "" {
+				req.Header.Set("X-User", tt.user)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := resp.Header.Get(ratelimit.HeaderRemaining); got != tt.wantRemaining {
+				t.Fatalf("%s = %q, want %q", ratelimit.HeaderRemaining, got, tt.wantRemaining)
+			}
+			if got := resp.Header.Get(ratelimit.HeaderPolicy); got != "2;w=10" {
+				t.Fatalf("%s = %q, want 2;w=10", ratelimit.HeaderPolicy, got)
+			}
+			if got := resp.Header.Get(fiber.HeaderRetryAfter); got != tt.wantRetryAfter {
+				t.Fatalf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	// Request app.Test selalu datang dari 0.0.0.0
+	proxy := netip.MustParsePrefix("0.0.0.0/32")
+	internal := netip.MustParsePrefix("10.0.0.0/8")
+
+	tests := []struct {
+		name    string
+		trusted []netip.Prefix
+		xff     string
+		want    string
+	}{
+		{name: "ignores forwarded headers from an untrusted peer", xff: "203.0.113.7", want: "0.0.0.0"},
+		{name: "uses the forwarded client behind a trusted proxy", trusted: []netip.Prefix{proxy}, xff: "203.0.113.7", want: "203.0.113.7"},
+		{
+			name: "ignores addresses spoofed by the client", trusted: []netip.Prefix{proxy},
+			xff: "198.51.100.1, 203.0.113.7", want: "203.0.113.7",
+		},
+		{
+			name: "skips every trusted hop", trusted: []netip.Prefix{proxy, internal},
+			xff: "203.0.113.7, 10.0.0.2, 10.0.0.3", want: "203.0.113.7",
+		},
+		{
+			name: "stops at a malformed hop", trusted: []netip.Prefix{proxy, internal},
+			xff: "203.0.113.7, garbage, 10.0.0.3", want: "10.0.0.3",
+		},
+		{name: "falls back to the peer without the header", trusted: []netip.Prefix{proxy}, want: "0.0.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := ratelimit.New(memory.New().RateLimits(), tt.trusted)
+			app := fiber.New()
+			var got string
+			app.Get("/", func(c *fiber.Ctx) error {
+				got = limiter.ClientIP(c)
+				return nil
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.xff != "" {
+				req.Header.Set(fiber.HeaderXForwardedFor, tt.xff)
+			}
+			if _, err := app.Test(req); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
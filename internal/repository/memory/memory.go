@@ -0,0 +1,1206 @@
+// Package memory
+package memory
+
+import (
+	"context"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store adalah implementasi repository.Store di memori. Ditujukan untuk
+// pengujian cepat tanpa database; semua data hilang saat proses berhenti.
+type Store struct {
+	mu   *sync.Mutex
+	txMu *sync.Mutex
+	d    *data
+
+	listeners *listeners
+	// pending menampung event yang di-Append di dalam transaksi; nil di luar transaksi.
+	pending *[]models.AvailabilityEvent
+}
+
+// listeners adalah fungsi yang didaftarkan lewat AvailabilityEvents().Listen.
+type listeners struct {
+	mu   sync.Mutex
+	next int
+	fns  map[int]func(models.AvailabilityEvent)
+}
+
+type staffKey struct {
+	userID     string
+	locationID int
+}
+
+type idempotencyKey struct {
+	userID string
+	key    string
+}
+
+type rateBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+type data struct {
+	users         map[string]models.User
+	tvs           map[int]models.TVInfo
+	tvGames       map[int][]int
+	games         map[int]models.Game
+	reservations  map[uint]models.Reservation
+	locations     map[int]models.Location
+	staff         map[staffKey]bool
+	issues        map[uint]models.TVIssue
+	notifications map[uint]models.Notification
+	idempotency   map[idempotencyKey]models.IdempotencyKey
+	rateBuckets   map[string]rateBucket
+	events        []models.AvailabilityEvent
+	devices       map[uint]models.Device
+	waitlist      map[uint]models.WaitlistEntry
+	series        map[uint]models.ReservationSeries
+	bookings      map[uint]models.Booking
+	participants  map[uint]models.ReservationParticipant
+	changes       map[uint]models.ReservationChange
+	nextID        map[string]int
+}
+
+// New membuat Store kosong.
+func New() *Store {
+	return &Store{
+		mu:   &sync.Mutex{},
+		txMu: &sync.Mutex{},
+		d: &data{
+			users:         map[string]models.User{},
+			tvs:           map[int]models.TVInfo{},
+			tvGames:       map[int][]int{},
+			games:         map[int]models.Game{},
+			reservations:  map[uint]models.Reservation{},
+			locations:     map[int]models.Location{},
+			staff:         map[staffKey]bool{},
+			issues:        map[uint]models.TVIssue{},
+			notifications: map[uint]models.Notification{},
+			idempotency:   map[idempotencyKey]models.IdempotencyKey{},
+			rateBuckets:   map[string]rateBucket{},
+			devices:       map[uint]models.Device{},
+			waitlist:      map[uint]models.WaitlistEntry{},
+			series:        map[uint]models.ReservationSeries{},
+			bookings:      map[uint]models.Booking{},
+			participants:  map[uint]models.ReservationParticipant{},
+			changes:       map[uint]models.ReservationChange{},
+			nextID:        map[string]int{},
+		},
+		listeners: &listeners{fns: map[int]func(models.AvailabilityEvent){}},
+	}
+}
+
+func (s *Store) Users() repository.UserRepository                     { return userRepo{s} }
+func (s *Store) TVs() repository.TVRepository                         { return tvRepo{s} }
+func (s *Store) Games() repository.GameRepository                     { return gameRepo{s} }
+func (s *Store) Reservations() repository.ReservationRepository       { return reservationRepo{s} }
+func (s *Store) Bookings() repository.BookingRepository               { return bookingRepo{s} }
+func (s *Store) Participants() repository.ParticipantRepository       { return participantRepo{s} }
+func (s *Store) Locations() repository.LocationRepository             { return locationRepo{s} }
+func (s *Store) Staff() repository.StaffRepository                    { return staffRepo{s} }
+func (s *Store) Issues() repository.IssueRepository                   { return issueRepo{s} }
+func (s *Store) Notifications() repository.NotificationRepository     { return notificationRepo{s} }
+func (s *Store) IdempotencyKeys() repository.IdempotencyKeyRepository { return idempotencyKeyRepo{s} }
+func (s *Store) RateLimits() repository.RateLimitRepository           { return rateLimitRepo{s} }
+func (s *Store) AvailabilityEvents() repository.AvailabilityEventRepository {
+	return availabilityEventRepo{s}
+}
+func (s *Store) Devices() repository.DeviceRepository    { return deviceRepo{s} }
+func (s *Store) Waitlist() repository.WaitlistRepository { return waitlistRepo{s} }
+func (s *Store) Series() repository.SeriesRepository     { return seriesRepo{s} }
+func (s *Store) ReservationChanges() repository.ReservationChangeRepository {
+	return reservationChangeRepo{s}
+}
+
+// Transaction menjalankan fn secara berurutan terhadap transaksi lain dan
+// mengembalikan semua data ke kondisi semula jika fn mengembalikan error.
+// Event ketersediaan baru diteruskan ke listener setelah fn berhasil.
+func (s *Store) Transaction(ctx context.Context, fn func(tx repository.Store) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	s.mu.Lock()
+	snapshot := s.d.clone()
+	s.mu.Unlock()
+
+	tx := *s
+	tx.pending = &[]models.AvailabilityEvent{}
+	if err := fn(&tx); err != nil {
+		s.mu.Lock()
+		*s.d = *snapshot
+		s.mu.Unlock()
+		return err
+	}
+	for _, e := range *tx.pending {
+		s.listeners.notify(e)
+	}
+	return nil
+}
+
+func (d *data) clone() *data {
+	tvGames := make(map[int][]int, len(d.tvGames))
+	for k, v := range d.tvGames {
+		tvGames[k] = slices.Clone(v)
+	}
+	return &data{
+		users:         cloneMap(d.users),
+		tvs:           cloneMap(d.tvs),
+		tvGames:       tvGames,
+		games:         cloneMap(d.games),
+		reservations:  cloneMap(d.reservations),
+		locations:     cloneMap(d.locations),
+		staff:         cloneMap(d.staff),
+		issues:        cloneMap(d.issues),
+		notifications: cloneMap(d.notifications),
+		idempotency:   cloneMap(d.idempotency),
+		rateBuckets:   cloneMap(d.rateBuckets),
+		events:        slices.Clone(d.events),
+		devices:       cloneMap(d.devices),
+		waitlist:      cloneMap(d.waitlist),
+		series:        cloneMap(d.series),
+		bookings:      cloneMap(d.bookings),
+		participants:  cloneMap(d.participants),
+		changes:       cloneMap(d.changes),
+		nextID:        cloneMap(d.nextID),
+	}
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	out := make(map[K]V, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// next menghasilkan ID berurutan per tabel, seperti sequence di Postgres.
+func (d *data) next(table string) int {
+	d.nextID[table]++
+	return d.nextID[table]
+}
+
+// --- Helper untuk menyiapkan data uji ---
+
+// AddUser menambahkan atau mengganti pengguna.
+func (s *Store) AddUser(u models.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u.Role == "" {
+		u.Role = models.RoleStudent
+	}
+	s.d.users[u.ID] = u
+}
+
+// AddLocation menambahkan lokasi dan mengembalikannya lengkap dengan ID.
+func (s *Store) AddLocation(l models.Location) models.Location {
+	_ = locationRepo{s}.Create(context.Background(), &l)
+	return l
+}
+
+// AddGame menambahkan game dan mengembalikannya lengkap dengan ID.
+func (s *Store) AddGame(g models.Game) models.Game {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g.ID == 0 {
+		g.ID = s.d.next("games")
+	}
+	g.TVs = nil
+	s.d.games[g.ID] = g
+	return g
+}
+
+// AddTV menambahkan TV beserta daftar game yang terpasang.
+func (s *Store) AddTV(tv models.TVInfo, gameIDs ...int) models.TVInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tv.ID == 0 {
+		tv.ID = s.d.next("tv_infos")
+	}
+	tv.Games, tv.Location, tv.Reservations = nil, nil, nil
+	s.d.tvs[tv.ID] = tv
+	s.d.tvGames[tv.ID] = slices.Clone(gameIDs)
+	return tv
+}
+
+// --- Users ---
+
+type userRepo struct{ s *Store }
+
+func (r userRepo) FindByID(_ context.Context, id string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	u, ok := r.s.d.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &u, nil
+}
+
+// Lock tidak perlu melakukan apa pun karena Transaction sudah berjalan berurutan.
+func (r userRepo) Lock(_ context.Context, _ string) error { return nil }
+
+// --- TVs ---
+
+type tvRepo struct{ s *Store }
+
+// hydrate melengkapi TV dengan Games dan Location; pemanggil harus memegang lock.
+func (r tvRepo) hydrate(tv models.TVInfo) models.TVInfo {
+	tv.Games = []*models.Game{}
+	for _, id := range r.s.d.tvGames[tv.ID] {
+		if g, ok := r.s.d.games[id]; ok {
+			tv.Games = append(tv.Games, &g)
+		}
+	}
+	if l, ok := r.s.d.locations[tv.LocationID]; ok {
+		tv.Location = &l
+	}
+	return tv
+}
+
+func (r tvRepo) List(_ context.Context, filter repository.TVFilter) ([]models.TVInfo, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	tvs := []models.TVInfo{}
+	for _, tv := range r.s.d.tvs {
+		if filter.LocationID != nil && tv.LocationID != *filter.LocationID {
+			continue
+		}
+		tvs = append(tvs, r.hydrate(tv))
+	}
+	sort.Slice(tvs, func(i, j int) bool { return tvs[i].ID < tvs[j].ID })
+	return tvs, nil
+}
+
+func (r tvRepo) FindByID(_ context.Context, id int) (*models.TVInfo, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	tv, ok := r.s.d.tvs[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	tv = r.hydrate(tv)
+	return &tv, nil
+}
+
+// Lock tidak perlu melakukan apa pun karena Transaction sudah berjalan berurutan.
+func (r tvRepo) Lock(_ context.Context, _ ...int) error { return nil }
+
+func (r tvRepo) UpdateService(_ context.Context, id int, outOfService bool, reason string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	tv, ok := r.s.d.tvs[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	tv.OutOfService = outOfService
+	tv.OutOfServiceReason = reason
+	r.s.d.tvs[id] = tv
+	return nil
+}
+
+// --- Games ---
+
+type gameRepo struct{ s *Store }
+
+func (r gameRepo) List(_ context.Context) ([]models.Game, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	games := []models.Game{}
+	for _, g := range r.s.d.games {
+		games = append(games, g)
+	}
+	sort.Slice(games, func(i, j int) bool { return games[i].ID < games[j].ID })
+	return games, nil
+}
+
+func (r gameRepo) ListByTV(_ context.Context, tvID int) ([]models.Game, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	games := []models.Game{}
+	for _, id := range r.s.d.tvGames[tvID] {
+		if g, ok := r.s.d.games[id]; ok {
+			games = append(games, g)
+		}
+	}
+	sort.Slice(games, func(i, j int) bool { return games[i].ID < games[j].ID })
+	return games, nil
+}
+
+// --- Reservations ---
+
+type reservationRepo struct{ s *Store }
+
+func (r reservationRepo) Create(_ context.Context, res *models.Reservation) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	res.ID = uint(r.s.d.next("reservations"))
+	res.CreatedAt, res.UpdatedAt = now, now
+	if res.Status == "" {
+		res.Status = models.ReservationBooked
+	}
+	if r.slotHeld(*res) {
+		return repository.ErrDuplicate
+	}
+	r.s.d.reservations[res.ID] = *res
+	return nil
+}
+
+// slotHeld meniru index unik idx_reservations_active_slot: slot dan TV res sudah
+// dipegang reservasi aktif lain yang belum check-out. Pemanggil memegang mu.
+func (r reservationRepo) slotHeld(res models.Reservation) bool {
+	if !holdsSlot(res) || res.CheckedOutAt != nil {
+		return false
+	}
+	for _, other := range r.s.d.reservations {
+		if other.ID != res.ID && other.TVID == res.TVID && other.TimeSlot == res.TimeSlot &&
+			holdsSlot(other) && other.CheckedOutAt == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (r reservationRepo) FindByID(_ context.Context, id uint) (*models.Reservation, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	res, ok := r.s.d.reservations[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &res, nil
+}
+
+func (r reservationRepo) Update(_ context.Context, res *models.Reservation) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.d.reservations[res.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	if r.slotHeld(*res) {
+		return repository.ErrDuplicate
+	}
+	res.UpdatedAt = time.Now()
+	r.s.d.reservations[res.ID] = *res
+	return nil
+}
+
+// filter mengembalikan reservasi yang memenuhi keep, diurutkan berdasarkan slot.
+func (r reservationRepo) filter(keep func(models.Reservation) bool) []models.Reservation {
+	out := []models.Reservation{}
+	for _, res := range r.s.d.reservations {
+		if keep(res) {
+			out = append(out, res)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TimeSlot != out[j].TimeSlot {
+			return out[i].TimeSlot < out[j].TimeSlot
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// holdsSlot melaporkan apakah reservasi masih memegang slotnya.
+func holdsSlot(res models.Reservation) bool {
+	return res.Status != models.ReservationCancelled && res.Status != models.ReservationNoShow
+}
+
+func (r reservationRepo) FindActiveBySlot(_ context.Context, tvID int, slot string) (*models.Reservation, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	found := r.filter(func(res models.Reservation) bool {
+		return res.TVID == tvID && res.TimeSlot == slot && holdsSlot(res)
+	})
+	if len(found) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &found[0], nil
+}
+
+func (r reservationRepo) ListActiveByTV(_ context.Context, tvID int, from, to string) ([]models.Reservation, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.filter(func(res models.Reservation) bool {
+		return res.TVID == tvID && res.TimeSlot >= from && res.TimeSlot < to && holdsSlot(res)
+	}), nil
+}
+
+func (r reservationRepo) ListActiveByBorrower(_ context.Context, borrowerID string, from, to string) ([]models.Reservation, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.filter(func(res models.Reservation) bool {
+		return res.BorrowerID == borrowerID && res.TimeSlot >= from && res.TimeSlot < to && holdsSlot(res)
+	}), nil
+}
+
+func (r reservationRepo) ListBookedFrom(_ context.Context, tvID int, from string) ([]models.Reservation, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.filter(func(res models.Reservation) bool {
+		return res.TVID == tvID && res.TimeSlot >= from && res.Status == models.ReservationBooked
+	}), nil
+}
+
+func (r reservationRepo) ListBySeries(_ context.Context, seriesID uint, from string) ([]models.Reservation, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.filter(func(res models.Reservation) bool {
+		return res.SeriesID != nil && *res.SeriesID == seriesID && res.TimeSlot >= from && holdsSlot(res)
+	}), nil
+}
+
+func (r reservationRepo) ListByUser(_ context.Context, userID string, limit, offset int) ([]models.Reservation, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	participating := map[uint]bool{}
+	for _, p := range r.s.d.participants {
+		if p.UserID == userID && p.Status == models.ParticipantAccepted {
+			participating[p.ReservationID] = true
+		}
+	}
+	all := r.filter(func(res models.Reservation) bool { return res.BorrowerID == userID || participating[res.ID] })
+	sort.Slice(all, func(i, j int) bool { return all[i].ID > all[j].ID })
+	return page(all, limit, offset), int64(len(all)), nil
+}
+
+func (r reservationRepo) UpdateStatus(_ context.Context, id uint, status string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	res, ok := r.s.d.reservations[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	res.Status = status
+	res.UpdatedAt = time.Now()
+	r.s.d.reservations[id] = res
+	return nil
+}
+
+func (r reservationRepo) TransitionStatus(_ context.Context, from, to, upTo string) ([]models.Reservation, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	changed := r.filter(func(res models.Reservation) bool { return res.Status == from && res.TimeSlot <= upTo })
+	for i := range changed {
+		changed[i].Status = to
+		changed[i].UpdatedAt = now
+		r.s.d.reservations[changed[i].ID] = changed[i]
+	}
+	return changed, nil
+}
+
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) || offset < 0 {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// --- Locations ---
+
+type locationRepo struct{ s *Store }
+
+func (r locationRepo) List(_ context.Context) ([]models.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	locations := []models.Location{}
+	for _, l := range r.s.d.locations {
+		locations = append(locations, l)
+	}
+	sort.Slice(locations, func(i, j int) bool { return locations[i].ID < locations[j].ID })
+	return locations, nil
+}
+
+func (r locationRepo) FindByID(_ context.Context, id int) (*models.Location, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	l, ok := r.s.d.locations[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &l, nil
+}
+
+func (r locationRepo) Create(_ context.Context, l *models.Location) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if l.ID == 0 {
+		l.ID = r.s.d.next("locations")
+	}
+	r.s.d.locations[l.ID] = *l
+	return nil
+}
+
+func (r locationRepo) Update(_ context.Context, l *models.Location) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.d.locations[l.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	r.s.d.locations[l.ID] = *l
+	return nil
+}
+
+// --- Staff assignments ---
+
+type staffRepo struct{ s *Store }
+
+func (r staffRepo) IsAssigned(_ context.Context, userID string, locationID int) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.d.staff[staffKey{userID, locationID}], nil
+}
+
+func (r staffRepo) LocationIDs(_ context.Context, userID string) ([]int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	ids := []int{}
+	for k := range r.s.d.staff {
+		if k.userID == userID {
+			ids = append(ids, k.locationID)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+func (r staffRepo) Assign(_ context.Context, userID string, locationID int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.d.staff[staffKey{userID, locationID}] = true
+	return nil
+}
+
+func (r staffRepo) Unassign(_ context.Context, userID string, locationID int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.d.staff, staffKey{userID, locationID})
+	return nil
+}
+
+// --- Issues ---
+
+type issueRepo struct{ s *Store }
+
+func (r issueRepo) Create(_ context.Context, issue *models.TVIssue) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	issue.ID = uint(r.s.d.next("tv_issues"))
+	issue.CreatedAt, issue.UpdatedAt = now, now
+	r.s.d.issues[issue.ID] = *issue
+	return nil
+}
+
+func (r issueRepo) FindByID(_ context.Context, id uint) (*models.TVIssue, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	issue, ok := r.s.d.issues[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &issue, nil
+}
+
+func (r issueRepo) List(_ context.Context, filter repository.IssueFilter) ([]models.TVIssue, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	issues := []models.TVIssue{}
+	for _, issue := range r.s.d.issues {
+		if filter.LocationIDs != nil && !slices.Contains(filter.LocationIDs, r.s.d.tvs[issue.TVID].LocationID) {
+			continue
+		}
+		if filter.Status != "" && issue.Status != filter.Status {
+			continue
+		}
+		if filter.TVID != nil && issue.TVID != *filter.TVID {
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].ID > issues[j].ID })
+	return issues, nil
+}
+
+func (r issueRepo) Update(_ context.Context, issue *models.TVIssue) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.d.issues[issue.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	issue.UpdatedAt = time.Now()
+	r.s.d.issues[issue.ID] = *issue
+	return nil
+}
+
+// --- Notifications ---
+
+type notificationRepo struct{ s *Store }
+
+func (r notificationRepo) Create(_ context.Context, n *models.Notification) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	n.ID = uint(r.s.d.next("notifications"))
+	n.CreatedAt = time.Now()
+	r.s.d.notifications[n.ID] = *n
+	return nil
+}
+
+func (r notificationRepo) ListByUser(_ context.Context, userID string, limit int) ([]models.Notification, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	notifications := []models.Notification{}
+	for _, n := range r.s.d.notifications {
+		if n.UserID == userID {
+			notifications = append(notifications, n)
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool { return notifications[i].ID > notifications[j].ID })
+	return page(notifications, limit, 0), nil
+}
+
+// --- Idempotency keys ---
+
+type idempotencyKeyRepo struct{ s *Store }
+
+func (r idempotencyKeyRepo) Reserve(_ context.Context, k *models.IdempotencyKey, staleBefore time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	id := idempotencyKey{k.UserID, k.Key}
+	if existing, ok := r.s.d.idempotency[id]; ok && !existing.CreatedAt.Before(staleBefore) {
+		return false, nil
+	}
+	stored := *k
+	stored.StatusCode, stored.ResponseBody = 0, nil
+	r.s.d.idempotency[id] = stored
+	return true, nil
+}
+
+func (r idempotencyKeyRepo) Find(_ context.Context, userID, key string) (*models.IdempotencyKey, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	k, ok := r.s.d.idempotency[idempotencyKey{userID, key}]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &k, nil
+}
+
+func (r idempotencyKeyRepo) Complete(_ context.Context, userID, key string, statusCode int, body []byte) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	id := idempotencyKey{userID, key}
+	k, ok := r.s.d.idempotency[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	k.StatusCode, k.ResponseBody = statusCode, slices.Clone(body)
+	r.s.d.idempotency[id] = k
+	return nil
+}
+
+func (r idempotencyKeyRepo) Delete(_ context.Context, userID, key string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.d.idempotency, idempotencyKey{userID, key})
+	return nil
+}
+
+func (r idempotencyKeyRepo) DeleteBefore(_ context.Context, t time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for id, k := range r.s.d.idempotency {
+		if k.CreatedAt.Before(t) {
+			delete(r.s.d.idempotency, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// --- Rate limit buckets ---
+
+type rateLimitRepo struct{ s *Store }
+
+func (r rateLimitRepo) Take(_ context.Context, key string, capacity, rate float64, now time.Time) (float64, bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	tokens := capacity
+	if b, ok := r.s.d.rateBuckets[key]; ok {
+		elapsed := max(now.Sub(b.updated).Seconds(), 0)
+		tokens = min(capacity, b.tokens+elapsed*rate)
+		if b.updated.After(now) {
+			now = b.updated
+		}
+	}
+
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	r.s.d.rateBuckets[key] = rateBucket{tokens: tokens, updated: now}
+	return tokens, allowed, nil
+}
+
+func (r rateLimitRepo) DeleteIdle(_ context.Context, before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for key, b := range r.s.d.rateBuckets {
+		if b.updated.Before(before) {
+			delete(r.s.d.rateBuckets, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// --- Availability events ---
+
+type availabilityEventRepo struct{ s *Store }
+
+func (r availabilityEventRepo) Append(_ context.Context, e *models.AvailabilityEvent) error {
+	r.s.mu.Lock()
+	e.ID = int64(r.s.d.next("availability_events"))
+	e.CreatedAt = time.Now()
+	r.s.d.events = append(r.s.d.events, *e)
+	r.s.mu.Unlock()
+
+	if r.s.pending != nil {
+		*r.s.pending = append(*r.s.pending, *e)
+	} else {
+		r.s.listeners.notify(*e)
+	}
+	return nil
+}
+
+func (r availabilityEventRepo) Since(_ context.Context, afterID int64, locationID *int, limit int) ([]models.AvailabilityEvent, bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	complete := afterID == 0
+	events := []models.AvailabilityEvent{}
+	for _, e := range r.s.d.events {
+		if e.ID == afterID {
+			complete = true
+		}
+		if e.ID <= afterID || (locationID != nil && e.LocationID != *locationID) {
+			continue
+		}
+		if len(events) < limit {
+			events = append(events, e)
+		}
+	}
+	if !complete {
+		return nil, false, nil
+	}
+	return events, true, nil
+}
+
+func (r availabilityEventRepo) DeleteBefore(_ context.Context, t time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	kept := r.s.d.events[:0]
+	for _, e := range r.s.d.events {
+		if !e.CreatedAt.Before(t) {
+			kept = append(kept, e)
+		}
+	}
+	n := int64(len(r.s.d.events) - len(kept))
+	r.s.d.events = kept
+	return n, nil
+}
+
+func (r availabilityEventRepo) Listen(ctx context.Context, fn func(models.AvailabilityEvent)) error {
+	l := r.s.listeners
+	l.mu.Lock()
+	id := l.next
+	l.next++
+	l.fns[id] = fn
+	l.mu.Unlock()
+
+	<-ctx.Done()
+
+	l.mu.Lock()
+	delete(l.fns, id)
+	l.mu.Unlock()
+	return ctx.Err()
+}
+
+func (l *listeners) notify(e models.AvailabilityEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, fn := range l.fns {
+		fn(e)
+	}
+}
+
+// --- Devices ---
+
+type deviceRepo struct{ s *Store }
+
+func (r deviceRepo) Create(_ context.Context, d *models.Device) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	d.ID = uint(r.s.d.next("devices"))
+	d.CreatedAt = time.Now()
+	r.s.d.devices[d.ID] = *d
+	return nil
+}
+
+func (r deviceRepo) FindByID(_ context.Context, id uint) (*models.Device, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	d, ok := r.s.d.devices[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &d, nil
+}
+
+func (r deviceRepo) FindByTokenHash(_ context.Context, hash string) (*models.Device, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, d := range r.s.d.devices {
+		if d.TokenHash == hash && d.RevokedAt == nil {
+			return &d, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r deviceRepo) List(_ context.Context) ([]models.Device, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	devices := []models.Device{}
+	for _, d := range r.s.d.devices {
+		devices = append(devices, d)
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].ID < devices[j].ID })
+	return devices, nil
+}
+
+func (r deviceRepo) Revoke(_ context.Context, id uint, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if d, ok := r.s.d.devices[id]; ok && d.RevokedAt == nil {
+		d.RevokedAt = &at
+		r.s.d.devices[id] = d
+	}
+	return nil
+}
+
+func (r deviceRepo) Touch(_ context.Context, id uint, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if d, ok := r.s.d.devices[id]; ok {
+		d.LastSeenAt = &at
+		r.s.d.devices[id] = d
+	}
+	return nil
+}
+
+// --- Waitlist ---
+
+type waitlistRepo struct{ s *Store }
+
+func (r waitlistRepo) Create(_ context.Context, e *models.WaitlistEntry) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	e.ID = uint(r.s.d.next("waitlist_entries"))
+	e.CreatedAt, e.UpdatedAt = now, now
+	if e.Status == "" {
+		e.Status = models.WaitlistWaiting
+	}
+	r.s.d.waitlist[e.ID] = *e
+	return nil
+}
+
+func (r waitlistRepo) FindByID(_ context.Context, id uint) (*models.WaitlistEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	e, ok := r.s.d.waitlist[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &e, nil
+}
+
+func (r waitlistRepo) Update(_ context.Context, e *models.WaitlistEntry) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.d.waitlist[e.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	e.UpdatedAt = time.Now()
+	r.s.d.waitlist[e.ID] = *e
+	return nil
+}
+
+// filter mengembalikan entri yang memenuhi keep, diurutkan berdasarkan slot
+// lalu urutan pendaftaran; pemanggil harus memegang lock.
+func (r waitlistRepo) filter(keep func(models.WaitlistEntry) bool) []models.WaitlistEntry {
+	out := []models.WaitlistEntry{}
+	for _, e := range r.s.d.waitlist {
+		if keep(e) {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TimeSlot != out[j].TimeSlot {
+			return out[i].TimeSlot < out[j].TimeSlot
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+func (r waitlistRepo) ListOpenByUser(_ context.Context, userID string) ([]models.WaitlistEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.filter(func(e models.WaitlistEntry) bool {
+		return e.UserID == userID && (e.Status == models.WaitlistWaiting || e.Status == models.WaitlistOffered)
+	}), nil
+}
+
+func (r waitlistRepo) NextWaiting(_ context.Context, tvID, locationID int, consoleType, slot string) (*models.WaitlistEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	found := r.filter(func(e models.WaitlistEntry) bool {
+		if e.Status != models.WaitlistWaiting || e.TimeSlot != slot {
+			return false
+		}
+		if e.TVID != nil {
+			return *e.TVID == tvID
+		}
+		return e.LocationID == locationID && e.ConsoleType == consoleType
+	})
+	if len(found) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &found[0], nil
+}
+
+func (r waitlistRepo) ListOffers(_ context.Context, tvID int, from, to string) ([]models.WaitlistEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.filter(func(e models.WaitlistEntry) bool {
+		return e.Status == models.WaitlistOffered && e.OfferedTVID != nil && *e.OfferedTVID == tvID &&
+			e.TimeSlot >= from && e.TimeSlot < to
+	}), nil
+}
+
+func (r waitlistRepo) ListExpiredOffers(_ context.Context, now time.Time) ([]models.WaitlistEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.filter(func(e models.WaitlistEntry) bool {
+		return e.Status == models.WaitlistOffered && e.OfferExpiresAt != nil && !e.OfferExpiresAt.After(now)
+	}), nil
+}
+
+func (r waitlistRepo) ExpireWaiting(_ context.Context, upTo string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	now := time.Now()
+	for id, e := range r.s.d.waitlist {
+		if e.Status == models.WaitlistWaiting && e.TimeSlot <= upTo {
+			e.Status = models.WaitlistExpired
+			e.UpdatedAt = now
+			r.s.d.waitlist[id] = e
+			n++
+		}
+	}
+	return n, nil
+}
+
+// --- Reservation participants ---
+
+type participantRepo struct{ s *Store }
+
+func (r participantRepo) Create(_ context.Context, p *models.ReservationParticipant) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	p.ID = uint(r.s.d.next("reservation_participants"))
+	p.CreatedAt, p.UpdatedAt = now, now
+	if p.Status == "" {
+		p.Status = models.ParticipantInvited
+	}
+	r.s.d.participants[p.ID] = *p
+	return nil
+}
+
+func (r participantRepo) FindByID(_ context.Context, id uint) (*models.ReservationParticipant, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	p, ok := r.s.d.participants[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &p, nil
+}
+
+func (r participantRepo) list(keep func(models.ReservationParticipant) bool) []models.ReservationParticipant {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	out := []models.ReservationParticipant{}
+	for _, p := range r.s.d.participants {
+		if keep(p) {
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func (r participantRepo) ListByReservation(_ context.Context, reservationID uint) ([]models.ReservationParticipant, error) {
+	return r.list(func(p models.ReservationParticipant) bool { return p.ReservationID == reservationID }), nil
+}
+
+func (r participantRepo) ListInvitations(_ context.Context, userID string) ([]models.ReservationParticipant, error) {
+	out := r.list(func(p models.ReservationParticipant) bool {
+		return p.UserID == userID && p.Status == models.ParticipantInvited
+	})
+	slices.Reverse(out)
+	return out, nil
+}
+
+func (r participantRepo) Update(_ context.Context, p *models.ReservationParticipant) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.d.participants[p.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	p.UpdatedAt = time.Now()
+	r.s.d.participants[p.ID] = *p
+	return nil
+}
+
+func (r participantRepo) Delete(_ context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.d.participants, id)
+	return nil
+}
+
+// --- Reservation changes ---
+
+type reservationChangeRepo struct{ s *Store }
+
+func (r reservationChangeRepo) Create(_ context.Context, c *models.ReservationChange) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	c.ID = uint(r.s.d.next("reservation_changes"))
+	c.CreatedAt = time.Now()
+	r.s.d.changes[c.ID] = *c
+	return nil
+}
+
+func (r reservationChangeRepo) ListByReservation(_ context.Context, reservationID uint) ([]models.ReservationChange, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	out := []models.ReservationChange{}
+	for _, c := range r.s.d.changes {
+		if c.ReservationID == reservationID {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// --- Bookings ---
+
+type bookingRepo struct{ s *Store }
+
+func (r bookingRepo) Create(_ context.Context, b *models.Booking) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	b.ID = uint(r.s.d.next("bookings"))
+	b.CreatedAt = time.Now()
+	r.s.d.bookings[b.ID] = *b
+	return nil
+}
+
+// --- Reservation series ---
+
+type seriesRepo struct{ s *Store }
+
+// Slice di dalam series disalin agar perubahan pemanggil tidak ikut mengubah data tersimpan.
+func cloneSeries(series models.ReservationSeries) models.ReservationSeries {
+	series.TVIDs = slices.Clone(series.TVIDs)
+	series.ExceptionDates = slices.Clone(series.ExceptionDates)
+	series.Overrides = slices.Clone(series.Overrides)
+	return series
+}
+
+func (r seriesRepo) Create(_ context.Context, series *models.ReservationSeries) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	series.ID = uint(r.s.d.next("reservation_series"))
+	series.CreatedAt, series.UpdatedAt = now, now
+	if series.Status == "" {
+		series.Status = models.SeriesPending
+	}
+	r.s.d.series[series.ID] = cloneSeries(*series)
+	return nil
+}
+
+func (r seriesRepo) FindByID(_ context.Context, id uint) (*models.ReservationSeries, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	series, ok := r.s.d.series[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	series = cloneSeries(series)
+	return &series, nil
+}
+
+func (r seriesRepo) List(_ context.Context, filter repository.SeriesFilter) ([]models.ReservationSeries, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	list := []models.ReservationSeries{}
+	for _, series := range r.s.d.series {
+		if filter.LocationIDs != nil && !slices.Contains(filter.LocationIDs, series.LocationID) {
+			continue
+		}
+		if filter.OrganizerID != "" && series.OrganizerID != filter.OrganizerID {
+			continue
+		}
+		if filter.Status != "" && series.Status != filter.Status {
+			continue
+		}
+		list = append(list, cloneSeries(series))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID > list[j].ID })
+	return list, nil
+}
+
+func (r seriesRepo) Update(_ context.Context, series *models.ReservationSeries) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.d.series[series.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	series.UpdatedAt = time.Now()
+	r.s.d.series[series.ID] = cloneSeries(*series)
+	return nil
+}
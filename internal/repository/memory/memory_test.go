@@ -0,0 +1,238 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"playcorner-be/internal/repository/memory"
+)
+
+var errRollback = errors.New("rollback")
+
+func TestTransaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		fail    bool
+		wantErr error
+		// wantCount adalah jumlah notifikasi u1 setelah transaksi.
+		wantCount int
+	}{
+		{name: "keeps changes when fn succeeds", wantCount: 2},
+		{name: "rolls back every change when fn fails", fail: true, wantErr: errRollback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := memory.New()
+			ctx := context.Background()
+
+			err := store.Transaction(ctx, func(tx repository.Store) error {
+				for _, msg := range []string{"first", "second"} {
+					if err := tx.Notifications().Create(ctx, &models.Notification{UserID: "u1", Message: msg}); err != nil {
+						return err
+					}
+				}
+				if tt.fail {
+					return errRollback
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			got, err := store.Notifications().ListByUser(ctx, "u1", 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.wantCount {
+				t.Fatalf("got %d notifications, want %d", len(got), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestTransactionRollbackKeepsSequence(t *testing.T) {
+	store := memory.New()
+	ctx := context.Background()
+
+	// ID yang dipakai transaksi yang gagal ikut dikembalikan, seperti data lainnya
+	_ = store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Notifications().Create(ctx, &models.Notification{UserID: "u1"}); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	n := &models.Notification{UserID: "u1"}
+	if err := store.Notifications().Create(ctx, n); err != nil {
+		t.Fatal(err)
+	}
+	if n.ID != 1 {
+		t.Fatalf("notification ID = %d, want 1", n.ID)
+	}
+}
+
+func TestReservationsHoldSlots(t *testing.T) {
+	store := memory.New()
+	ctx := context.Background()
+	slots := []string{"2026-03-02T02:00:00Z", "2026-03-02T03:00:00Z", "2026-03-02T04:00:00Z"}
+	for _, slot := range slots {
+		if err := store.Reservations().Create(ctx, &models.Reservation{TVID: 1, BorrowerID: "u1", TimeSlot: slot}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Reservations().UpdateStatus(ctx, 2, models.ReservationCancelled); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		slot   string
+		wantID uint
+	}{
+		{name: "finds a booked slot", slot: slots[0], wantID: 1},
+		{name: "ignores a cancelled reservation", slot: slots[1]},
+		{name: "ignores another slot", slot: "2026-03-02T05:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := store.Reservations().FindActiveBySlot(ctx, 1, tt.slot)
+			if tt.wantID == 0 {
+				if !errors.Is(err, repository.ErrNotFound) {
+					t.Fatalf("error = %v, want ErrNotFound", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.ID != tt.wantID {
+				t.Fatalf("found reservation %d, want %d", res.ID, tt.wantID)
+			}
+		})
+	}
+
+	// Rentang from <= slot < to, tanpa reservasi yang dibatalkan
+	active, err := store.Reservations().ListActiveByTV(ctx, 1, slots[0], slots[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 1 || active[0].ID != 1 {
+		t.Fatalf("active reservations = %+v, want only reservation 1", active)
+	}
+}
+
+func TestReservationsRejectDuplicateSlot(t *testing.T) {
+	checkedOut := time.Date(2026, time.March, 2, 2, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		existing models.Reservation
+		wantErr  error
+	}{
+		{name: "rejects a slot held by a booking", existing: models.Reservation{Status: models.ReservationBooked}, wantErr: repository.ErrDuplicate},
+		{name: "rejects a slot held by a session", existing: models.Reservation{Status: models.ReservationCheckedIn}, wantErr: repository.ErrDuplicate},
+		{name: "frees a cancelled slot", existing: models.Reservation{Status: models.ReservationCancelled}},
+		{name: "frees a slot after check-out", existing: models.Reservation{Status: models.ReservationCheckedIn, CheckedOutAt: &checkedOut}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := memory.New()
+			ctx := context.Background()
+			existing := tt.existing
+			existing.TVID, existing.BorrowerID, existing.TimeSlot = 1, "u1", "2026-03-02T02:00:00Z"
+			if err := store.Reservations().Create(ctx, &existing); err != nil {
+				t.Fatal(err)
+			}
+
+			err := store.Reservations().Create(ctx, &models.Reservation{TVID: 1, BorrowerID: "u2", TimeSlot: "2026-03-02T02:00:00Z"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTVsAreHydrated(t *testing.T) {
+	store := memory.New()
+	ctx := context.Background()
+	loc := store.AddLocation(models.Location{Name: "FILKOM", Timezone: "Asia/Jakarta", OpenHour: 8, CloseHour: 22})
+	game := store.AddGame(models.Game{Title: "FC"})
+	store.AddTV(models.TVInfo{ID: 1, ConsoleType: "PS5", LocationID: loc.ID}, game.ID)
+
+	tv, err := store.TVs().FindByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tv.Location == nil || tv.Location.ID != loc.ID {
+		t.Fatalf("TV location = %+v, want location %d", tv.Location, loc.ID)
+	}
+	if len(tv.Games) != 1 || tv.Games[0].ID != game.ID {
+		t.Fatalf("TV games = %+v, want game %d", tv.Games, game.ID)
+	}
+
+	if _, err := store.TVs().FindByID(ctx, 2); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestIssuesFilterByLocation(t *testing.T) {
+	store := memory.New()
+	ctx := context.Background()
+	a := store.AddLocation(models.Location{Name: "A"})
+	b := store.AddLocation(models.Location{Name: "B"})
+	store.AddTV(models.TVInfo{ID: 1, LocationID: a.ID})
+	store.AddTV(models.TVInfo{ID: 2, LocationID: b.ID})
+	for _, tvID := range []int{1, 2} {
+		if err := store.Issues().Create(ctx, &models.TVIssue{TVID: tvID, Status: models.IssueOpen}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		locationIDs []int
+		want        int
+	}{
+		{name: "nil means every location", want: 2},
+		{name: "empty means no location", locationIDs: []int{}, want: 0},
+		{name: "limits to the given locations", locationIDs: []int{b.ID}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := store.Issues().List(ctx, repository.IssueFilter{LocationIDs: tt.locationIDs})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(issues) != tt.want {
+				t.Fatalf("got %d issues, want %d", len(issues), tt.want)
+			}
+		})
+	}
+}
+
+func TestStaffAssignments(t *testing.T) {
+	store := memory.New()
+	ctx := context.Background()
+	for _, id := range []int{3, 1, 2} {
+		if err := store.Staff().Assign(ctx, "s1", id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Staff().Unassign(ctx, "s1", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := store.Staff().LocationIDs(ctx, "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Fatalf("location IDs = %v, want [1 3]", ids)
+	}
+	if ok, _ := store.Staff().IsAssigned(ctx, "s1", 2); ok {
+		t.Fatal("s1 is still assigned to location 2")
+	}
+}
@@ -0,0 +1,744 @@
+// Package postgres
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Store adalah implementasi repository.Store di atas GORM/Postgres.
+type Store struct {
+	db *gorm.DB
+}
+
+// NewStore membuat Store dari koneksi GORM yang sudah terbuka.
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) Users() repository.UserRepository                 { return userRepo{s.db} }
+func (s *Store) TVs() repository.TVRepository                     { return tvRepo{s.db} }
+func (s *Store) Games() repository.GameRepository                 { return gameRepo{s.db} }
+func (s *Store) Reservations() repository.ReservationRepository   { return reservationRepo{s.db} }
+func (s *Store) Bookings() repository.BookingRepository           { return bookingRepo{s.db} }
+func (s *Store) Participants() repository.ParticipantRepository   { return participantRepo{s.db} }
+func (s *Store) Locations() repository.LocationRepository         { return locationRepo{s.db} }
+func (s *Store) Staff() repository.StaffRepository                { return staffRepo{s.db} }
+func (s *Store) Issues() repository.IssueRepository               { return issueRepo{s.db} }
+func (s *Store) Notifications() repository.NotificationRepository { return notificationRepo{s.db} }
+func (s *Store) IdempotencyKeys() repository.IdempotencyKeyRepository {
+	return idempotencyKeyRepo{s.db}
+}
+func (s *Store) RateLimits() repository.RateLimitRepository { return rateLimitRepo{s.db} }
+func (s *Store) AvailabilityEvents() repository.AvailabilityEventRepository {
+	return availabilityEventRepo{s.db}
+}
+func (s *Store) Devices() repository.DeviceRepository    { return deviceRepo{s.db} }
+func (s *Store) Waitlist() repository.WaitlistRepository { return waitlistRepo{s.db} }
+func (s *Store) Series() repository.SeriesRepository     { return seriesRepo{s.db} }
+func (s *Store) ReservationChanges() repository.ReservationChangeRepository {
+	return reservationChangeRepo{s.db}
+}
+
+func (s *Store) Transaction(ctx context.Context, fn func(tx repository.Store) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&Store{db: tx})
+	})
+}
+
+// notFound menerjemahkan gorm.ErrRecordNotFound menjadi repository.ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+// duplicate menerjemahkan pelanggaran constraint unik menjadi repository.ErrDuplicate.
+func duplicate(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+// --- Users ---
+
+type userRepo struct{ db *gorm.DB }
+
+func (r userRepo) FindByID(ctx context.Context, id string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+// Lock memakai advisory lock transaksi karena peminjam belum tentu punya baris
+// yang bisa dikunci dan kunci harus bertahan sampai commit atau rollback.
+func (r userRepo) Lock(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(hashtext('borrower:' || ?))", id).Error
+}
+
+// --- TVs ---
+
+type tvRepo struct{ db *gorm.DB }
+
+func (r tvRepo) List(ctx context.Context, filter repository.TVFilter) ([]models.TVInfo, error) {
+	query := r.db.WithContext(ctx).Preload("Games").Preload("Location").Order("id")
+	if filter.LocationID != nil {
+		query = query.Where("location_id = ?", *filter.LocationID)
+	}
+
+	tvs := []models.TVInfo{}
+	err := query.Find(&tvs).Error
+	return tvs, err
+}
+
+func (r tvRepo) FindByID(ctx context.Context, id int) (*models.TVInfo, error) {
+	var tv models.TVInfo
+	if err := r.db.WithContext(ctx).Preload("Games").Preload("Location").First(&tv, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &tv, nil
+}
+
+func (r tvRepo) Lock(ctx context.Context, ids ...int) error {
+	var locked []int
+	return r.db.WithContext(ctx).Model(&models.TVInfo{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("id").
+		Pluck("id", &locked).Error
+}
+
+func (r tvRepo) UpdateService(ctx context.Context, id int, outOfService bool, reason string) error {
+	result := r.db.WithContext(ctx).Model(&models.TVInfo{ID: id}).
+		Select("out_of_service", "out_of_service_reason").
+		Updates(models.TVInfo{OutOfService: outOfService, OutOfServiceReason: reason})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return result.Error
+}
+
+// --- Games ---
+
+type gameRepo struct{ db *gorm.DB }
+
+func (r gameRepo) List(ctx context.Context) ([]models.Game, error) {
+	games := []models.Game{}
+	err := r.db.WithContext(ctx).Order("id").Find(&games).Error
+	return games, err
+}
+
+func (r gameRepo) ListByTV(ctx context.Context, tvID int) ([]models.Game, error) {
+	games := []models.Game{}
+	err := r.db.WithContext(ctx).
+		Joins("JOIN tv_info_games ON tv_info_games.game_id = games.id").
+		Where("tv_info_games.tv_info_id = ?", tvID).
+		Order("games.id").
+		Find(&games).Error
+	return games, err
+}
+
+// --- Reservations ---
+
+type reservationRepo struct{ db *gorm.DB }
+
+// releasedStatuses adalah status reservasi yang tidak lagi memegang slotnya.
+var releasedStatuses = []string{models.ReservationCancelled, models.ReservationNoShow}
+
+func (r reservationRepo) Create(ctx context.Context, res *models.Reservation) error {
+	return duplicate(r.db.WithContext(ctx).Create(res).Error)
+}
+
+func (r reservationRepo) FindByID(ctx context.Context, id uint) (*models.Reservation, error) {
+	var res models.Reservation
+	if err := r.db.WithContext(ctx).First(&res, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &res, nil
+}
+
+func (r reservationRepo) Update(ctx context.Context, res *models.Reservation) error {
+	return duplicate(r.db.WithContext(ctx).Save(res).Error)
+}
+
+func (r reservationRepo) FindActiveBySlot(ctx context.Context, tvID int, slot string) (*models.Reservation, error) {
+	var res models.Reservation
+	if err := r.db.WithContext(ctx).
+		Where("tv_id = ? AND time_slot = ? AND status NOT IN ?", tvID, slot, releasedStatuses).
+		First(&res).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &res, nil
+}
+
+func (r reservationRepo) ListActiveByTV(ctx context.Context, tvID int, from, to string) ([]models.Reservation, error) {
+	reservations := []models.Reservation{}
+	err := r.db.WithContext(ctx).
+		Where("tv_id = ? AND time_slot >= ? AND time_slot < ? AND status NOT IN ?", tvID, from, to, releasedStatuses).
+		Order("time_slot").
+		Find(&reservations).Error
+	return reservations, err
+}
+
+func (r reservationRepo) ListActiveByBorrower(ctx context.Context, borrowerID string, from, to string) ([]models.Reservation, error) {
+	reservations := []models.Reservation{}
+	err := r.db.WithContext(ctx).
+		Where("borrower_id = ? AND time_slot >= ? AND time_slot < ? AND status NOT IN ?", borrowerID, from, to, releasedStatuses).
+		Order("time_slot").
+		Find(&reservations).Error
+	return reservations, err
+}
+
+func (r reservationRepo) ListBookedFrom(ctx context.Context, tvID int, from string) ([]models.Reservation, error) {
+	reservations := []models.Reservation{}
+	err := r.db.WithContext(ctx).
+		Where("tv_id = ? AND status = ? AND time_slot >= ?", tvID, models.ReservationBooked, from).
+		Order("time_slot").
+		Find(&reservations).Error
+	return reservations, err
+}
+
+func (r reservationRepo) ListBySeries(ctx context.Context, seriesID uint, from string) ([]models.Reservation, error) {
+	reservations := []models.Reservation{}
+	err := r.db.WithContext(ctx).
+		Where("series_id = ? AND time_slot >= ? AND status NOT IN ?", seriesID, from, releasedStatuses).
+		Order("time_slot, tv_id").
+		Find(&reservations).Error
+	return reservations, err
+}
+
+func (r reservationRepo) ListByUser(ctx context.Context, userID string, limit, offset int) ([]models.Reservation, int64, error) {
+	participating := r.db.Model(&models.ReservationParticipant{}).
+		Select("reservation_id").
+		Where("user_id = ? AND status = ?", userID, models.ParticipantAccepted)
+	ofUser := func(db *gorm.DB) *gorm.DB {
+		return db.Where("borrower_id = ? OR id IN (?)", userID, participating)
+	}
+
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.Reservation{}).Scopes(ofUser).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	reservations := []models.Reservation{}
+	err := r.db.WithContext(ctx).
+		Scopes(ofUser).
+		Order("created_at desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&reservations).Error
+	return reservations, total, err
+}
+
+func (r reservationRepo) UpdateStatus(ctx context.Context, id uint, status string) error {
+	return r.db.WithContext(ctx).Model(&models.Reservation{}).Where("id = ?", id).Update("status", status).Error
+}
+
+func (r reservationRepo) TransitionStatus(ctx context.Context, from, to, upTo string) ([]models.Reservation, error) {
+	reservations := []models.Reservation{}
+	err := r.db.WithContext(ctx).Model(&reservations).
+		Clauses(clause.Returning{}).
+		Where("status = ? AND time_slot <= ?", from, upTo).
+		Update("status", to).Error
+	return reservations, err
+}
+
+// --- Locations ---
+
+type locationRepo struct{ db *gorm.DB }
+
+func (r locationRepo) List(ctx context.Context) ([]models.Location, error) {
+	locations := []models.Location{}
+	err := r.db.WithContext(ctx).Order("id").Find(&locations).Error
+	return locations, err
+}
+
+func (r locationRepo) FindByID(ctx context.Context, id int) (*models.Location, error) {
+	var location models.Location
+	if err := r.db.WithContext(ctx).First(&location, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &location, nil
+}
+
+func (r locationRepo) Create(ctx context.Context, l *models.Location) error {
+	return r.db.WithContext(ctx).Create(l).Error
+}
+
+func (r locationRepo) Update(ctx context.Context, l *models.Location) error {
+	return r.db.WithContext(ctx).Save(l).Error
+}
+
+// --- Staff assignments ---
+
+type staffRepo struct{ db *gorm.DB }
+
+func (r staffRepo) IsAssigned(ctx context.Context, userID string, locationID int) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.StaffAssignment{}).
+		Where("user_id = ? AND location_id = ?", userID, locationID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r staffRepo) LocationIDs(ctx context.Context, userID string) ([]int, error) {
+	ids := []int{}
+	err := r.db.WithContext(ctx).Model(&models.StaffAssignment{}).Where("user_id = ?", userID).Pluck("location_id", &ids).Error
+	return ids, err
+}
+
+func (r staffRepo) Assign(ctx context.Context, userID string, locationID int) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.StaffAssignment{UserID: userID, LocationID: locationID}).Error
+}
+
+func (r staffRepo) Unassign(ctx context.Context, userID string, locationID int) error {
+	return r.db.WithContext(ctx).Where("user_id = ? AND location_id = ?", userID, locationID).
+		Delete(&models.StaffAssignment{}).Error
+}
+
+// --- Issues ---
+
+type issueRepo struct{ db *gorm.DB }
+
+func (r issueRepo) Create(ctx context.Context, issue *models.TVIssue) error {
+	return r.db.WithContext(ctx).Create(issue).Error
+}
+
+func (r issueRepo) FindByID(ctx context.Context, id uint) (*models.TVIssue, error) {
+	var issue models.TVIssue
+	if err := r.db.WithContext(ctx).First(&issue, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &issue, nil
+}
+
+func (r issueRepo) List(ctx context.Context, filter repository.IssueFilter) ([]models.TVIssue, error) {
+	query := r.db.WithContext(ctx).Order("created_at desc")
+	if filter.LocationIDs != nil {
+		query = query.Where("tv_id IN (?)", r.db.Model(&models.TVInfo{}).Select("id").Where("location_id IN ?", filter.LocationIDs))
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.TVID != nil {
+		query = query.Where("tv_id = ?", *filter.TVID)
+	}
+
+	issues := []models.TVIssue{}
+	err := query.Find(&issues).Error
+	return issues, err
+}
+
+func (r issueRepo) Update(ctx context.Context, issue *models.TVIssue) error {
+	return r.db.WithContext(ctx).Save(issue).Error
+}
+
+// --- Notifications ---
+
+type notificationRepo struct{ db *gorm.DB }
+
+func (r notificationRepo) Create(ctx context.Context, n *models.Notification) error {
+	return r.db.WithContext(ctx).Create(n).Error
+}
+
+func (r notificationRepo) ListByUser(ctx context.Context, userID string, limit int) ([]models.Notification, error) {
+	notifications := []models.Notification{}
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+// --- Idempotency keys ---
+
+type idempotencyKeyRepo struct{ db *gorm.DB }
+
+func (r idempotencyKeyRepo) Reserve(ctx context.Context, k *models.IdempotencyKey, staleBefore time.Time) (bool, error) {
+	// Insert atau timpa kunci kedaluwarsa dalam satu statement agar dua request
+	// bersamaan dengan kunci yang sama tidak bisa sama-sama lolos
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"request_hash":  k.RequestHash,
+			"status_code":   0,
+			"response_body": nil,
+			"created_at":    k.CreatedAt,
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Lt{Column: clause.Column{Table: "idempotency_keys", Name: "created_at"}, Value: staleBefore},
+		}},
+	}).Create(k)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r idempotencyKeyRepo) Find(ctx context.Context, userID, key string) (*models.IdempotencyKey, error) {
+	var k models.IdempotencyKey
+	if err := r.db.WithContext(ctx).First(&k, "user_id = ? AND key = ?", userID, key).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &k, nil
+}
+
+func (r idempotencyKeyRepo) Complete(ctx context.Context, userID, key string, statusCode int, body []byte) error {
+	return r.db.WithContext(ctx).Model(&models.IdempotencyKey{}).
+		Where("user_id = ? AND key = ?", userID, key).
+		Updates(map[string]any{"status_code": statusCode, "response_body": body}).Error
+}
+
+func (r idempotencyKeyRepo) Delete(ctx context.Context, userID, key string) error {
+	return r.db.WithContext(ctx).Where("user_id = ? AND key = ?", userID, key).
+		Delete(&models.IdempotencyKey{}).Error
+}
+
+func (r idempotencyKeyRepo) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", t).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
+
+// --- Rate limit buckets ---
+
+type rateLimitRepo struct{ db *gorm.DB }
+
+// refillExpr adalah isi bucket setelah diisi ulang sejak pemakaian terakhir.
+const refillExpr = `LEAST(CAST(@capacity AS float8),
+	b.tokens + GREATEST(EXTRACT(EPOCH FROM CAST(@now AS timestamptz) - b.updated_at), 0) * CAST(@rate AS float8))`
+
+// takeSQL mengisi ulang dan mengambil token secara atomik; semua ekspresi SET
+// membaca nilai baris sebelum diubah.
+const takeSQL = `
+INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+VALUES (@key, CAST(@capacity AS float8) - 1, true, @now)
+ON CONFLICT (key) DO UPDATE SET
+	allowed = ` + refillExpr + ` >= 1,
+	tokens = ` + refillExpr + ` - CASE WHEN ` + refillExpr + ` >= 1 THEN 1 ELSE 0 END,
+	updated_at = GREATEST(b.updated_at, CAST(@now AS timestamptz))
+RETURNING tokens, allowed`
+
+func (r rateLimitRepo) Take(ctx context.Context, key string, capacity, rate float64, now time.Time) (float64, bool, error) {
+	var (
+		remaining float64
+		allowed   bool
+	)
+	err := r.db.WithContext(ctx).Raw(takeSQL,
+		sql.Named("key", key), sql.Named("capacity", capacity), sql.Named("rate", rate), sql.Named("now", now),
+	).Row().Scan(&remaining, &allowed)
+	return remaining, allowed, err
+}
+
+func (r rateLimitRepo) DeleteIdle(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Exec("DELETE FROM rate_limit_buckets WHERE updated_at < ?", before)
+	return result.RowsAffected, result.Error
+}
+
+// --- Availability events ---
+
+// availabilityChannel adalah kanal LISTEN/NOTIFY untuk event ketersediaan.
+const availabilityChannel = "availability_events"
+
+type availabilityEventRepo struct{ db *gorm.DB }
+
+func (r availabilityEventRepo) Append(ctx context.Context, e *models.AvailabilityEvent) error {
+	// NOTIFY di dalam transaksi baru dikirim saat commit dan dibuang saat rollback
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(e).Error; err != nil {
+			return err
+		}
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		return tx.Exec("SELECT pg_notify(?, ?)", availabilityChannel, string(payload)).Error
+	})
+}
+
+func (r availabilityEventRepo) Since(ctx context.Context, afterID int64, locationID *int, limit int) ([]models.AvailabilityEvent, bool, error) {
+	db := r.db.WithContext(ctx)
+	if afterID > 0 {
+		var found int64
+		if err := db.Model(&models.AvailabilityEvent{}).Where("id = ?", afterID).Count(&found).Error; err != nil {
+			return nil, false, err
+		}
+		if found == 0 {
+			return nil, false, nil
+		}
+	}
+
+	events := []models.AvailabilityEvent{}
+	query := db.Where("id > ?", afterID)
+	if locationID != nil {
+		query = query.Where("location_id = ?", *locationID)
+	}
+	err := query.Order("id").Limit(limit).Find(&events).Error
+	return events, true, err
+}
+
+func (r availabilityEventRepo) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", t).Delete(&models.AvailabilityEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// Listen memakai satu koneksi khusus dari pool selama ctx berjalan.
+func (r availabilityEventRepo) Listen(ctx context.Context, fn func(models.AvailabilityEvent)) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Koneksi selalu dibuang setelahnya agar koneksi yang masih LISTEN tidak
+	// kembali ke pool
+	var listenErr error
+	_ = conn.Raw(func(driverConn any) error {
+		listenErr = listen(ctx, driverConn, fn)
+		return driver.ErrBadConn
+	})
+	return listenErr
+}
+
+func listen(ctx context.Context, driverConn any, fn func(models.AvailabilityEvent)) error {
+	pgConn, ok := driverConn.(*stdlib.Conn)
+	if !ok {
+		return fmt.Errorf("LISTEN needs a pgx connection, got %T", driverConn)
+	}
+	if _, err := pgConn.Conn().Exec(ctx, "LISTEN "+availabilityChannel); err != nil {
+		return err
+	}
+	for {
+		n, err := pgConn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var e models.AvailabilityEvent
+		if err := json.Unmarshal([]byte(n.Payload), &e); err != nil {
+			return fmt.Errorf("decoding availability event: %w", err)
+		}
+		fn(e)
+	}
+}
+
+// --- Devices ---
+
+type deviceRepo struct{ db *gorm.DB }
+
+func (r deviceRepo) Create(ctx context.Context, d *models.Device) error {
+	return r.db.WithContext(ctx).Create(d).Error
+}
+
+func (r deviceRepo) FindByID(ctx context.Context, id uint) (*models.Device, error) {
+	var d models.Device
+	if err := r.db.WithContext(ctx).First(&d, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &d, nil
+}
+
+func (r deviceRepo) FindByTokenHash(ctx context.Context, hash string) (*models.Device, error) {
+	var d models.Device
+	if err := r.db.WithContext(ctx).First(&d, "token_hash = ? AND revoked_at IS NULL", hash).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &d, nil
+}
+
+func (r deviceRepo) List(ctx context.Context) ([]models.Device, error) {
+	devices := []models.Device{}
+	err := r.db.WithContext(ctx).Order("id").Find(&devices).Error
+	return devices, err
+}
+
+func (r deviceRepo) Revoke(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Device{}).
+		Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", at).Error
+}
+
+func (r deviceRepo) Touch(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Device{}).Where("id = ?", id).Update("last_seen_at", at).Error
+}
+
+// --- Waitlist ---
+
+type waitlistRepo struct{ db *gorm.DB }
+
+func (r waitlistRepo) Create(ctx context.Context, e *models.WaitlistEntry) error {
+	return r.db.WithContext(ctx).Create(e).Error
+}
+
+func (r waitlistRepo) FindByID(ctx context.Context, id uint) (*models.WaitlistEntry, error) {
+	var e models.WaitlistEntry
+	if err := r.db.WithContext(ctx).First(&e, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &e, nil
+}
+
+func (r waitlistRepo) Update(ctx context.Context, e *models.WaitlistEntry) error {
+	return r.db.WithContext(ctx).Save(e).Error
+}
+
+func (r waitlistRepo) ListOpenByUser(ctx context.Context, userID string) ([]models.WaitlistEntry, error) {
+	entries := []models.WaitlistEntry{}
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status IN ?", userID, []string{models.WaitlistWaiting, models.WaitlistOffered}).
+		Order("time_slot, id").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r waitlistRepo) NextWaiting(ctx context.Context, tvID, locationID int, consoleType, slot string) (*models.WaitlistEntry, error) {
+	var e models.WaitlistEntry
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND time_slot = ?", models.WaitlistWaiting, slot).
+		Where("tv_id = ? OR (tv_id IS NULL AND location_id = ? AND console_type = ?)", tvID, locationID, consoleType).
+		Order("created_at, id").
+		First(&e).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &e, nil
+}
+
+func (r waitlistRepo) ListOffers(ctx context.Context, tvID int, from, to string) ([]models.WaitlistEntry, error) {
+	entries := []models.WaitlistEntry{}
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND offered_tv_id = ? AND time_slot >= ? AND time_slot < ?", models.WaitlistOffered, tvID, from, to).
+		Order("time_slot").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r waitlistRepo) ListExpiredOffers(ctx context.Context, now time.Time) ([]models.WaitlistEntry, error) {
+	entries := []models.WaitlistEntry{}
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND offer_expires_at <= ?", models.WaitlistOffered, now).
+		Order("offer_expires_at, id").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r waitlistRepo) ExpireWaiting(ctx context.Context, upTo string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.WaitlistEntry{}).
+		Where("status = ? AND time_slot <= ?", models.WaitlistWaiting, upTo).
+		Update("status", models.WaitlistExpired)
+	return result.RowsAffected, result.Error
+}
+
+// --- Reservation participants ---
+
+type participantRepo struct{ db *gorm.DB }
+
+func (r participantRepo) Create(ctx context.Context, p *models.ReservationParticipant) error {
+	return r.db.WithContext(ctx).Create(p).Error
+}
+
+func (r participantRepo) FindByID(ctx context.Context, id uint) (*models.ReservationParticipant, error) {
+	var p models.ReservationParticipant
+	if err := r.db.WithContext(ctx).First(&p, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &p, nil
+}
+
+func (r participantRepo) ListByReservation(ctx context.Context, reservationID uint) ([]models.ReservationParticipant, error) {
+	participants := []models.ReservationParticipant{}
+	err := r.db.WithContext(ctx).Where("reservation_id = ?", reservationID).Order("id").Find(&participants).Error
+	return participants, err
+}
+
+func (r participantRepo) ListInvitations(ctx context.Context, userID string) ([]models.ReservationParticipant, error) {
+	participants := []models.ReservationParticipant{}
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, models.ParticipantInvited).
+		Order("id desc").
+		Find(&participants).Error
+	return participants, err
+}
+
+func (r participantRepo) Update(ctx context.Context, p *models.ReservationParticipant) error {
+	return r.db.WithContext(ctx).Save(p).Error
+}
+
+func (r participantRepo) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.ReservationParticipant{}, id).Error
+}
+
+// --- Reservation changes ---
+
+type reservationChangeRepo struct{ db *gorm.DB }
+
+func (r reservationChangeRepo) Create(ctx context.Context, c *models.ReservationChange) error {
+	return r.db.WithContext(ctx).Create(c).Error
+}
+
+func (r reservationChangeRepo) ListByReservation(ctx context.Context, reservationID uint) ([]models.ReservationChange, error) {
+	changes := []models.ReservationChange{}
+	err := r.db.WithContext(ctx).Where("reservation_id = ?", reservationID).Order("id").Find(&changes).Error
+	return changes, err
+}
+
+// --- Bookings ---
+
+type bookingRepo struct{ db *gorm.DB }
+
+func (r bookingRepo) Create(ctx context.Context, b *models.Booking) error {
+	return r.db.WithContext(ctx).Create(b).Error
+}
+
+// --- Reservation series ---
+
+type seriesRepo struct{ db *gorm.DB }
+
+func (r seriesRepo) Create(ctx context.Context, series *models.ReservationSeries) error {
+	return r.db.WithContext(ctx).Create(series).Error
+}
+
+func (r seriesRepo) FindByID(ctx context.Context, id uint) (*models.ReservationSeries, error) {
+	var series models.ReservationSeries
+	if err := r.db.WithContext(ctx).First(&series, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &series, nil
+}
+
+func (r seriesRepo) List(ctx context.Context, filter repository.SeriesFilter) ([]models.ReservationSeries, error) {
+	series := []models.ReservationSeries{}
+	if filter.LocationIDs != nil && len(filter.LocationIDs) == 0 {
+		return series, nil
+	}
+
+	query := r.db.WithContext(ctx).Order("id desc")
+	if filter.LocationIDs != nil {
+		query = query.Where("location_id IN ?", filter.LocationIDs)
+	}
+	if filter.OrganizerID != "" {
+		query = query.Where("organizer_id = ?", filter.OrganizerID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	err := query.Find(&series).Error
+	return series, err
+}
+
+func (r seriesRepo) Update(ctx context.Context, series *models.ReservationSeries) error {
+	return r.db.WithContext(ctx).Save(series).Error
+}
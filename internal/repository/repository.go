@@ -0,0 +1,231 @@
+// Package repository
+package repository
+
+import (
+	"context"
+	"errors"
+	"playcorner-be/internal/models"
+	"time"
+)
+
+// ErrNotFound dikembalikan oleh semua implementasi repository jika data tidak ditemukan.
+var ErrNotFound = errors.New("record not found")
+
+// ErrDuplicate dikembalikan jika data melanggar constraint unik, misalnya dua
+// reservasi aktif pada slot dan TV yang sama.
+var ErrDuplicate = errors.New("duplicate record")
+
+// Store mengumpulkan semua repository dan menyediakan transaksi. Implementasi
+// Postgres dipakai di produksi, implementasi memory dipakai untuk pengujian cepat.
+type Store interface {
+	Users() UserRepository
+	TVs() TVRepository
+	Games() GameRepository
+	Reservations() ReservationRepository
+	Bookings() BookingRepository
+	ReservationChanges() ReservationChangeRepository
+	Participants() ParticipantRepository
+	Locations() LocationRepository
+	Staff() StaffRepository
+	Issues() IssueRepository
+	Notifications() NotificationRepository
+	IdempotencyKeys() IdempotencyKeyRepository
+	RateLimits() RateLimitRepository
+	AvailabilityEvents() AvailabilityEventRepository
+	Devices() DeviceRepository
+	Waitlist() WaitlistRepository
+	Series() SeriesRepository
+
+	// Transaction menjalankan fn secara atomik. Store yang diberikan ke fn
+	// harus dipakai untuk semua operasi di dalam transaksi.
+	Transaction(ctx context.Context, fn func(tx Store) error) error
+}
+
+type UserRepository interface {
+	FindByID(ctx context.Context, id string) (*models.User, error)
+	// Lock mengunci peminjam id sampai transaksi selesai sehingga pemeriksaan
+	// kuota dan pemesanan oleh peminjam yang sama berjalan berurutan. Hanya
+	// bermakna di dalam Transaction dan harus diambil sebelum TVs().Lock.
+	Lock(ctx context.Context, id string) error
+}
+
+// TVFilter membatasi hasil TVRepository.List. Nilai nol berarti tanpa filter.
+type TVFilter struct {
+	LocationID *int
+}
+
+type TVRepository interface {
+	// List dan FindByID mengembalikan TV lengkap dengan Games dan Location.
+	List(ctx context.Context, filter TVFilter) ([]models.TVInfo, error)
+	FindByID(ctx context.Context, id int) (*models.TVInfo, error)
+	UpdateService(ctx context.Context, id int, outOfService bool, reason string) error
+	// Lock mengunci TV ids sampai transaksi selesai sehingga pemeriksaan dan
+	// pemesanan slot pada TV yang sama berjalan berurutan. Hanya bermakna di
+	// dalam Transaction; TV dikunci berurutan menurut ID agar tidak deadlock.
+	Lock(ctx context.Context, ids ...int) error
+}
+
+type GameRepository interface {
+	List(ctx context.Context) ([]models.Game, error)
+	ListByTV(ctx context.Context, tvID int) ([]models.Game, error)
+}
+
+// Create dan Update pada ReservationRepository mengembalikan ErrDuplicate jika
+// slot dan TV-nya sudah dipegang reservasi aktif lain yang belum check-out.
+type ReservationRepository interface {
+	Create(ctx context.Context, r *models.Reservation) error
+	FindByID(ctx context.Context, id uint) (*models.Reservation, error)
+	Update(ctx context.Context, r *models.Reservation) error
+	// FindActiveBySlot mencari reservasi yang masih memegang slot tertentu, yaitu
+	// yang tidak dibatalkan dan tidak dilepas sebagai no_show.
+	FindActiveBySlot(ctx context.Context, tvID int, slot string) (*models.Reservation, error)
+	// ListActiveByTV mengembalikan reservasi yang masih memegang slotnya dengan from <= slot < to.
+	ListActiveByTV(ctx context.Context, tvID int, from, to string) ([]models.Reservation, error)
+	// ListBookedFrom mengembalikan reservasi berstatus booked dengan slot >= from.
+	ListBookedFrom(ctx context.Context, tvID int, from string) ([]models.Reservation, error)
+	// ListActiveByBorrower mengembalikan reservasi peminjam yang masih memegang slotnya dengan from <= slot < to.
+	ListActiveByBorrower(ctx context.Context, borrowerID string, from, to string) ([]models.Reservation, error)
+	// ListBySeries mengembalikan reservasi series yang masih memegang slotnya dengan slot >= from.
+	ListBySeries(ctx context.Context, seriesID uint, from string) ([]models.Reservation, error)
+	// ListByUser mengembalikan satu halaman riwayat (terbaru dahulu) dan total
+	// datanya: reservasi yang dipinjam userID atau yang undangannya diterima userID.
+	ListByUser(ctx context.Context, userID string, limit, offset int) ([]models.Reservation, int64, error)
+	UpdateStatus(ctx context.Context, id uint, status string) error
+	// TransitionStatus mengubah status reservasi dari from menjadi to untuk semua
+	// slot <= upTo dan mengembalikan reservasi yang diubah.
+	TransitionStatus(ctx context.Context, from, to, upTo string) ([]models.Reservation, error)
+}
+
+type BookingRepository interface {
+	Create(ctx context.Context, b *models.Booking) error
+}
+
+type ReservationChangeRepository interface {
+	Create(ctx context.Context, c *models.ReservationChange) error
+	// ListByReservation mengembalikan riwayat perpindahan reservasi, terlama dahulu.
+	ListByReservation(ctx context.Context, reservationID uint) ([]models.ReservationChange, error)
+}
+
+type ParticipantRepository interface {
+	Create(ctx context.Context, p *models.ReservationParticipant) error
+	FindByID(ctx context.Context, id uint) (*models.ReservationParticipant, error)
+	// ListByReservation mengembalikan semua undangan sebuah reservasi, terlama dahulu.
+	ListByReservation(ctx context.Context, reservationID uint) ([]models.ReservationParticipant, error)
+	// ListInvitations mengembalikan undangan userID yang belum dijawab.
+	ListInvitations(ctx context.Context, userID string) ([]models.ReservationParticipant, error)
+	Update(ctx context.Context, p *models.ReservationParticipant) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type LocationRepository interface {
+	List(ctx context.Context) ([]models.Location, error)
+	FindByID(ctx context.Context, id int) (*models.Location, error)
+	Create(ctx context.Context, l *models.Location) error
+	Update(ctx context.Context, l *models.Location) error
+}
+
+type StaffRepository interface {
+	IsAssigned(ctx context.Context, userID string, locationID int) (bool, error)
+	LocationIDs(ctx context.Context, userID string) ([]int, error)
+	Assign(ctx context.Context, userID string, locationID int) error
+	Unassign(ctx context.Context, userID string, locationID int) error
+}
+
+// IssueFilter membatasi hasil IssueRepository.List. LocationIDs bernilai nil
+// berarti semua lokasi, sedangkan slice kosong berarti tidak ada lokasi sama sekali.
+type IssueFilter struct {
+	Status      string
+	TVID        *int
+	LocationIDs []int
+}
+
+type IssueRepository interface {
+	Create(ctx context.Context, issue *models.TVIssue) error
+	FindByID(ctx context.Context, id uint) (*models.TVIssue, error)
+	List(ctx context.Context, filter IssueFilter) ([]models.TVIssue, error)
+	Update(ctx context.Context, issue *models.TVIssue) error
+}
+
+type NotificationRepository interface {
+	Create(ctx context.Context, n *models.Notification) error
+	ListByUser(ctx context.Context, userID string, limit int) ([]models.Notification, error)
+}
+
+type IdempotencyKeyRepository interface {
+	// Reserve menyimpan k sebagai request yang sedang diproses. Mengembalikan
+	// false jika kunci yang sama sudah ada dan dibuat setelah staleBefore;
+	// kunci yang lebih tua ditimpa.
+	Reserve(ctx context.Context, k *models.IdempotencyKey, staleBefore time.Time) (bool, error)
+	Find(ctx context.Context, userID, key string) (*models.IdempotencyKey, error)
+	// Complete menyimpan respons akhir untuk kunci yang sudah di-Reserve.
+	Complete(ctx context.Context, userID, key string, statusCode int, body []byte) error
+	Delete(ctx context.Context, userID, key string) error
+	// DeleteBefore menghapus semua kunci yang dibuat sebelum t.
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+}
+
+type RateLimitRepository interface {
+	// Take mengisi ulang bucket key dengan rate token per detik hingga capacity,
+	// lalu mengambil satu token jika tersedia. Bucket baru dimulai penuh.
+	// Mengembalikan sisa token dan apakah token berhasil diambil.
+	Take(ctx context.Context, key string, capacity, rate float64, now time.Time) (remaining float64, allowed bool, err error)
+	// DeleteIdle menghapus bucket yang tidak dipakai sejak before.
+	DeleteIdle(ctx context.Context, before time.Time) (int64, error)
+}
+
+type AvailabilityEventRepository interface {
+	// Append mencatat e dan mengisi ID-nya. Di dalam transaksi, listener baru
+	// menerima e setelah transaksi berhasil di-commit.
+	Append(ctx context.Context, e *models.AvailabilityEvent) error
+	// Since mengembalikan paling banyak limit event dengan ID > afterID (urut
+	// naik), opsional difilter per lokasi. complete bernilai false jika event
+	// afterID sudah dihapus sehingga sebagian event setelahnya mungkin hilang.
+	Since(ctx context.Context, afterID int64, locationID *int, limit int) (events []models.AvailabilityEvent, complete bool, err error)
+	// DeleteBefore menghapus event yang dibuat sebelum t.
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+	// Listen memanggil fn untuk setiap event yang di-Append dari instance mana
+	// pun sampai ctx selesai atau koneksi terputus.
+	Listen(ctx context.Context, fn func(models.AvailabilityEvent)) error
+}
+
+type DeviceRepository interface {
+	Create(ctx context.Context, d *models.Device) error
+	FindByID(ctx context.Context, id uint) (*models.Device, error)
+	// FindByTokenHash mencari perangkat yang belum dicabut berdasarkan hash tokennya.
+	FindByTokenHash(ctx context.Context, hash string) (*models.Device, error)
+	List(ctx context.Context) ([]models.Device, error)
+	Revoke(ctx context.Context, id uint, at time.Time) error
+	Touch(ctx context.Context, id uint, at time.Time) error
+}
+
+type WaitlistRepository interface {
+	Create(ctx context.Context, e *models.WaitlistEntry) error
+	FindByID(ctx context.Context, id uint) (*models.WaitlistEntry, error)
+	Update(ctx context.Context, e *models.WaitlistEntry) error
+	// ListOpenByUser mengembalikan entri waiting dan offered milik pengguna, urut slot.
+	ListOpenByUser(ctx context.Context, userID string) ([]models.WaitlistEntry, error)
+	// NextWaiting mengembalikan entri waiting tertua untuk slot pada TV tvID,
+	// termasuk entri untuk TV mana pun dengan consoleType yang sama di locationID.
+	NextWaiting(ctx context.Context, tvID, locationID int, consoleType, slot string) (*models.WaitlistEntry, error)
+	// ListOffers mengembalikan entri offered yang menahan TV tvID dengan from <= slot < to.
+	ListOffers(ctx context.Context, tvID int, from, to string) ([]models.WaitlistEntry, error)
+	// ListExpiredOffers mengembalikan entri offered yang batas waktunya <= now.
+	ListExpiredOffers(ctx context.Context, now time.Time) ([]models.WaitlistEntry, error)
+	// ExpireWaiting menandai entri waiting dengan slot <= upTo sebagai expired.
+	ExpireWaiting(ctx context.Context, upTo string) (int64, error)
+}
+
+// SeriesFilter membatasi hasil SeriesRepository.List. LocationIDs bernilai nil
+// berarti semua lokasi; OrganizerID dan Status kosong berarti tanpa filter.
+type SeriesFilter struct {
+	LocationIDs []int
+	OrganizerID string
+	Status      string
+}
+
+type SeriesRepository interface {
+	Create(ctx context.Context, series *models.ReservationSeries) error
+	FindByID(ctx context.Context, id uint) (*models.ReservationSeries, error)
+	List(ctx context.Context, filter SeriesFilter) ([]models.ReservationSeries, error)
+	Update(ctx context.Context, series *models.ReservationSeries) error
+}
@@ -2,34 +2,132 @@
 package routes
 
 import (
+	"playcorner-be/internal/auth"
 	"playcorner-be/internal/handlers"
 	"playcorner-be/internal/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// RateLimits berisi middleware pembatas laju per kelompok rute. Field yang
+// bernilai nil berarti kelompok tersebut tidak dibatasi.
+type RateLimits struct {
+	Default fiber.Handler
+	Auth    fiber.Handler
+	Booking fiber.Handler
+}
+
 // SetupRoutes menginisialisasi semua rute untuk aplikasi PlayCorner
-func SetupRoutes(app *fiber.App) {
+func SetupRoutes(app *fiber.App, h *handlers.Handler, tokens *auth.Manager, roles middleware.RoleResolver, limits RateLimits) {
+	limits.fill()
 	api := app.Group("/api")
 
 	// --- Rute Publik ---
-	auth := api.Group("/auth")
-	auth.Post("/login", handlers.Login)
-	auth.Post("/refresh", handlers.RefreshToken)
+	// Dibatasi per IP klien; login dan refresh memakai batas yang lebih ketat
+	authGroup := api.Group("/auth")
+	authGroup.Post("/login", limits.Auth, h.Login)
+	authGroup.Post("/refresh", limits.Auth, h.RefreshToken)
+
+	api.Get("/tvs", limits.Default, h.GetAllTVs)
+	api.Get("/tvs/:tvId/reservations", limits.Default, h.GetTVReservations)
+	api.Get("/locations", limits.Default, h.GetLocations)
+	api.Get("/availability", limits.Default, h.GetAvailability)
+	api.Get("/availability/stream", limits.Default, h.StreamAvailability)
 
-	api.Get("/tvs", handlers.GetAllTVs)
-	api.Get("/tvs/:tvId/reservations", handlers.GetTVReservations)
+	// --- Rute Kiosk ---
+	// Layar kiosk diautentikasi dengan token perangkat, bukan JWT pengguna
+	api.Get("/kiosk/ws", limits.Default, middleware.DeviceAuth(h.Devices), h.KioskSocket)
 
 	// --- Rute Terproteksi ---
 	// Rute di bawah ini memerlukan token JWT yang valid di header 'Authorization'
+	// dan dibatasi per pengguna, bukan per IP, agar pengguna di balik NAT kampus
+	// yang sama tidak saling menghabiskan kuota
 	protected := api.Group("/")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(tokens), limits.Default)
+	// withRole dipakai rute yang terbuka untuk semua pengguna, tetapi staff boleh
+	// bertindak atas data pengguna lain di lokasi yang ditugaskan kepadanya
+	withRole := middleware.WithRole(roles)
+
+	protected.Get("/users/:userId", h.GetUser)
+	protected.Get("/users/:userId/histories", h.GetUserHistories)
+	// Idempotency-Key mencegah reservasi ganda saat klien mengulang request
+	protected.Post("/tvs/:tvId/reservations", limits.Booking, middleware.Idempotency(h.IdempotencyKeys), h.CreateReservation)
+	// Beberapa slot dan/atau TV dipesan sekaligus dalam satu transaksi
+	protected.Post("/bookings", limits.Booking, middleware.Idempotency(h.IdempotencyKeys), h.CreateBooking)
+	protected.Post("/reservations/:reservationId/cancel", withRole, h.CancelReservation)
+	// Reservasi dipindahkan ke slot dan/atau TV lain tanpa melepas slot lama lebih dulu
+	protected.Patch("/reservations/:reservationId", limits.Booking, withRole, h.RescheduleReservation)
+	protected.Get("/reservations/:reservationId/changes", withRole, h.GetReservationChanges)
+	// Sesi yang sedang berjalan diperpanjang ke slot berikutnya jika masih kosong
+	protected.Post("/reservations/:reservationId/extend", limits.Booking, withRole, h.ExtendReservation)
+	// Sesi diakhiri lebih awal; sisa slot terbuka untuk walk-in
+	protected.Post("/reservations/:reservationId/check-out", withRole, h.CheckOutReservation)
+	// Pemain lain diundang peminjam dan menerima atau menolak undangannya
+	protected.Post("/reservations/:reservationId/participants", h.InviteParticipant)
+	protected.Get("/reservations/:reservationId/participants", withRole, h.GetParticipants)
+	protected.Delete("/reservations/:reservationId/participants/:userId", h.RemoveParticipant)
+	protected.Get("/invitations", h.GetInvitations)
+	protected.Post("/invitations/:invitationId/accept", h.AcceptInvitation)
+	protected.Post("/invitations/:invitationId/decline", h.DeclineInvitation)
+	// Waitlist untuk slot yang penuh; slot yang dilepas ditawarkan berurutan
+	protected.Post("/tvs/:tvId/waitlist", h.JoinWaitlist)
+	protected.Get("/waitlist", h.GetWaitlist)
+	protected.Post("/waitlist/:entryId/accept", limits.Booking, h.AcceptWaitlistOffer)
+	protected.Delete("/waitlist/:entryId", h.LeaveWaitlist)
+	// Booking berulang diajukan penyelenggara dan baru memblokir slot setelah disetujui staff
+	protected.Post("/series", h.CreateSeries)
+	protected.Get("/series", withRole, h.GetSeriesList)
+	protected.Get("/series/:seriesId", withRole, h.GetSeries)
+	protected.Patch("/series/:seriesId", withRole, h.UpdateSeries)
+	protected.Patch("/series/:seriesId/occurrences/:date", withRole, h.UpdateSeriesOccurrence)
+	protected.Delete("/series/:seriesId", withRole, h.CancelSeries)
+	protected.Post("/tvs/:tvId/issues", h.ReportIssue)
+	protected.Get("/notifications", h.GetNotifications)
+
+	// --- Rute Staff ---
+	// Pengelolaan tiket kerusakan dan status layanan TV, dibatasi per lokasi yang ditugaskan
+	staffOnly := middleware.StaffOnly(roles)
+	protected.Get("/issues", staffOnly, h.GetIssues)
+	protected.Patch("/issues/:issueId", staffOnly, h.UpdateIssueStatus)
+	protected.Patch("/tvs/:tvId/service", staffOnly, h.SetTVService)
+	protected.Post("/reservations/:reservationId/check-in", staffOnly, h.CheckInReservation)
+	// Mahasiswa yang datang langsung saat TV kosong dicatat sebagai sesi walk-in
+	protected.Post("/tvs/:tvId/walk-ins", staffOnly, h.StartWalkIn)
+	protected.Post("/series/:seriesId/approve", staffOnly, h.ApproveSeries)
+	protected.Post("/series/:seriesId/reject", staffOnly, h.RejectSeries)
+	// Batas jam berturut-turut saat memperpanjang sesi diatur per lokasi
+	protected.Put("/locations/:locationId/booking-policy", staffOnly, h.UpdateBookingPolicy)
 
-	protected.Get("/users/:userId", handlers.GetUser)
-	protected.Get("/users/:userId/histories", handlers.GetUserHistories)
-	protected.Post("/tvs/:tvId/reservations", handlers.CreateReservation)
+	// --- Rute Admin ---
+	// Pengelolaan lokasi dan penugasan staff per lokasi
+	adminOnly := middleware.AdminOnly(roles)
+	protected.Post("/locations", adminOnly, h.CreateLocation)
+	protected.Patch("/locations/:locationId", adminOnly, h.UpdateLocation)
+	protected.Put("/locations/:locationId/staff/:userId", adminOnly, h.AssignStaff)
+	protected.Delete("/locations/:locationId/staff/:userId", adminOnly, h.UnassignStaff)
+	protected.Get("/devices", adminOnly, h.GetDevices)
+	protected.Post("/devices", adminOnly, h.RegisterDevice)
+	protected.Delete("/devices/:deviceId", adminOnly, h.RevokeDevice)
+
+	app.Static("/uploads", h.UploadDir)
+
+	// --- Probe ---
+	// /healthz hanya memastikan proses hidup, /readyz memeriksa database,
+	// versi migrasi, dan scheduler, serta gagal selama graceful shutdown
+	app.Get("/healthz", h.Healthz)
+	app.Get("/readyz", h.Readyz)
 
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{"status": "ok", "message": "Welcome to PlayCorner API!"})
 	})
 }
+
+// fill mengganti middleware yang kosong dengan middleware yang langsung meneruskan request.
+func (l *RateLimits) fill() {
+	next := func(c *fiber.Ctx) error { return c.Next() }
+	for _, handler := range []*fiber.Handler{&l.Default, &l.Auth, &l.Booking} {
+		if *handler == nil {
+			*handler = next
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Package scheduler
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job adalah pekerjaan latar belakang yang dijalankan berkala.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler menjalankan sekumpulan Job di goroutine masing-masing sampai Stop dipanggil.
+type Scheduler struct {
+	jobs []Job
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running bool
+}
+
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Add mendaftarkan job. Harus dipanggil sebelum Start.
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start menjalankan semua job. Setiap job langsung dijalankan sekali, lalu
+// diulang setiap Interval.
+func (s *Scheduler) Start(parent context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	s.cancel = cancel
+	s.running = true
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "background job failed", "job", job.Name, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Running bernilai true selama scheduler sudah dimulai dan belum dihentikan.
+func (s *Scheduler) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// Stop membatalkan context semua job lalu menunggu job yang sedang berjalan
+// selesai, paling lama sampai ctx berakhir.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.cancel()
+	s.running = false
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
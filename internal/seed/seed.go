@@ -0,0 +1,240 @@
+// Package seed
+package seed
+
+import (
+	"embed"
+	"fmt"
+	"log/slog"
+	"os"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/utils"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// fixtureFiles berisi fixture bawaan untuk setiap profil (dev, demo, test).
+//
+//go:embed fixtures/*.yaml
+var fixtureFiles embed.FS
+
+// Profiles adalah daftar profil fixture bawaan.
+var Profiles = []string{"dev", "demo", "test"}
+
+// Fixture adalah isi satu file fixture. File JSON juga dapat dibaca karena
+// JSON merupakan subset dari YAML.
+type Fixture struct {
+	Locations []LocationFixture `yaml:"locations"`
+	Games     []GameFixture     `yaml:"games"`
+	Users     []UserFixture     `yaml:"users"`
+	TVs       []TVFixture       `yaml:"tvs"`
+}
+
+// LocationFixture diidentifikasi berdasarkan nama lokasi.
+type LocationFixture struct {
+	Name      string `yaml:"name"`
+	Building  string `yaml:"building"`
+	Timezone  string `yaml:"timezone"`
+	OpenHour  int    `yaml:"openHour"`
+	CloseHour int    `yaml:"closeHour"`
+}
+
+// GameFixture diidentifikasi berdasarkan judul game.
+type GameFixture struct {
+	Title        string `yaml:"title"`
+	CoverPictURL string `yaml:"coverPictUrl"`
+}
+
+// UserFixture diidentifikasi berdasarkan NIM. Locations berisi nama lokasi
+// yang ditugaskan kepada pengguna berperan staff.
+type UserFixture struct {
+	ID             string   `yaml:"id"`
+	Name           string   `yaml:"name"`
+	Faculty        string   `yaml:"faculty"`
+	Major          string   `yaml:"major"`
+	CreditScore    int      `yaml:"creditScore"`
+	ProfilePictURL string   `yaml:"profilePictUrl"`
+	Role           string   `yaml:"role"`
+	Password       string   `yaml:"password"`
+	Locations      []string `yaml:"locations"`
+}
+
+// TVFixture diidentifikasi berdasarkan nomor TV. Location dan Games merujuk
+// ke nama lokasi dan judul game di fixture yang sama atau yang sudah ada di database.
+type TVFixture struct {
+	ID          int      `yaml:"id"`
+	ConsoleType string   `yaml:"consoleType"`
+	Location    string   `yaml:"location"`
+	Games       []string `yaml:"games"`
+}
+
+// LoadProfile membaca fixture bawaan untuk profil tertentu.
+func LoadProfile(profile string) (*Fixture, error) {
+	data, err := fixtureFiles.ReadFile("fixtures/" + profile + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("unknown seed profile %q (available: %v)", profile, Profiles)
+	}
+	return parse(data)
+}
+
+// LoadFile membaca fixture dari file YAML atau JSON di luar binary.
+func LoadFile(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parse(data)
+}
+
+func parse(data []byte) (*Fixture, error) {
+	var f Fixture
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid fixture: %w", err)
+	}
+	return &f, nil
+}
+
+// Apply memasukkan fixture ke database dalam satu transaksi. Setiap entitas
+// di-upsert berdasarkan kunci alaminya sehingga aman dijalankan berulang kali.
+func Apply(db *gorm.DB, f *Fixture) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		locationIDs := map[string]int{}
+		for _, l := range f.Locations {
+			location := models.Location{
+				Name:      l.Name,
+				Building:  l.Building,
+				Timezone:  l.Timezone,
+				OpenHour:  l.OpenHour,
+				CloseHour: l.CloseHour,
+			}
+			if err := tx.Where(models.Location{Name: l.Name}).
+				Assign(map[string]any{"building": l.Building, "timezone": l.Timezone, "open_hour": l.OpenHour, "close_hour": l.CloseHour}).
+				FirstOrCreate(&location).Error; err != nil {
+				return fmt.Errorf("location %q: %w", l.Name, err)
+			}
+			locationIDs[l.Name] = location.ID
+		}
+
+		games := map[string]*models.Game{}
+		for _, g := range f.Games {
+			game := models.Game{Title: g.Title, CoverPictURL: g.CoverPictURL}
+			if err := tx.Where(models.Game{Title: g.Title}).
+				Assign(map[string]any{"cover_pict_url": g.CoverPictURL}).
+				FirstOrCreate(&game).Error; err != nil {
+				return fmt.Errorf("game %q: %w", g.Title, err)
+			}
+			games[g.Title] = &game
+		}
+
+		for _, u := range f.Users {
+			if err := upsertUser(tx, u, locationIDs); err != nil {
+				return fmt.Errorf("user %s: %w", u.ID, err)
+			}
+		}
+
+		for _, t := range f.TVs {
+			if err := upsertTV(tx, t, locationIDs, games); err != nil {
+				return fmt.Errorf("tv %d: %w", t.ID, err)
+			}
+		}
+
+		// TV dibuat dengan ID eksplisit, jadi sequence perlu dimajukan agar
+		// insert berikutnya tidak bentrok.
+		if len(f.TVs) > 0 {
+			if err := tx.Exec("SELECT setval(pg_get_serial_sequence('tv_infos', 'id'), (SELECT MAX(id) FROM tv_infos))").Error; err != nil {
+				return err
+			}
+		}
+
+		slog.Info("Seeded fixtures", "locations", len(f.Locations), "games", len(f.Games), "users", len(f.Users), "tvs", len(f.TVs))
+		return nil
+	})
+}
+
+func upsertUser(tx *gorm.DB, u UserFixture, locationIDs map[string]int) error {
+	role := u.Role
+	if role == "" {
+		role = models.RoleStudent
+	}
+
+	var user models.User
+	err := tx.Where("id = ?", u.ID).Limit(1).Find(&user).Error
+	if err != nil {
+		return err
+	}
+	exists := user.ID != ""
+
+	// Hash hanya dibuat ulang jika password di fixture berubah, karena bcrypt lambat
+	passwordHash := user.PasswordHash
+	if !exists || !utils.CheckPasswordHash(u.Password, user.PasswordHash) {
+		passwordHash, err = utils.HashPassword(u.Password)
+		if err != nil {
+			return err
+		}
+	}
+
+	user = models.User{
+		ID:             u.ID,
+		Name:           u.Name,
+		Faculty:        u.Faculty,
+		Major:          u.Major,
+		CreditScore:    u.CreditScore,
+		ProfilePictURL: u.ProfilePictURL,
+		Role:           role,
+		PasswordHash:   passwordHash,
+	}
+	if err := tx.Save(&user).Error; err != nil {
+		return err
+	}
+
+	for _, name := range u.Locations {
+		locationID, err := resolveLocation(tx, name, locationIDs)
+		if err != nil {
+			return err
+		}
+		assignment := models.StaffAssignment{UserID: u.ID, LocationID: locationID}
+		if err := tx.Where(&assignment).FirstOrCreate(&assignment).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func upsertTV(tx *gorm.DB, t TVFixture, locationIDs map[string]int, games map[string]*models.Game) error {
+	locationID, err := resolveLocation(tx, t.Location, locationIDs)
+	if err != nil {
+		return err
+	}
+
+	tv := models.TVInfo{ID: t.ID, ConsoleType: t.ConsoleType, LocationID: locationID}
+	if err := tx.Omit("Games", "Location", "OutOfService", "OutOfServiceReason").Save(&tv).Error; err != nil {
+		return err
+	}
+
+	tvGames := []*models.Game{}
+	for _, title := range t.Games {
+		game, ok := games[title]
+		if !ok {
+			game = &models.Game{}
+			if err := tx.Where("title = ?", title).First(game).Error; err != nil {
+				return fmt.Errorf("unknown game %q", title)
+			}
+			games[title] = game
+		}
+		tvGames = append(tvGames, game)
+	}
+	return tx.Model(&tv).Association("Games").Replace(tvGames)
+}
+
+func resolveLocation(tx *gorm.DB, name string, locationIDs map[string]int) (int, error) {
+	if id, ok := locationIDs[name]; ok {
+		return id, nil
+	}
+
+	var location models.Location
+	if err := tx.Where("name = ?", name).First(&location).Error; err != nil {
+		return 0, fmt.Errorf("unknown location %q", name)
+	}
+	locationIDs[name] = location.ID
+	return location.ID, nil
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+)
+
+// Actor adalah pengguna yang sedang login beserta perannya.
+type Actor struct {
+	UserID string
+	Role   string
+}
+
+// canManage memeriksa apakah actor boleh mengelola lokasi tertentu.
+// Admin dapat mengelola semua lokasi; staff hanya lokasi yang ditugaskan kepadanya.
+func canManage(ctx context.Context, staff repository.StaffRepository, actor Actor, locationID int) (bool, error) {
+	switch actor.Role {
+	case models.RoleAdmin:
+		return true, nil
+	case models.RoleStaff:
+		return staff.IsAssigned(ctx, actor.UserID, locationID)
+	default:
+		return false, nil
+	}
+}
+
+// managedLocationIDs mengembalikan lokasi yang dikelola actor. Nilai nil berarti tanpa batasan (admin).
+func managedLocationIDs(ctx context.Context, staff repository.StaffRepository, actor Actor) ([]int, error) {
+	switch actor.Role {
+	case models.RoleAdmin:
+		return nil, nil
+	case models.RoleStaff:
+		return staff.LocationIDs(ctx, actor.UserID)
+	default:
+		return []int{}, nil
+	}
+}
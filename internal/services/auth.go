@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"playcorner-be/internal/auth"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"playcorner-be/internal/utils"
+	"time"
+)
+
+// AuthService menangani login dan pembaruan token.
+type AuthService struct {
+	users  repository.UserRepository
+	tokens *auth.Manager
+}
+
+func NewAuthService(users repository.UserRepository, tokens *auth.Manager) *AuthService {
+	return &AuthService{users: users, tokens: tokens}
+}
+
+// Tokens adalah hasil login atau refresh.
+type Tokens struct {
+	UserID       string
+	AccessToken  string
+	RefreshToken string
+	// AccessExpiresAt dan RefreshExpiresAt mengikuti masa berlaku dari konfigurasi JWT.
+	AccessExpiresAt  time.Time
+	RefreshExpiresAt time.Time
+}
+
+// Login memverifikasi NIM dan password lalu membuat access dan refresh token.
+func (s *AuthService) Login(ctx context.Context, identifier, password string) (*Tokens, error) {
+	user, err := s.users.FindByID(ctx, identifier)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if !utils.CheckPasswordHash(password, user.PasswordHash) {
+		return nil, ErrInvalidCredentials
+	}
+
+	accessToken, refreshToken, err := s.tokens.GenerateTokens(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &Tokens{
+		UserID:           user.ID,
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		AccessExpiresAt:  now.Add(s.tokens.AccessTTL()),
+		RefreshExpiresAt: now.Add(s.tokens.RefreshTTL()),
+	}, nil
+}
+
+// Refresh membuat access token baru dari refresh token yang masih berlaku.
+func (s *AuthService) Refresh(refreshToken string) (*Tokens, error) {
+	claims, err := s.tokens.ValidateToken(refreshToken)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	accessToken, _, err := s.tokens.GenerateTokens(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &Tokens{
+		UserID:          claims.UserID,
+		AccessToken:     accessToken,
+		AccessExpiresAt: time.Now().Add(s.tokens.AccessTTL()),
+	}, nil
+}
+
+// Role mengembalikan peran pengguna, dipakai oleh middleware otorisasi.
+func (s *AuthService) Role(ctx context.Context, userID string) (string, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", ErrUserNotFound
+		}
+		return "", err
+	}
+	if user.Role == "" {
+		return models.RoleStudent, nil
+	}
+	return user.Role, nil
+}
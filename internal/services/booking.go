@@ -0,0 +1,584 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/config"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"time"
+)
+
+// BookingService memuat aturan ketersediaan slot dan pembuatan reservasi.
+type BookingService struct {
+	store  repository.Store
+	policy config.BookingConfig
+
+	// Now dapat diganti saat pengujian agar "hari ini" deterministik.
+	Now func() time.Time
+}
+
+func NewBookingService(store repository.Store, policy config.BookingConfig) *BookingService {
+	return &BookingService{store: store, policy: policy, Now: time.Now}
+}
+
+// ListTVs mengembalikan semua TV beserta game dan lokasinya, opsional difilter per lokasi.
+func (s *BookingService) ListTVs(ctx context.Context, locationID *int) ([]models.TVInfo, error) {
+	return s.store.TVs().List(ctx, repository.TVFilter{LocationID: locationID})
+}
+
+// TVStatus mengembalikan ketersediaan slot hari ini untuk satu TV.
+func (s *BookingService) TVStatus(ctx context.Context, tvID int) (*models.TV, error) {
+	tv, err := s.store.TVs().FindByID(ctx, tvID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTVNotFound
+		}
+		return nil, err
+	}
+
+	status, err := s.statusToday(ctx, *tv)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Availability mengembalikan ketersediaan slot hari ini untuk semua TV, opsional difilter per lokasi.
+func (s *BookingService) Availability(ctx context.Context, locationID *int) ([]models.TV, error) {
+	tvs, err := s.store.TVs().List(ctx, repository.TVFilter{LocationID: locationID})
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []models.TV{}
+	for _, tv := range tvs {
+		status, err := s.statusToday(ctx, tv)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// statusToday menyusun status ketersediaan seluruh slot hari ini untuk sebuah TV.
+func (s *BookingService) statusToday(ctx context.Context, tv models.TVInfo) (models.TV, error) {
+	slots := daySlots(locationOf(tv), s.Now())
+
+	reservedSlots := make(map[string]bool)
+	// checkedOut menyimpan check-out terakhir per slot; occupied menandai slot
+	// yang masih dipegang sesi yang belum check-out (termasuk walk-in)
+	checkedOut := make(map[string]time.Time)
+	occupied := make(map[string]bool)
+	if len(slots) > 0 {
+		reservations, err := s.store.Reservations().ListActiveByTV(ctx, tv.ID,
+			SlotKey(slots[0]), SlotKey(slots[len(slots)-1].Add(time.Hour)))
+		if err != nil {
+			return models.TV{}, err
+		}
+		for _, r := range reservations {
+			reservedSlots[r.TimeSlot] = true
+			if r.CheckedOutAt == nil {
+				occupied[r.TimeSlot] = true
+			} else if r.CheckedOutAt.After(checkedOut[r.TimeSlot]) {
+				checkedOut[r.TimeSlot] = *r.CheckedOutAt
+			}
+		}
+
+		// Slot yang sedang ditawarkan ke antrean waitlist ditahan untuk penerima tawaran
+		offers, err := s.store.Waitlist().ListOffers(ctx, tv.ID,
+			SlotKey(slots[0]), SlotKey(slots[len(slots)-1].Add(time.Hour)))
+		if err != nil {
+			return models.TV{}, err
+		}
+		for _, o := range offers {
+			reservedSlots[o.TimeSlot] = true
+		}
+	}
+
+	now := s.Now()
+	timeSlots := []models.TimeSlot{}
+	for _, start := range slots {
+		slotString := SlotKey(start)
+		slot := models.TimeSlot{
+			StartTime:    slotString,
+			EndTime:      SlotKey(start.Add(time.Hour)),
+			Availability: "available",
+		}
+		if reservedSlots[slotString] || tv.OutOfService {
+			slot.Availability = "unavailable"
+		}
+
+		// Sisa slot yang sesinya sudah check-out terbuka untuk walk-in sampai slot berakhir
+		if at, ok := checkedOut[slotString]; ok && !occupied[slotString] && !tv.OutOfService && now.Before(start.Add(time.Hour)) {
+			from := SlotKey(at)
+			slot.Availability = "walk_in"
+			slot.WalkInFrom = &from
+		}
+
+		timeSlots = append(timeSlots, slot)
+	}
+
+	return models.TV{
+		ID:           tv.ID,
+		ConsoleType:  tv.ConsoleType,
+		LocationID:   tv.LocationID,
+		OutOfService: tv.OutOfService,
+		TimeSlots:    timeSlots,
+	}, nil
+}
+
+// CreateReservation memesan satu slot pada sebuah TV atas nama borrowerID.
+func (s *BookingService) CreateReservation(ctx context.Context, borrowerID string, tvID int, timeslot string) (*models.Reservation, error) {
+	_, reservations, err := s.book(ctx, borrowerID, []models.BookingItem{{TVID: tvID, Timeslot: timeslot}}, false)
+	if err != nil {
+		return nil, err
+	}
+	return &reservations[0], nil
+}
+
+// CreateBooking memesan beberapa pasangan TV dan slot atas nama borrowerID
+// dalam satu transaksi: semuanya dipesan, atau tidak ada sama sekali. Semua
+// reservasinya dikelompokkan dalam satu Booking.
+func (s *BookingService) CreateBooking(ctx context.Context, borrowerID string, items []models.BookingItem) (*models.Booking, []models.Reservation, error) {
+	return s.book(ctx, borrowerID, items, true)
+}
+
+// book memeriksa dan membuat reservasi untuk items. Jika batch, kesalahan
+// field ditandai dengan indeks item agar klien tahu item mana yang salah.
+func (s *BookingService) book(ctx context.Context, borrowerID string, items []models.BookingItem, batch bool) (*models.Booking, []models.Reservation, error) {
+	field := func(i int, name string) string {
+		if batch {
+			return fmt.Sprintf("items[%d].%s", i, name)
+		}
+		return name
+	}
+
+	planned := make([]plannedSlot, 0, len(items))
+	seen := map[tvSlot]bool{}
+	for i, item := range items {
+		tv, err := s.store.TVs().FindByID(ctx, item.TVID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, nil, ErrTVNotFound
+			}
+			return nil, nil, err
+		}
+
+		// TV yang sedang rusak tidak dapat dipesan
+		if tv.OutOfService {
+			return nil, nil, ErrTVOutOfService
+		}
+
+		// Slot harus sesuai jam buka lokasi TV, berada dalam jendela booking,
+		// dan disimpan dalam format kanonik (UTC)
+		slot, err := normalizeSlot(item.Timeslot, locationOf(*tv))
+		if err == nil {
+			err = s.checkWindow(slot)
+		}
+		if err != nil {
+			var appErr *apperr.Error
+			if errors.As(err, &appErr) && len(appErr.Fields) == 1 {
+				return nil, nil, invalid(field(i, appErr.Fields[0].Field), appErr.Msg)
+			}
+			return nil, nil, err
+		}
+
+		key := tvSlot{tv.ID, slot}
+		if seen[key] {
+			return nil, nil, invalid(field(i, "timeslot"), "The same TV and timeslot must not be booked twice")
+		}
+		seen[key] = true
+		planned = append(planned, plannedSlot{tv: *tv, slot: slot})
+	}
+
+	booking := &models.Booking{BorrowerID: borrowerID}
+	reservations := make([]models.Reservation, 0, len(planned))
+	tvIDs := make([]int, 0, len(planned))
+	for _, p := range planned {
+		tvIDs = append(tvIDs, p.tv.ID)
+	}
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := s.checkQuota(ctx, tx, borrowerID, planned, 0); err != nil {
+			return err
+		}
+		// Kunci TV terlebih dahulu agar pemesanan paralel pada TV yang sama
+		// tidak lolos pemeriksaan slot bersamaan
+		if err := tx.TVs().Lock(ctx, tvIDs...); err != nil {
+			return err
+		}
+		// Cek apakah slot sudah dipesan atau sedang ditawarkan ke antrean waitlist
+		for _, p := range planned {
+			free, err := s.slotFree(ctx, tx, p.tv, p.slot)
+			if err != nil {
+				return err
+			}
+			if !free {
+				return ErrSlotTaken
+			}
+		}
+
+		if err := tx.Bookings().Create(ctx, booking); err != nil {
+			return err
+		}
+		for _, p := range planned {
+			reservation := models.Reservation{
+				TVID:       p.tv.ID,
+				BorrowerID: borrowerID,
+				TimeSlot:   p.slot,
+				Status:     models.ReservationBooked,
+				BookingID:  &booking.ID,
+			}
+			if err := tx.Reservations().Create(ctx, &reservation); err != nil {
+				return slotConflict(err)
+			}
+			if err := recordAvailability(ctx, tx, models.AvailabilityBooked, p.tv, p.slot); err != nil {
+				return err
+			}
+			reservations = append(reservations, reservation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return booking, reservations, nil
+}
+
+// checkWindow memastikan slot belum berakhir dan tidak dimulai lebih dari
+// MaxAdvance dari sekarang.
+func (s *BookingService) checkWindow(slot string) error {
+	start, err := time.Parse(time.RFC3339, slot)
+	if err != nil {
+		return err
+	}
+	now := s.Now()
+	if !now.Before(start.Add(time.Hour)) {
+		return invalid("timeslot", "Timeslot has already ended")
+	}
+	if start.After(now.Add(s.policy.MaxAdvance)) {
+		return invalid("timeslot", "Timeslot is too far in advance")
+	}
+	return nil
+}
+
+// checkQuota memastikan borrowerID tidak memegang lebih dari MaxHoursPerDay
+// slot per hari (zona waktu lokasi TV) setelah slots ditambahkan. Reservasi
+// series dan reservasi except (yang sedang dipindahkan) tidak dihitung.
+//
+// checkQuota dipanggil di dalam transaksi tx sebelum TV dikunci. Peminjam
+// dikunci lebih dulu agar dua pemesanan paralel oleh peminjam yang sama tidak
+// sama-sama lolos pemeriksaan kuota.
+func (s *BookingService) checkQuota(ctx context.Context, tx repository.Store, borrowerID string, slots []plannedSlot, except uint) error {
+	if err := tx.Users().Lock(ctx, borrowerID); err != nil {
+		return err
+	}
+
+	// Hari dikunci dengan awal harinya (tengah malam zona waktu lokasi) dalam UTC
+	wanted := map[string]int{}
+	days := map[string]time.Time{}
+	var order []string
+	for _, p := range slots {
+		start, err := time.Parse(time.RFC3339, p.slot)
+		if err != nil {
+			return err
+		}
+		local := start.In(timezoneOf(locationOf(p.tv)))
+		from := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+		key := SlotKey(from)
+		if _, ok := days[key]; !ok {
+			days[key] = from
+			order = append(order, key)
+		}
+		wanted[key]++
+	}
+
+	for _, key := range order {
+		from := days[key]
+		held, err := tx.Reservations().ListActiveByBorrower(ctx, borrowerID,
+			SlotKey(from), SlotKey(from.AddDate(0, 0, 1)))
+		if err != nil {
+			return err
+		}
+		count := wanted[key]
+		for _, r := range held {
+			if r.SeriesID == nil && r.ID != except {
+				count++
+			}
+		}
+		if count > s.policy.MaxHoursPerDay {
+			return ErrBookingQuota
+		}
+	}
+	return nil
+}
+
+// CheckIn mencatat kehadiran peminjam beserta pemain undangan yang hadir
+// (attendees; nil berarti semua undangan yang sudah diterima). Hanya staff
+// lokasi TV (atau admin) yang dapat melakukannya, mulai CheckInOpensBefore
+// sebelum slot sampai NoShowGrace setelah slot dimulai.
+func (s *BookingService) CheckIn(ctx context.Context, actor Actor, reservationID uint, attendees *[]string) (*models.Reservation, []models.ReservationParticipant, error) {
+	res, err := s.store.Reservations().FindByID(ctx, reservationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrReservationNotFound
+		}
+		return nil, nil, err
+	}
+
+	tv, err := s.store.TVs().FindByID(ctx, res.TVID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrTVNotFound
+		}
+		return nil, nil, err
+	}
+	ok, err := canManage(ctx, s.store.Staff(), actor, tv.LocationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, ErrLocationForbidden
+	}
+
+	now := s.Now()
+	var participants []models.ReservationParticipant
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		// Status dibaca ulang di bawah kunci TV agar tidak bersamaan dengan
+		// pembatalan, pemindahan, atau check-in lain
+		res, tv, err = lockReservation(ctx, tx, res.ID, tv.ID)
+		if err != nil {
+			return err
+		}
+		if res.Status != models.ReservationBooked {
+			return ErrReservationNotBooked
+		}
+		start, err := time.Parse(time.RFC3339, res.TimeSlot)
+		if err != nil {
+			return err
+		}
+		if now.Before(start.Add(-s.policy.CheckInOpensBefore)) || now.After(start.Add(s.policy.NoShowGrace)) {
+			return ErrCheckInClosed
+		}
+
+		res.Status = models.ReservationCheckedIn
+		res.CheckedInAt = &now
+		if err := tx.Reservations().Update(ctx, res); err != nil {
+			return err
+		}
+		participants, err = recordAttendance(ctx, tx, *res, attendees, now)
+		if err != nil {
+			return err
+		}
+		return recordAvailability(ctx, tx, models.AvailabilityCheckedIn, *tv, res.TimeSlot)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return res, participants, nil
+}
+
+// CancelReservation membatalkan reservasi booked. Peminjam dapat membatalkan
+// reservasinya sendiri, sedangkan staff lokasi TV (atau admin) dapat membatalkan
+// reservasi siapa pun. Slot yang dilepas ditawarkan ke antrean waitlist.
+func (s *BookingService) CancelReservation(ctx context.Context, actor Actor, reservationID uint) (*models.Reservation, error) {
+	res, err := s.store.Reservations().FindByID(ctx, reservationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrReservationNotFound
+		}
+		return nil, err
+	}
+
+	tv, err := s.store.TVs().FindByID(ctx, res.TVID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTVNotFound
+		}
+		return nil, err
+	}
+	if res.BorrowerID != actor.UserID {
+		ok, err := canManage(ctx, s.store.Staff(), actor, tv.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		// Reservasi orang lain disembunyikan dari mahasiswa
+		if !ok {
+			return nil, ErrReservationNotFound
+		}
+	}
+
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		// Status dibaca ulang di bawah kunci TV agar pembatalan ganda tidak
+		// melepas slot dan menawarkannya ke antrean dua kali
+		res, tv, err = lockReservation(ctx, tx, res.ID, tv.ID)
+		if err != nil {
+			return err
+		}
+		if res.Status != models.ReservationBooked {
+			return ErrReservationNotBooked
+		}
+		if err := tx.Reservations().UpdateStatus(ctx, res.ID, models.ReservationCancelled); err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("The session on TV %d at %s you were invited to has been cancelled.", res.TVID, res.TimeSlot)
+		if err := notifyParticipants(ctx, tx, res.ID, msg); err != nil {
+			return err
+		}
+		return s.releaseSlot(ctx, tx, *tv, res.TimeSlot)
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Status = models.ReservationCancelled
+	return res, nil
+}
+
+// lockReservation dipanggil di dalam transaksi: mengunci TV reservasi id lalu
+// membaca ulang reservasi dan TV-nya, sehingga status yang diperiksa tidak
+// berubah sampai transaksi selesai. Reservasi yang baru saja dipindahkan ke TV
+// lain dibaca ulang setelah TV barunya ikut dikunci.
+func lockReservation(ctx context.Context, tx repository.Store, id uint, tvID int) (*models.Reservation, *models.TVInfo, error) {
+	for {
+		if err := tx.TVs().Lock(ctx, tvID); err != nil {
+			return nil, nil, err
+		}
+		res, err := tx.Reservations().FindByID(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if res.TVID != tvID {
+			tvID = res.TVID
+			continue
+		}
+		tv, err := tx.TVs().FindByID(ctx, tvID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return res, tv, nil
+	}
+}
+
+// recordAvailability mencatat perubahan ketersediaan slot (atau seluruh TV jika
+// slot kosong) agar diteruskan ke klien GET /api/availability/stream.
+func recordAvailability(ctx context.Context, tx repository.Store, eventType string, tv models.TVInfo, slot string) error {
+	return tx.AvailabilityEvents().Append(ctx, &models.AvailabilityEvent{
+		Type:       eventType,
+		LocationID: tv.LocationID,
+		TVID:       tv.ID,
+		TimeSlot:   slot,
+	})
+}
+
+// SettleResult merangkum reservasi yang diubah oleh SettleReservations.
+type SettleResult struct {
+	NoShows   int64
+	Completed int64
+	// PlayedMinutes adalah lama bermain setiap sesi yang baru selesai.
+	PlayedMinutes []int
+}
+
+// SettleReservations menandai reservasi booked yang melewati batas toleransi
+// sebagai no_show, dan reservasi checked_in yang slotnya sudah berakhir sebagai
+// completed. Slot yang dilepas no_show ditawarkan ke antrean waitlist.
+// Dijalankan berkala oleh scheduler.
+func (s *BookingService) SettleReservations(ctx context.Context) (SettleResult, error) {
+	var result SettleResult
+	now := s.Now()
+
+	noShows, err := s.store.Reservations().TransitionStatus(ctx,
+		models.ReservationBooked, models.ReservationNoShow, SlotKey(now.Add(-s.policy.NoShowGrace)))
+	if err != nil {
+		return result, err
+	}
+	result.NoShows = int64(len(noShows))
+
+	for _, r := range noShows {
+		tv, err := s.store.TVs().FindByID(ctx, r.TVID)
+		if err != nil {
+			return result, err
+		}
+		err = s.store.Transaction(ctx, func(tx repository.Store) error {
+			return s.releaseSlot(ctx, tx, *tv, r.TimeSlot)
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	completed, err := s.store.Reservations().TransitionStatus(ctx,
+		models.ReservationCheckedIn, models.ReservationCompleted, SlotKey(now.Add(-time.Hour)))
+	if err != nil {
+		return result, err
+	}
+	result.Completed = int64(len(completed))
+
+	// Sesi yang berjalan sampai slot berakhir dicatat lama bermainnya
+	for _, r := range completed {
+		start, end, err := sessionBounds(r)
+		if err != nil {
+			return result, err
+		}
+		played := playedMinutes(r, start, end)
+		r.PlayedMinutes = &played
+		if err := s.store.Reservations().Update(ctx, &r); err != nil {
+			return result, err
+		}
+		result.PlayedMinutes = append(result.PlayedMinutes, played)
+	}
+	return result, nil
+}
+
+// LocationUtilization adalah jumlah TV yang sedang dipakai (sudah check-in pada
+// slot saat ini) dibandingkan TV yang beroperasi di sebuah lokasi.
+type LocationUtilization struct {
+	LocationID int
+	InUse      int
+	InService  int
+}
+
+// Utilization menghitung pemakaian TV pada slot yang sedang berjalan per lokasi.
+func (s *BookingService) Utilization(ctx context.Context) ([]LocationUtilization, error) {
+	tvs, err := s.store.TVs().List(ctx, repository.TVFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	currentSlot := s.Now().UTC().Truncate(time.Hour)
+	from, to := SlotKey(currentSlot), SlotKey(currentSlot.Add(time.Hour))
+
+	byLocation := map[int]*LocationUtilization{}
+	var order []int
+	for _, tv := range tvs {
+		u, ok := byLocation[tv.LocationID]
+		if !ok {
+			u = &LocationUtilization{LocationID: tv.LocationID}
+			byLocation[tv.LocationID] = u
+			order = append(order, tv.LocationID)
+		}
+		if tv.OutOfService {
+			continue
+		}
+		u.InService++
+
+		reservations, err := s.store.Reservations().ListActiveByTV(ctx, tv.ID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range reservations {
+			if r.Status == models.ReservationCheckedIn {
+				u.InUse++
+				break
+			}
+		}
+	}
+
+	result := make([]LocationUtilization, 0, len(order))
+	for _, id := range order {
+		result = append(result, *byLocation[id])
+	}
+	return result, nil
+}
@@ -0,0 +1,446 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/config"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository/memory"
+	"playcorner-be/internal/services"
+)
+
+// fixture menyiapkan BookingService di atas memory store dengan satu lokasi
+// (08:00–22:00 WIB), dua TV PS5, staff s1, dan jam yang dapat digeser tes.
+type fixture struct {
+	svc   *services.BookingService
+	store *memory.Store
+	tz    *time.Location
+	now   time.Time
+	staff services.Actor
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	tz, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := memory.New()
+	for _, id := range []string{"u1", "u2", "u3"} {
+		store.AddUser(models.User{ID: id})
+	}
+	store.AddUser(models.User{ID: "s1", Role: models.RoleStaff})
+	loc := store.AddLocation(models.Location{Name: "FILKOM", Timezone: "Asia/Jakarta", OpenHour: 8, CloseHour: 22})
+	game := store.AddGame(models.Game{Title: "FC"})
+	store.AddTV(models.TVInfo{ID: 1, ConsoleType: "PS5", LocationID: loc.ID}, game.ID)
+	store.AddTV(models.TVInfo{ID: 2, ConsoleType: "PS5", LocationID: loc.ID}, game.ID)
+	if err := store.Staff().Assign(context.Background(), "s1", loc.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fixture{
+		store: store,
+		tz:    tz,
+		// Senin, 2 Maret 2026 pukul 09:30 WIB
+		now:   time.Date(2026, time.March, 2, 9, 30, 0, 0, tz),
+		staff: services.Actor{UserID: "s1", Role: models.RoleStaff},
+	}
+	f.svc = services.NewBookingService(store, config.BookingConfig{
+		MaxHoursPerDay:      4,
+		MaxAdvance:          7 * 24 * time.Hour,
+		MaxConsecutiveHours: 3,
+		CheckInOpensBefore:  15 * time.Minute,
+		NoShowGrace:         15 * time.Minute,
+		WaitlistOfferWindow: 10 * time.Minute,
+	})
+	f.svc.Now = func() time.Time { return f.now }
+	return f
+}
+
+// slot mengembalikan slot pada jam hour WIB, day hari setelah hari ini.
+func (f *fixture) slot(day, hour int) string {
+	return time.Date(2026, time.March, 2+day, hour, 0, 0, 0, f.tz).UTC().Format(time.RFC3339)
+}
+
+// at menggeser jam fixture ke hari dan jam:menit WIB tertentu.
+func (f *fixture) at(day, hour, minute int) {
+	f.now = time.Date(2026, time.March, 2+day, hour, minute, 0, 0, f.tz)
+}
+
+func (f *fixture) book(t *testing.T, userID string, tvID int, slot string) *models.Reservation {
+	t.Helper()
+	res, err := f.svc.CreateReservation(context.Background(), userID, tvID, slot)
+	if err != nil {
+		t.Fatalf("book TV %d at %s for %s: %v", tvID, slot, userID, err)
+	}
+	return res
+}
+
+// concurrently menjalankan fn n kali secara paralel, dimulai bersamaan setelah
+// semua goroutine siap, dan mengembalikan error setiap pemanggilan sesuai urutan i.
+func concurrently(n int, fn func(i int) error) []error {
+	var ready, done sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([]error, n)
+	for i := range n {
+		ready.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			ready.Done()
+			<-start
+			errs[i] = fn(i)
+		}()
+	}
+	ready.Wait()
+	close(start)
+	done.Wait()
+	return errs
+}
+
+func (f *fixture) checkIn(t *testing.T, res *models.Reservation) {
+	t.Helper()
+	if _, _, err := f.svc.CheckIn(context.Background(), f.staff, res.ID, nil); err != nil {
+		t.Fatalf("check in reservation %d: %v", res.ID, err)
+	}
+}
+
+// active mengembalikan reservasi userID yang masih memegang slot dalam seminggu ke depan.
+func (f *fixture) active(t *testing.T, userID string) []models.Reservation {
+	t.Helper()
+	list, err := f.store.Reservations().ListActiveByBorrower(context.Background(), userID, f.slot(0, 0), f.slot(8, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return list
+}
+
+// code mengembalikan kode apperr dari err, atau string kosong jika err nil.
+func code(err error) string {
+	if err == nil {
+		return ""
+	}
+	var appErr *apperr.Error
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return err.Error()
+}
+
+// item adalah pasangan TV dan slot relatif terhadap hari fixture.
+type item struct{ tv, day, hour int }
+
+func (f *fixture) items(list []item) []models.BookingItem {
+	out := make([]models.BookingItem, len(list))
+	for i, it := range list {
+		out[i] = models.BookingItem{TVID: it.tv, Timeslot: f.slot(it.day, it.hour)}
+	}
+	return out
+}
+
+func TestCreateBooking(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []item
+		items    []item
+		wantCode string
+		// wantActive adalah jumlah reservasi aktif u1 setelah booking.
+		wantActive int
+	}{
+		{
+			name:       "books every item",
+			items:      []item{{1, 0, 10}, {2, 0, 10}},
+			wantActive: 2,
+		},
+		{
+			name:     "rejects the whole booking when one slot is taken",
+			existing: []item{{2, 0, 11}},
+			items:    []item{{1, 0, 11}, {2, 0, 11}},
+			wantCode: "SLOT_TAKEN",
+		},
+		{
+			name:     "rejects the same TV and slot twice",
+			items:    []item{{1, 0, 10}, {1, 0, 10}},
+			wantCode: "VALIDATION_FAILED",
+		},
+		{
+			name:       "allows up to the daily quota",
+			items:      []item{{1, 0, 10}, {1, 0, 11}, {1, 0, 12}, {1, 0, 13}},
+			wantActive: 4,
+		},
+		{
+			name:     "rejects going over the daily quota",
+			items:    []item{{1, 0, 10}, {1, 0, 11}, {1, 0, 12}, {1, 0, 13}, {1, 0, 14}},
+			wantCode: "BOOKING_QUOTA_EXCEEDED",
+		},
+		{
+			name:       "counts the quota per day",
+			items:      []item{{1, 0, 10}, {1, 0, 11}, {1, 0, 12}, {1, 0, 13}, {1, 1, 10}},
+			wantActive: 5,
+		},
+		{
+			name:     "rejects a slot beyond the booking window",
+			items:    []item{{1, 8, 10}},
+			wantCode: "VALIDATION_FAILED",
+		},
+		{
+			name:     "rejects a slot that has already ended",
+			items:    []item{{1, 0, 8}},
+			wantCode: "VALIDATION_FAILED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			for _, it := range f.items(tt.existing) {
+				f.book(t, "u2", it.TVID, it.Timeslot)
+			}
+
+			_, reservations, err := f.svc.CreateBooking(context.Background(), "u1", f.items(tt.items))
+			if got := code(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			if err == nil && len(reservations) != len(tt.items) {
+				t.Fatalf("got %d reservations, want %d", len(reservations), len(tt.items))
+			}
+			if got := len(f.active(t, "u1")); got != tt.wantActive {
+				t.Fatalf("u1 holds %d active reservations, want %d", got, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestCreateReservation(t *testing.T) {
+	tests := []struct {
+		name    string
+		tvID    int
+		slot    func(f *fixture) string
+		setup   func(t *testing.T, f *fixture)
+		wantErr string
+	}{
+		{name: "books a free slot", tvID: 1, slot: func(f *fixture) string { return f.slot(0, 12) }},
+		{
+			name: "accepts a slot written in another offset", tvID: 1,
+			slot: func(f *fixture) string { return "2026-03-02T12:00:00+07:00" },
+		},
+		{
+			name: "rejects a booked slot", tvID: 1,
+			slot:    func(f *fixture) string { return f.slot(0, 12) },
+			setup:   func(t *testing.T, f *fixture) { f.book(t, "u2", 1, f.slot(0, 12)) },
+			wantErr: "SLOT_TAKEN",
+		},
+		{
+			name: "rejects a slot outside opening hours", tvID: 1,
+			slot:    func(f *fixture) string { return f.slot(0, 7) },
+			wantErr: "VALIDATION_FAILED",
+		},
+		{
+			name: "rejects a slot that does not start on the hour", tvID: 1,
+			slot:    func(f *fixture) string { return "2026-03-02T12:30:00+07:00" },
+			wantErr: "VALIDATION_FAILED",
+		},
+		{
+			name: "rejects a TV that is out of service", tvID: 1,
+			slot: func(f *fixture) string { return f.slot(0, 12) },
+			setup: func(t *testing.T, f *fixture) {
+				if err := f.store.TVs().UpdateService(context.Background(), 1, true, "broken"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: "TV_OUT_OF_SERVICE",
+		},
+		{name: "rejects an unknown TV", tvID: 9, slot: func(f *fixture) string { return f.slot(0, 12) }, wantErr: "TV_NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+
+			res, err := f.svc.CreateReservation(context.Background(), "u1", tt.tvID, tt.slot(f))
+			if code(err) != tt.wantErr {
+				t.Fatalf("error = %v, want %s", err, tt.wantErr)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Slot selalu disimpan dalam bentuk kanonik UTC
+			if res.TimeSlot != f.slot(0, 12) || res.Status != models.ReservationBooked {
+				t.Fatalf("reservation = %s (%s), want booked at %s", res.TimeSlot, res.Status, f.slot(0, 12))
+			}
+		})
+	}
+}
+
+func TestCreateReservationConcurrent(t *testing.T) {
+	f := newFixture(t)
+	slot := f.slot(0, 15)
+	users := []string{"u1", "u2", "u3"}
+
+	errs := concurrently(len(users), func(i int) error {
+		_, err := f.svc.CreateReservation(context.Background(), users[i], 1, slot)
+		return err
+	})
+
+	booked := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			booked++
+		case !errors.Is(err, services.ErrSlotTaken):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if booked != 1 {
+		t.Fatalf("%d concurrent bookings succeeded, want exactly 1", booked)
+	}
+}
+
+func TestCreateReservationQuotaConcurrent(t *testing.T) {
+	f := newFixture(t)
+	for hour := 10; hour < 13; hour++ {
+		f.book(t, "u1", 1, f.slot(0, hour))
+	}
+	// Sisa kuota u1 hari ini tinggal satu jam, diperebutkan tiga pemesanan paralel
+	slots := []item{{1, 0, 15}, {2, 0, 16}, {1, 0, 17}}
+
+	errs := concurrently(len(slots), func(i int) error {
+		_, err := f.svc.CreateReservation(context.Background(), "u1", slots[i].tv, f.slot(slots[i].day, slots[i].hour))
+		return err
+	})
+
+	booked := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			booked++
+		case !errors.Is(err, services.ErrBookingQuota):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if booked != 1 {
+		t.Fatalf("%d concurrent bookings succeeded, want exactly 1", booked)
+	}
+	if got := len(f.active(t, "u1")); got != 4 {
+		t.Fatalf("u1 holds %d slots, want 4", got)
+	}
+}
+
+func TestTVStatus(t *testing.T) {
+	f := newFixture(t)
+	f.book(t, "u1", 1, f.slot(0, 12))
+	// Reservasi hari lain tidak memengaruhi ketersediaan hari ini
+	f.book(t, "u1", 1, f.slot(1, 13))
+
+	status, err := f.svc.TVStatus(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.TimeSlots) != 14 {
+		t.Fatalf("got %d slots, want 14 (08:00–22:00)", len(status.TimeSlots))
+	}
+	for _, slot := range status.TimeSlots {
+		want := "available"
+		if slot.StartTime == f.slot(0, 12) {
+			want = "unavailable"
+		}
+		if slot.Availability != want {
+			t.Fatalf("slot %s is %s, want %s", slot.StartTime, slot.Availability, want)
+		}
+	}
+}
+
+func TestCheckIn(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   services.Actor
+		hour    int
+		minute  int
+		wantErr string
+	}{
+		{name: "opens 15 minutes before the slot", actor: services.Actor{UserID: "s1", Role: models.RoleStaff}, hour: 11, minute: 45},
+		{name: "accepts within the grace period", actor: services.Actor{UserID: "s1", Role: models.RoleStaff}, hour: 12, minute: 15},
+		{name: "rejects too early", actor: services.Actor{UserID: "s1", Role: models.RoleStaff}, hour: 11, minute: 44, wantErr: "CHECK_IN_CLOSED"},
+		{name: "rejects after the grace period", actor: services.Actor{UserID: "s1", Role: models.RoleStaff}, hour: 12, minute: 16, wantErr: "CHECK_IN_CLOSED"},
+		{name: "rejects staff of another location", actor: services.Actor{UserID: "s2", Role: models.RoleStaff}, hour: 12, wantErr: "LOCATION_FORBIDDEN"},
+		{name: "lets an admin check in anywhere", actor: services.Actor{UserID: "a1", Role: models.RoleAdmin}, hour: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			res := f.book(t, "u1", 1, f.slot(0, 12))
+			f.at(0, tt.hour, tt.minute)
+
+			got, _, err := f.svc.CheckIn(context.Background(), tt.actor, res.ID, nil)
+			if code(err) != tt.wantErr {
+				t.Fatalf("error = %v, want %s", err, tt.wantErr)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if got.Status != models.ReservationCheckedIn || got.CheckedInAt == nil {
+				t.Fatalf("reservation = %s (checked in at %v), want checked_in", got.Status, got.CheckedInAt)
+			}
+
+			// Check-in kedua ditolak karena reservasi tidak lagi booked
+			if _, _, err := f.svc.CheckIn(context.Background(), tt.actor, res.ID, nil); code(err) != "RESERVATION_NOT_BOOKED" {
+				t.Fatalf("second check-in error = %v, want RESERVATION_NOT_BOOKED", err)
+			}
+		})
+	}
+}
+
+func TestSettleReservations(t *testing.T) {
+	f := newFixture(t)
+	noShow := f.book(t, "u1", 1, f.slot(0, 10))
+	attended := f.book(t, "u2", 2, f.slot(0, 10))
+	upcoming := f.book(t, "u3", 1, f.slot(0, 11))
+	f.at(0, 10, 0)
+	f.checkIn(t, attended)
+
+	// 10:16 melewati toleransi slot 10:00, tetapi slot tersebut belum berakhir
+	f.at(0, 10, 16)
+	result, err := f.svc.SettleReservations(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.NoShows != 1 || result.Completed != 0 {
+		t.Fatalf("settle at 10:16 = %+v, want 1 no-show", result)
+	}
+
+	f.at(0, 11, 0)
+	if result, err = f.svc.SettleReservations(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if result.NoShows != 0 || result.Completed != 1 {
+		t.Fatalf("settle at 11:00 = %+v, want 1 completed", result)
+	}
+
+	want := map[uint]string{
+		noShow.ID:   models.ReservationNoShow,
+		attended.ID: models.ReservationCompleted,
+		upcoming.ID: models.ReservationBooked,
+	}
+	for id, status := range want {
+		res, err := f.store.Reservations().FindByID(context.Background(), id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != status {
+			t.Fatalf("reservation %d = %s, want %s", id, res.Status, status)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"time"
+)
+
+// CheckOut mengakhiri sesi yang sedang berjalan lebih awal. Jika reservasinya
+// sudah diperpanjang, sesi yang diakhiri adalah perpanjangan yang sedang
+// berjalan, dan perpanjangan berikutnya dibatalkan. Sisa slot ditampilkan
+// sebagai waktu walk-in di ketersediaan slot.
+func (s *BookingService) CheckOut(ctx context.Context, actor Actor, reservationID uint) (*models.Reservation, error) {
+	res, err := s.findReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	tv, err := s.store.TVs().FindByID(ctx, res.TVID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTVNotFound
+		}
+		return nil, err
+	}
+	if res.BorrowerID != actor.UserID {
+		ok, err := canManage(ctx, s.store.Staff(), actor, tv.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		// Reservasi orang lain disembunyikan dari mahasiswa
+		if !ok {
+			return nil, ErrReservationNotFound
+		}
+	}
+	if res.Status != models.ReservationCheckedIn {
+		return nil, ErrSessionNotActive
+	}
+
+	now := s.Now()
+	start, end, err := sessionBounds(*res)
+	if err != nil {
+		return nil, err
+	}
+	// Ikuti rantai perpanjangan sampai slot yang sedang berjalan
+	for !now.Before(end) {
+		if res, err = s.extensionOf(ctx, s.store, *res, end); err != nil {
+			return nil, err
+		}
+		if res == nil {
+			return nil, ErrSessionNotActive
+		}
+		if start, end, err = sessionBounds(*res); err != nil {
+			return nil, err
+		}
+	}
+	if now.Before(start) {
+		return nil, ErrSessionNotActive
+	}
+
+	played := playedMinutes(*res, start, now)
+	res.Status = models.ReservationCompleted
+	res.CheckedOutAt = &now
+	res.PlayedMinutes = &played
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Reservations().Update(ctx, res); err != nil {
+			return err
+		}
+		if err := recordAvailability(ctx, tx, models.AvailabilityCheckedOut, *tv, res.TimeSlot); err != nil {
+			return err
+		}
+		// Perpanjangan yang belum dimulai tidak lagi dipakai
+		for prev, next := *res, end; ; next = next.Add(time.Hour) {
+			ext, err := s.extensionOf(ctx, tx, prev, next)
+			if err != nil || ext == nil {
+				return err
+			}
+			if err := tx.Reservations().UpdateStatus(ctx, ext.ID, models.ReservationCancelled); err != nil {
+				return err
+			}
+			if err := s.releaseSlot(ctx, tx, *tv, ext.TimeSlot); err != nil {
+				return err
+			}
+			prev = *ext
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// extensionOf mencari perpanjangan res pada slot next, atau nil jika tidak ada.
+// Slot yang sama dapat berisi sesi yang sudah check-out dan sesi walk-in, jadi
+// semua reservasi di slot tersebut diperiksa.
+func (s *BookingService) extensionOf(ctx context.Context, store repository.Store, res models.Reservation, next time.Time) (*models.Reservation, error) {
+	reservations, err := store.Reservations().ListActiveByTV(ctx, res.TVID, SlotKey(next), SlotKey(next.Add(time.Hour)))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reservations {
+		if r.ExtendsID != nil && *r.ExtendsID == res.ID && r.Status == models.ReservationCheckedIn {
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
+
+// playedMinutes menghitung lama bermain dari check-in (atau awal slot jika
+// check-in dilakukan lebih awal) sampai until.
+func playedMinutes(r models.Reservation, start, until time.Time) int {
+	from := start
+	if r.CheckedInAt != nil && r.CheckedInAt.After(from) {
+		from = *r.CheckedInAt
+	}
+	if !until.After(from) {
+		return 0
+	}
+	return int(until.Sub(from) / time.Minute)
+}
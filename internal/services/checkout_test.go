@@ -0,0 +1,88 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+)
+
+func TestCheckOut(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor services.Actor
+		// checkedIn dan extended menyiapkan sesi u1 di TV 1 pukul 10:00.
+		checkedIn bool
+		extended  bool
+		// minute adalah menit setelah 10:00 saat check-out.
+		minute     int
+		wantCode   string
+		wantSlot   int
+		wantPlayed int
+		// wantFree adalah slot perpanjangan yang harus dilepas.
+		wantFree bool
+	}{
+		{
+			name: "ends a session early", actor: services.Actor{UserID: "u1", Role: models.RoleStudent},
+			checkedIn: true, minute: 40, wantSlot: 10, wantPlayed: 40,
+		},
+		{
+			name: "staff ends a session", actor: services.Actor{UserID: "s1", Role: models.RoleStaff},
+			checkedIn: true, minute: 5, wantSlot: 10, wantPlayed: 5,
+		},
+		{
+			name: "ends the running extension", actor: services.Actor{UserID: "u1", Role: models.RoleStudent},
+			checkedIn: true, extended: true, minute: 80, wantSlot: 11, wantPlayed: 20,
+		},
+		{
+			name: "cancels an extension that has not started", actor: services.Actor{UserID: "u1", Role: models.RoleStudent},
+			checkedIn: true, extended: true, minute: 45, wantSlot: 10, wantPlayed: 45, wantFree: true,
+		},
+		{
+			name: "rejects a session that has not been checked in", actor: services.Actor{UserID: "u1", Role: models.RoleStudent},
+			minute: 10, wantCode: "SESSION_NOT_ACTIVE",
+		},
+		{
+			name: "hides another student's session", actor: services.Actor{UserID: "u2", Role: models.RoleStudent},
+			checkedIn: true, minute: 10, wantCode: "RESERVATION_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+			f.at(0, 9, 50)
+			res := f.book(t, "u1", 1, f.slot(0, 10))
+			if tt.checkedIn {
+				f.checkIn(t, res)
+			}
+			if tt.extended {
+				f.at(0, 10, 30)
+				if _, err := f.svc.ExtendReservation(ctx, services.Actor{UserID: "u1", Role: models.RoleStudent}, res.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			f.at(0, 10+tt.minute/60, tt.minute%60)
+			ended, err := f.svc.CheckOut(ctx, tt.actor, res.ID)
+			if got := code(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if ended.TimeSlot != f.slot(0, tt.wantSlot) || ended.Status != models.ReservationCompleted {
+				t.Fatalf("ended %s (%s), want completed at %s", ended.TimeSlot, ended.Status, f.slot(0, tt.wantSlot))
+			}
+			if ended.PlayedMinutes == nil || *ended.PlayedMinutes != tt.wantPlayed {
+				t.Fatalf("played minutes = %v, want %d", ended.PlayedMinutes, tt.wantPlayed)
+			}
+			_, err = f.store.Reservations().FindActiveBySlot(ctx, 1, f.slot(0, 11))
+			if free := err != nil; tt.extended && free != tt.wantFree {
+				t.Fatalf("extension slot free = %v, want %v", free, tt.wantFree)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"strings"
+	"time"
+)
+
+// deviceTokenPrefix memudahkan mengenali token perangkat, misalnya saat bocor ke log.
+const deviceTokenPrefix = "pcd_"
+
+// DeviceService mengelola perangkat kiosk dan tokennya.
+type DeviceService struct {
+	store repository.Store
+	Now   func() time.Time
+}
+
+func NewDeviceService(store repository.Store) *DeviceService {
+	return &DeviceService{store: store, Now: time.Now}
+}
+
+// NewDevice adalah perangkat yang baru didaftarkan beserta tokennya. Token
+// hanya dikembalikan sekali dan tidak dapat diambil lagi.
+type NewDevice struct {
+	models.Device
+	Token string `json:"token"`
+}
+
+// Register mendaftarkan perangkat kiosk untuk sebuah lokasi dan membuat tokennya.
+func (s *DeviceService) Register(ctx context.Context, name string, locationID int) (*NewDevice, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, invalid("name", "Name is required")
+	}
+	if _, err := s.store.Locations().FindByID(ctx, locationID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrLocationNotFound
+		}
+		return nil, err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := deviceTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	device := &models.Device{Name: name, LocationID: locationID, TokenHash: hashDeviceToken(token)}
+	if err := s.store.Devices().Create(ctx, device); err != nil {
+		return nil, err
+	}
+	return &NewDevice{Device: *device, Token: token}, nil
+}
+
+func (s *DeviceService) List(ctx context.Context) ([]models.Device, error) {
+	return s.store.Devices().List(ctx)
+}
+
+// Revoke mencabut token perangkat. Koneksi kiosk yang masih terbuka diputus
+// saat perangkatnya diperiksa ulang lewat Active.
+func (s *DeviceService) Revoke(ctx context.Context, id uint) error {
+	if _, err := s.store.Devices().FindByID(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrDeviceNotFound
+		}
+		return err
+	}
+	return s.store.Devices().Revoke(ctx, id, s.Now())
+}
+
+// Authenticate mencari perangkat aktif pemilik token dan mencatat waktu terakhir terlihat.
+func (s *DeviceService) Authenticate(ctx context.Context, token string) (*models.Device, error) {
+	if !strings.HasPrefix(token, deviceTokenPrefix) {
+		return nil, ErrInvalidDeviceToken
+	}
+	device, err := s.store.Devices().FindByTokenHash(ctx, hashDeviceToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidDeviceToken
+		}
+		return nil, err
+	}
+	if err := s.store.Devices().Touch(ctx, device.ID, s.Now()); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// Active memeriksa apakah perangkat masih terdaftar dan belum dicabut.
+func (s *DeviceService) Active(ctx context.Context, id uint) (bool, error) {
+	device, err := s.store.Devices().FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return device.RevokedAt == nil, nil
+}
+
+// hashDeviceToken menghasilkan hash yang disimpan di database. Token sudah acak
+// 256 bit, jadi SHA-256 tanpa salt cukup dan tetap dapat dicari langsung.
+func hashDeviceToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
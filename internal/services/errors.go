@@ -0,0 +1,48 @@
+// Package services
+package services
+
+import "playcorner-be/internal/apperr"
+
+// Kesalahan bisnis yang dikembalikan service. Pesannya aman ditampilkan ke klien,
+// kodenya stabil, dan status HTTP-nya ditentukan oleh jenis kesalahannya.
+var (
+	ErrUserNotFound         = apperr.NotFound("USER_NOT_FOUND", "User not found")
+	ErrTVNotFound           = apperr.NotFound("TV_NOT_FOUND", "TV not found")
+	ErrIssueNotFound        = apperr.NotFound("ISSUE_NOT_FOUND", "Issue not found")
+	ErrLocationNotFound     = apperr.NotFound("LOCATION_NOT_FOUND", "Location not found")
+	ErrReservationNotFound  = apperr.NotFound("RESERVATION_NOT_FOUND", "Reservation not found")
+	ErrDeviceNotFound       = apperr.NotFound("DEVICE_NOT_FOUND", "Device not found")
+	ErrWaitlistNotFound     = apperr.NotFound("WAITLIST_ENTRY_NOT_FOUND", "Waitlist entry not found")
+	ErrInvitationNotFound   = apperr.NotFound("INVITATION_NOT_FOUND", "Invitation not found")
+	ErrSeriesNotFound       = apperr.NotFound("SERIES_NOT_FOUND", "Reservation series not found")
+	ErrSlotTaken            = apperr.Conflict("SLOT_TAKEN", "Timeslot is already booked")
+	ErrTVOutOfService       = apperr.Conflict("TV_OUT_OF_SERVICE", "TV is out of service")
+	ErrReservationNotBooked = apperr.Conflict("RESERVATION_NOT_BOOKED", "Reservation is not booked")
+	ErrCheckInClosed        = apperr.Conflict("CHECK_IN_CLOSED", "Check-in is not open for this reservation")
+	ErrSlotAvailable        = apperr.Conflict("SLOT_AVAILABLE", "Timeslot is still available and can be booked directly")
+	ErrAlreadyWaitlisted    = apperr.Conflict("ALREADY_WAITLISTED", "You are already on the waitlist for this timeslot")
+	ErrOfferNotActive       = apperr.Conflict("OFFER_NOT_ACTIVE", "There is no active offer for this waitlist entry")
+	ErrWaitlistClosed       = apperr.Conflict("WAITLIST_ENTRY_CLOSED", "Waitlist entry is no longer active")
+	ErrAlreadyInvited       = apperr.Conflict("ALREADY_INVITED", "User is already invited to this reservation")
+	ErrTooManyPlayers       = apperr.Conflict("TOO_MANY_PLAYERS", "A reservation can have at most 3 invited players")
+	ErrInvitationClosed     = apperr.Conflict("INVITATION_CLOSED", "Invitation has already been answered")
+	ErrRescheduleClosed     = apperr.Conflict("RESCHEDULE_CLOSED", "Reservation can no longer be rescheduled once its slot has started")
+	ErrSeriesOccurrence     = apperr.Conflict("SERIES_OCCURRENCE", "Series reservations can only be moved through their series")
+	ErrTVBusy               = apperr.Conflict("TV_BUSY", "TV is in use or reserved for the current slot")
+	ErrLocationClosed       = apperr.Conflict("LOCATION_CLOSED", "The location is closed right now")
+	ErrSessionNotActive     = apperr.Conflict("SESSION_NOT_ACTIVE", "Reservation has no session in progress")
+	ErrExtensionUnavailable = apperr.Conflict("EXTENSION_UNAVAILABLE", "The location closes after this slot")
+	ErrConsecutiveLimit     = apperr.Conflict("CONSECUTIVE_LIMIT", "Session would exceed the limit of consecutive hours on one TV")
+	ErrBookingQuota         = apperr.Conflict("BOOKING_QUOTA_EXCEEDED", "Booking would exceed the daily limit of hours per student")
+	ErrSeriesConflict       = apperr.Conflict("SERIES_CONFLICT", "Series conflicts with existing bookings; review the conflicts or retry with skipConflicts")
+	ErrSeriesNotPending     = apperr.Conflict("SERIES_NOT_PENDING", "Series has already been reviewed")
+	ErrSeriesClosed         = apperr.Conflict("SERIES_CLOSED", "Series has been rejected or cancelled")
+	ErrInvalidCredentials   = apperr.Unauthorized("INVALID_CREDENTIALS", "Invalid identifier or password")
+	ErrInvalidToken         = apperr.Unauthorized("INVALID_REFRESH_TOKEN", "Invalid or expired refresh token")
+	ErrInvalidDeviceToken   = apperr.Unauthorized("INVALID_DEVICE_TOKEN", "Invalid or revoked device token")
+	ErrLocationForbidden    = apperr.Forbidden("LOCATION_FORBIDDEN", "You do not manage this TV's location")
+	ErrSeriesForbidden      = apperr.Forbidden("SERIES_FORBIDDEN", "Only staff of the series location can change an approved series")
+)
+
+// invalid menandakan input yang tidak valid pada field tertentu.
+func invalid(field, msg string) error { return apperr.Field(field, msg) }
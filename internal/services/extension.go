@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"time"
+)
+
+// ExtendReservation memperpanjang sesi yang sedang berjalan ke slot berikutnya
+// pada TV yang sama. Slot berikutnya harus kosong, masih dalam jam buka lokasi,
+// dan peminjam harus masih dalam kuota harian serta batas jam berturut-turut
+// lokasi.
+// Perpanjangan disimpan sebagai reservasi checked_in baru yang menunjuk ke
+// reservasi semula.
+func (s *BookingService) ExtendReservation(ctx context.Context, actor Actor, reservationID uint) (*models.Reservation, error) {
+	res, err := s.findReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	tv, err := s.store.TVs().FindByID(ctx, res.TVID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTVNotFound
+		}
+		return nil, err
+	}
+	if res.BorrowerID != actor.UserID {
+		ok, err := canManage(ctx, s.store.Staff(), actor, tv.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		// Reservasi orang lain disembunyikan dari mahasiswa
+		if !ok {
+			return nil, ErrReservationNotFound
+		}
+	}
+
+	start, end, err := sessionBounds(*res)
+	if err != nil {
+		return nil, err
+	}
+	now := s.Now()
+	if res.Status != models.ReservationCheckedIn || now.Before(start) || !now.Before(end) {
+		return nil, ErrSessionNotActive
+	}
+	if tv.OutOfService {
+		return nil, ErrTVOutOfService
+	}
+	next, err := normalizeSlot(SlotKey(end), locationOf(*tv))
+	if err != nil {
+		return nil, ErrExtensionUnavailable
+	}
+
+	extension := &models.Reservation{
+		TVID:        res.TVID,
+		BorrowerID:  res.BorrowerID,
+		TimeSlot:    next,
+		Status:      models.ReservationCheckedIn,
+		CheckedInAt: &now,
+		BookingID:   res.BookingID,
+		ExtendsID:   &res.ID,
+		WalkIn:      res.WalkIn,
+	}
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := s.checkQuota(ctx, tx, res.BorrowerID, []plannedSlot{{tv: *tv, slot: next}}, 0); err != nil {
+			return err
+		}
+		// Kunci TV agar pemesanan paralel tidak lolos pemeriksaan slot bersamaan
+		if err := tx.TVs().Lock(ctx, tv.ID); err != nil {
+			return err
+		}
+		// Cek apakah slot berikutnya sudah dipesan atau sedang ditawarkan ke antrean waitlist
+		free, err := s.slotFree(ctx, tx, *tv, next)
+		if err != nil {
+			return err
+		}
+		if !free {
+			return ErrSlotTaken
+		}
+		if err := s.checkConsecutive(ctx, tx, *tv, *res, start); err != nil {
+			return err
+		}
+
+		if err := tx.Reservations().Create(ctx, extension); err != nil {
+			return slotConflict(err)
+		}
+
+		// Pemain yang sudah menerima undangan ikut bermain di slot perpanjangan
+		participants, err := tx.Participants().ListByReservation(ctx, res.ID)
+		if err != nil {
+			return err
+		}
+		for _, p := range participants {
+			if p.Status != models.ParticipantAccepted {
+				continue
+			}
+			carried := &models.ReservationParticipant{
+				ReservationID: extension.ID,
+				UserID:        p.UserID,
+				Status:        models.ParticipantAccepted,
+				Attended:      p.Attended,
+				RespondedAt:   p.RespondedAt,
+			}
+			if err := tx.Participants().Create(ctx, carried); err != nil {
+				return err
+			}
+		}
+		return recordAvailability(ctx, tx, models.AvailabilityBooked, *tv, next)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return extension, nil
+}
+
+// checkConsecutive dipanggil di dalam transaksi tx setelah TV dikunci dan
+// memastikan peminjam res tidak memegang tv lebih dari batas jam berturut-turut
+// lokasi setelah sesi yang dimulai pada start diperpanjang satu slot. Slot milik
+// peminjam setelah slot perpanjangan ikut dihitung karena perpanjangan dapat
+// menyambungkan sesi dengan reservasi berikutnya.
+func (s *BookingService) checkConsecutive(ctx context.Context, tx repository.Store, tv models.TVInfo, res models.Reservation, start time.Time) error {
+	limit := s.consecutiveLimit(tv)
+	next := start.Add(time.Hour)
+	from := start.Add(-time.Duration(limit) * time.Hour)
+	to := next.Add(time.Duration(limit+1) * time.Hour)
+	held, err := tx.Reservations().ListActiveByTV(ctx, res.TVID, SlotKey(from), SlotKey(to))
+	if err != nil {
+		return err
+	}
+	owned := map[string]bool{}
+	for _, r := range held {
+		if r.BorrowerID == res.BorrowerID {
+			owned[r.TimeSlot] = true
+		}
+	}
+
+	// Slot saat ini ditambah slot perpanjangan, lalu slot milik peminjam yang
+	// bersambung ke belakang dan ke depan
+	hours := 2
+	for slot := start.Add(-time.Hour); owned[SlotKey(slot)]; slot = slot.Add(-time.Hour) {
+		hours++
+	}
+	for slot := next.Add(time.Hour); owned[SlotKey(slot)]; slot = slot.Add(time.Hour) {
+		hours++
+	}
+	if hours > limit {
+		return ErrConsecutiveLimit
+	}
+	return nil
+}
+
+// consecutiveLimit mengembalikan batas jam berturut-turut yang diatur staff di
+// lokasi tv, atau MaxConsecutiveHours dari konfigurasi jika belum diatur.
+func (s *BookingService) consecutiveLimit(tv models.TVInfo) int {
+	if limit := locationOf(tv).MaxConsecutiveHours; limit != nil {
+		return *limit
+	}
+	return s.policy.MaxConsecutiveHours
+}
@@ -0,0 +1,154 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+)
+
+// setConsecutiveLimit mengatur batas jam berturut-turut lokasi fixture sebagai staff s1.
+func (f *fixture) setConsecutiveLimit(t *testing.T, hours int) {
+	t.Helper()
+	tv, err := f.store.TVs().FindByID(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := services.NewLocationService(f.store).SetBookingPolicy(context.Background(), f.staff, tv.LocationID, &hours); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtendReservation(t *testing.T) {
+	tests := []struct {
+		name string
+		// hour adalah jam sesi u1 di TV 1; jam fixture berada 30 menit setelahnya.
+		hour      int
+		checkedIn bool
+		// extended adalah jumlah perpanjangan yang sudah dilakukan sebelumnya.
+		extended int
+		setup    func(t *testing.T, f *fixture)
+		wantCode string
+	}{
+		{name: "extends into the next free slot", hour: 10, checkedIn: true},
+		{
+			name: "rejects when the next slot is booked", hour: 10, checkedIn: true,
+			setup:    func(t *testing.T, f *fixture) { f.book(t, "u2", 1, f.slot(0, 11)) },
+			wantCode: "SLOT_TAKEN",
+		},
+		{name: "allows up to the consecutive limit", hour: 10, checkedIn: true, extended: 1},
+		{name: "rejects going over the consecutive limit", hour: 10, checkedIn: true, extended: 2, wantCode: "CONSECUTIVE_LIMIT"},
+		{
+			name: "allows joining a later booking within the limit", hour: 10, checkedIn: true,
+			setup: func(t *testing.T, f *fixture) { f.book(t, "u1", 1, f.slot(0, 12)) },
+		},
+		{
+			name: "rejects joining a later booking over the limit", hour: 10, checkedIn: true,
+			setup: func(t *testing.T, f *fixture) {
+				f.book(t, "u1", 1, f.slot(0, 12))
+				f.book(t, "u1", 1, f.slot(0, 13))
+			},
+			wantCode: "CONSECUTIVE_LIMIT",
+		},
+		{
+			name: "applies the limit set for the location", hour: 10, checkedIn: true,
+			setup:    func(t *testing.T, f *fixture) { f.setConsecutiveLimit(t, 1) },
+			wantCode: "CONSECUTIVE_LIMIT",
+		},
+		{
+			name: "allows a location limit above the default", hour: 10, checkedIn: true, extended: 2,
+			setup: func(t *testing.T, f *fixture) { f.setConsecutiveLimit(t, 4) },
+		},
+		{name: "rejects extending past closing time", hour: 21, checkedIn: true, wantCode: "EXTENSION_UNAVAILABLE"},
+		{name: "rejects a session that has not been checked in", hour: 10, wantCode: "SESSION_NOT_ACTIVE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+			u1 := services.Actor{UserID: "u1", Role: models.RoleStudent}
+			f.at(0, tt.hour-1, 50)
+			res := f.book(t, "u1", 1, f.slot(0, tt.hour))
+			if tt.checkedIn {
+				f.checkIn(t, res)
+			}
+			// Setiap perpanjangan dilakukan selama slot sebelumnya berjalan
+			for i := 0; i < tt.extended; i++ {
+				f.at(0, tt.hour+i, 30)
+				ext, err := f.svc.ExtendReservation(ctx, u1, res.ID)
+				if err != nil {
+					t.Fatalf("extension %d: %v", i+1, err)
+				}
+				res = ext
+			}
+			f.at(0, tt.hour+tt.extended, 30)
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+
+			ext, err := f.svc.ExtendReservation(ctx, u1, res.ID)
+			if got := code(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if ext.TimeSlot != f.slot(0, tt.hour+tt.extended+1) || ext.Status != models.ReservationCheckedIn {
+				t.Fatalf("extension at %s (%s), want checked in at %s", ext.TimeSlot, ext.Status, f.slot(0, tt.hour+tt.extended+1))
+			}
+			if ext.ExtendsID == nil || *ext.ExtendsID != res.ID {
+				t.Fatalf("extension extends %v, want %d", ext.ExtendsID, res.ID)
+			}
+		})
+	}
+}
+
+func TestExtendReservationCarriesOver(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.at(0, 9, 50)
+	res := f.book(t, "u1", 1, f.slot(0, 10))
+
+	// u2 menerima undangan, sedangkan undangan u3 belum dijawab
+	invited, err := f.svc.InviteParticipant(ctx, "u1", res.ID, "u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.RespondInvitation(ctx, "u2", invited.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.InviteParticipant(ctx, "u1", res.ID, "u3"); err != nil {
+		t.Fatal(err)
+	}
+	f.checkIn(t, res)
+
+	f.at(0, 10, 30)
+	ext, err := f.svc.ExtendReservation(ctx, services.Actor{UserID: "u1", Role: models.RoleStudent}, res.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	participants, err := f.store.Participants().ListByReservation(ctx, ext.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(participants) != 1 || participants[0].UserID != "u2" || participants[0].Status != models.ParticipantAccepted {
+		t.Fatalf("extension participants = %+v, want only u2 accepted", participants)
+	}
+
+	// Perpanjangan sesi walk-in tetap ditandai walk-in
+	f.at(0, 12, 5)
+	walkIn, err := f.svc.StartWalkIn(ctx, f.staff, 2, "u3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.at(0, 12, 30)
+	ext, err = f.svc.ExtendReservation(ctx, f.staff, walkIn.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ext.WalkIn {
+		t.Fatal("extension of a walk-in session is not marked as walk-in")
+	}
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"time"
+)
+
+// SessionEndingWarning adalah seberapa awal layar kiosk diberi peringatan
+// bahwa sebuah sesi akan berakhir.
+const SessionEndingWarning = 5 * time.Minute
+
+// KioskService menyusun keadaan TV untuk layar kiosk di ruang game.
+type KioskService struct {
+	store repository.Store
+
+	// Now dapat diganti saat pengujian.
+	Now func() time.Time
+}
+
+func NewKioskService(store repository.Store) *KioskService {
+	return &KioskService{store: store, Now: time.Now}
+}
+
+// Board mengembalikan pemain saat ini dan booking berikutnya (dalam 24 jam)
+// untuk setiap TV di sebuah lokasi. Sesi saat ini adalah reservasi yang sudah
+// check-in dan sedang berjalan; booking berikutnya adalah reservasi booked
+// pertama yang belum berakhir, termasuk yang belum check-in pada slot ini.
+func (s *KioskService) Board(ctx context.Context, locationID int) (*models.KioskBoard, error) {
+	now := s.Now()
+	tvs, err := s.store.TVs().List(ctx, repository.TVFilter{LocationID: &locationID})
+	if err != nil {
+		return nil, err
+	}
+
+	from := now.UTC().Truncate(time.Hour)
+	players := map[string]string{}
+	board := &models.KioskBoard{LocationID: locationID, TVs: []models.KioskTV{}}
+	for _, tv := range tvs {
+		reservations, err := s.store.Reservations().ListActiveByTV(ctx, tv.ID, SlotKey(from), SlotKey(from.Add(24*time.Hour)))
+		if err != nil {
+			return nil, err
+		}
+
+		state := models.KioskTV{ID: tv.ID, ConsoleType: tv.ConsoleType, OutOfService: tv.OutOfService}
+		for _, r := range reservations {
+			start, end, err := sessionBounds(r)
+			if err != nil {
+				return nil, err
+			}
+			if !now.Before(end) {
+				continue
+			}
+
+			switch {
+			case r.Status == models.ReservationCheckedIn && !now.Before(start) && state.Current == nil:
+				state.Current, err = s.session(ctx, players, r, start, end)
+			// Perpanjangan sesi yang sedang berjalan menggeser waktu berakhirnya
+			case r.Status == models.ReservationCheckedIn && state.Current != nil && r.ExtendsID != nil && start.Equal(state.Current.EndsAt):
+				state.Current.EndsAt = end
+			case r.Status == models.ReservationBooked && state.Next == nil:
+				state.Next, err = s.session(ctx, players, r, start, end)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		board.TVs = append(board.TVs, state)
+	}
+	return board, nil
+}
+
+// session menyusun KioskSession dengan nama pemain, memakai players sebagai cache.
+func (s *KioskService) session(ctx context.Context, players map[string]string, r models.Reservation, start, end time.Time) (*models.KioskSession, error) {
+	name, ok := players[r.BorrowerID]
+	if !ok {
+		user, err := s.store.Users().FindByID(ctx, r.BorrowerID)
+		switch {
+		case err == nil:
+			name = user.Name
+		case !errors.Is(err, repository.ErrNotFound):
+			return nil, err
+		}
+		players[r.BorrowerID] = name
+	}
+	return &models.KioskSession{ReservationID: r.ID, Player: name, StartsAt: start, EndsAt: end}, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"strings"
+	"time"
+)
+
+// LocationService mengelola lokasi PlayCorner dan penugasan staff per lokasi.
+type LocationService struct {
+	store repository.Store
+}
+
+func NewLocationService(store repository.Store) *LocationService {
+	return &LocationService{store: store}
+}
+
+// LocationInput adalah data lokasi yang dapat diatur oleh admin.
+type LocationInput struct {
+	Name      string
+	Building  string
+	Timezone  string
+	OpenHour  int
+	CloseHour int
+}
+
+func (in LocationInput) validate() error {
+	if strings.TrimSpace(in.Name) == "" {
+		return invalid("name", "Name is required")
+	}
+	if _, err := time.LoadLocation(in.Timezone); err != nil || in.Timezone == "" {
+		return invalid("timezone", "Timezone must be a valid IANA time zone")
+	}
+	if in.OpenHour < 0 || in.CloseHour > 24 || in.OpenHour >= in.CloseHour {
+		return invalid("openHour", "Opening hours must satisfy 0 <= openHour < closeHour <= 24")
+	}
+	return nil
+}
+
+func (s *LocationService) List(ctx context.Context) ([]models.Location, error) {
+	return s.store.Locations().List(ctx)
+}
+
+func (s *LocationService) Get(ctx context.Context, id int) (*models.Location, error) {
+	location, err := s.store.Locations().FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrLocationNotFound
+	}
+	return location, err
+}
+
+func (s *LocationService) Create(ctx context.Context, in LocationInput) (*models.Location, error) {
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+
+	location := &models.Location{
+		Name:      strings.TrimSpace(in.Name),
+		Building:  in.Building,
+		Timezone:  in.Timezone,
+		OpenHour:  in.OpenHour,
+		CloseHour: in.CloseHour,
+	}
+	if err := s.store.Locations().Create(ctx, location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+func (s *LocationService) Update(ctx context.Context, id int, in LocationInput) (*models.Location, error) {
+	location, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := in.validate(); err != nil {
+		return nil, err
+	}
+
+	location.Name = strings.TrimSpace(in.Name)
+	location.Building = in.Building
+	location.Timezone = in.Timezone
+	location.OpenHour = in.OpenHour
+	location.CloseHour = in.CloseHour
+	if err := s.store.Locations().Update(ctx, location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// SetBookingPolicy mengatur batas jam berturut-turut seorang peminjam pada TV
+// yang sama di lokasi locationID. Hanya staff lokasi (atau admin) yang dapat
+// mengubahnya; nil mengembalikan batas ke MaxConsecutiveHours dari konfigurasi.
+func (s *LocationService) SetBookingPolicy(ctx context.Context, actor Actor, locationID int, maxConsecutiveHours *int) (*models.Location, error) {
+	location, err := s.Get(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := canManage(ctx, s.store.Staff(), actor, location.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLocationForbidden
+	}
+	if maxConsecutiveHours != nil && (*maxConsecutiveHours < 1 || *maxConsecutiveHours > 24) {
+		return nil, invalid("maxConsecutiveHours", "Max consecutive hours must be between 1 and 24")
+	}
+
+	location.MaxConsecutiveHours = maxConsecutiveHours
+	if err := s.store.Locations().Update(ctx, location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// AssignStaff memberikan hak pengelolaan lokasi kepada pengguna berperan staff.
+func (s *LocationService) AssignStaff(ctx context.Context, locationID int, userID string) (*models.StaffAssignment, error) {
+	if _, err := s.Get(ctx, locationID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.store.Users().FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if user.Role != models.RoleStaff {
+		return nil, invalid("userId", "Only staff users can be assigned to a location")
+	}
+
+	if err := s.store.Staff().Assign(ctx, user.ID, locationID); err != nil {
+		return nil, err
+	}
+	return &models.StaffAssignment{UserID: user.ID, LocationID: locationID}, nil
+}
+
+func (s *LocationService) UnassignStaff(ctx context.Context, locationID int, userID string) error {
+	return s.store.Staff().Unassign(ctx, userID, locationID)
+}
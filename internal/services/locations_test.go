@@ -0,0 +1,60 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+)
+
+func TestSetBookingPolicy(t *testing.T) {
+	two, zero := 2, 0
+	tests := []struct {
+		name     string
+		actor    services.Actor
+		hours    *int
+		wantCode string
+	}{
+		{name: "staff of the location sets a limit", actor: services.Actor{UserID: "s1", Role: models.RoleStaff}, hours: &two},
+		{name: "staff of the location resets to the default", actor: services.Actor{UserID: "s1", Role: models.RoleStaff}},
+		{name: "admin sets a limit", actor: services.Actor{UserID: "a1", Role: models.RoleAdmin}, hours: &two},
+		{name: "rejects staff of another location", actor: services.Actor{UserID: "s2", Role: models.RoleStaff}, hours: &two, wantCode: "LOCATION_FORBIDDEN"},
+		{name: "rejects a student", actor: services.Actor{UserID: "u1", Role: models.RoleStudent}, hours: &two, wantCode: "LOCATION_FORBIDDEN"},
+		{name: "rejects a non-positive limit", actor: services.Actor{UserID: "s1", Role: models.RoleStaff}, hours: &zero, wantCode: "VALIDATION_FAILED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+			f.store.AddUser(models.User{ID: "s2", Role: models.RoleStaff})
+			other := f.store.AddLocation(models.Location{Name: "FIB", Timezone: "Asia/Jakarta", OpenHour: 8, CloseHour: 22})
+			if err := f.store.Staff().Assign(ctx, "s2", other.ID); err != nil {
+				t.Fatal(err)
+			}
+			tv, err := f.store.TVs().FindByID(ctx, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			locations := services.NewLocationService(f.store)
+
+			_, err = locations.SetBookingPolicy(ctx, tt.actor, tv.LocationID, tt.hours)
+			if got := code(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			location, err := locations.Get(ctx, tv.LocationID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.hours
+			if tt.wantCode != "" {
+				want = nil
+			}
+			if (location.MaxConsecutiveHours == nil) != (want == nil) ||
+				(want != nil && *location.MaxConsecutiveHours != *want) {
+				t.Fatalf("maxConsecutiveHours = %v, want %v", location.MaxConsecutiveHours, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"strings"
+	"time"
+)
+
+// MaintenanceService mengelola laporan kerusakan dan status layanan TV.
+type MaintenanceService struct {
+	store repository.Store
+	Now   func() time.Time
+}
+
+func NewMaintenanceService(store repository.Store) *MaintenanceService {
+	return &MaintenanceService{store: store, Now: time.Now}
+}
+
+// IssueReport adalah isi laporan kerusakan dari mahasiswa.
+type IssueReport struct {
+	GameID      *int
+	Description string
+	PhotoURL    string
+}
+
+// CheckReport memvalidasi laporan sebelum fotonya disimpan, sehingga laporan
+// yang ditolak tidak meninggalkan file di direktori upload.
+func (s *MaintenanceService) CheckReport(ctx context.Context, tvID int, report IssueReport) error {
+	_, err := s.checkReport(ctx, tvID, report)
+	return err
+}
+
+// ReportIssue membuat tiket kerusakan baru berstatus open.
+func (s *MaintenanceService) ReportIssue(ctx context.Context, reporterID string, tvID int, report IssueReport) (*models.TVIssue, error) {
+	tv, err := s.checkReport(ctx, tvID, report)
+	if err != nil {
+		return nil, err
+	}
+
+	issue := &models.TVIssue{
+		TVID:        tv.ID,
+		GameID:      report.GameID,
+		ReporterID:  reporterID,
+		Description: strings.TrimSpace(report.Description),
+		PhotoURL:    report.PhotoURL,
+		Status:      models.IssueOpen,
+	}
+	if err := s.store.Issues().Create(ctx, issue); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// checkReport memastikan deskripsi terisi, TV ada, dan game yang dilaporkan
+// terpasang di TV tersebut.
+func (s *MaintenanceService) checkReport(ctx context.Context, tvID int, report IssueReport) (*models.TVInfo, error) {
+	description := strings.TrimSpace(report.Description)
+	if description == "" {
+		return nil, invalid("description", "Description is required")
+	}
+
+	tv, err := s.store.TVs().FindByID(ctx, tvID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTVNotFound
+		}
+		return nil, err
+	}
+
+	// Game yang dilaporkan harus terpasang di TV tersebut
+	if report.GameID != nil {
+		games, err := s.store.Games().ListByTV(ctx, tv.ID)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, g := range games {
+			if g.ID == *report.GameID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, invalid("gameId", "Game is not installed on this TV")
+		}
+	}
+	return tv, nil
+}
+
+// ListIssues mengembalikan tiket pada lokasi yang dikelola actor.
+func (s *MaintenanceService) ListIssues(ctx context.Context, actor Actor, status string, tvID *int) ([]models.TVIssue, error) {
+	locationIDs, err := managedLocationIDs(ctx, s.store.Staff(), actor)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.Issues().List(ctx, repository.IssueFilter{Status: status, TVID: tvID, LocationIDs: locationIDs})
+}
+
+// UpdateIssueStatus memindahkan tiket ke open, in_progress, atau resolved.
+func (s *MaintenanceService) UpdateIssueStatus(ctx context.Context, actor Actor, issueID uint, status, staffNote string) (*models.TVIssue, error) {
+	switch status {
+	case models.IssueOpen, models.IssueInProgress, models.IssueResolved:
+	default:
+		return nil, invalid("status", "Status must be open, in_progress or resolved")
+	}
+
+	issue, err := s.store.Issues().FindByID(ctx, issueID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrIssueNotFound
+		}
+		return nil, err
+	}
+
+	if _, err := s.authorizeTV(ctx, actor, issue.TVID); err != nil {
+		return nil, err
+	}
+
+	issue.Status = status
+	if staffNote != "" {
+		issue.StaffNote = staffNote
+	}
+	if status == models.IssueResolved {
+		now := s.Now()
+		issue.ResolvedAt = &now
+	} else {
+		issue.ResolvedAt = nil
+	}
+
+	if err := s.store.Issues().Update(ctx, issue); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// SetTVService menandai TV rusak atau kembali beroperasi. Saat TV ditandai rusak,
+// reservasi mendatang (termasuk slot yang sedang berjalan) dibatalkan, tawaran
+// waitlist pada TV tersebut ditarik, dan peminjam, pemain undangan, serta
+// penerima tawaran menerima notifikasi. Mengembalikan jumlah reservasi yang dibatalkan.
+func (s *MaintenanceService) SetTVService(ctx context.Context, actor Actor, tvID int, outOfService bool, reason string) (int, error) {
+	tv, err := s.authorizeTV(ctx, actor, tvID)
+	if err != nil {
+		return 0, err
+	}
+	if !outOfService {
+		reason = ""
+	}
+
+	cancelled := 0
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		// Kunci TV agar tidak ada pemesanan atau tawaran baru yang lolos di
+		// tengah pembatalan
+		if err := tx.TVs().Lock(ctx, tvID); err != nil {
+			return err
+		}
+		if err := tx.TVs().UpdateService(ctx, tvID, outOfService, reason); err != nil {
+			return err
+		}
+		if !outOfService {
+			return recordAvailability(ctx, tx, models.AvailabilityInService, *tv, "")
+		}
+		if err := recordAvailability(ctx, tx, models.AvailabilityOutOfService, *tv, ""); err != nil {
+			return err
+		}
+		suffix := ""
+		if reason != "" {
+			suffix = " Reason: " + reason
+		}
+
+		current := slotStart(locationOf(*tv), s.Now())
+		affected, err := tx.Reservations().ListBookedFrom(ctx, tvID, SlotKey(current))
+		if err != nil {
+			return err
+		}
+		for _, r := range affected {
+			if err := tx.Reservations().UpdateStatus(ctx, r.ID, models.ReservationCancelled); err != nil {
+				return err
+			}
+
+			msg := fmt.Sprintf("Your reservation for TV %d at %s was cancelled because the TV is out of service.", tvID, r.TimeSlot)
+			if err := tx.Notifications().Create(ctx, &models.Notification{UserID: r.BorrowerID, Message: msg + suffix}); err != nil {
+				return err
+			}
+			msg = fmt.Sprintf("The session on TV %d at %s you were invited to was cancelled because the TV is out of service.", tvID, r.TimeSlot)
+			if err := notifyParticipants(ctx, tx, r.ID, msg+suffix); err != nil {
+				return err
+			}
+		}
+
+		// Tawaran hanya ada untuk slot yang belum berakhir, jauh di bawah batas satu tahun ini
+		offers, err := tx.Waitlist().ListOffers(ctx, tvID, SlotKey(current), SlotKey(current.AddDate(1, 0, 0)))
+		if err != nil {
+			return err
+		}
+		for _, e := range offers {
+			e.Status = models.WaitlistExpired
+			if err := tx.Waitlist().Update(ctx, &e); err != nil {
+				return err
+			}
+			msg := fmt.Sprintf("Your waitlist offer for TV %d at %s was withdrawn because the TV is out of service.", tvID, e.TimeSlot)
+			if err := tx.Notifications().Create(ctx, &models.Notification{UserID: e.UserID, Message: msg + suffix}); err != nil {
+				return err
+			}
+		}
+		cancelled = len(affected)
+		return nil
+	})
+	return cancelled, err
+}
+
+// authorizeTV memastikan TV ada dan lokasinya dikelola oleh actor.
+func (s *MaintenanceService) authorizeTV(ctx context.Context, actor Actor, tvID int) (*models.TVInfo, error) {
+	tv, err := s.store.TVs().FindByID(ctx, tvID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTVNotFound
+		}
+		return nil, err
+	}
+
+	ok, err := canManage(ctx, s.store.Staff(), actor, tv.LocationID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLocationForbidden
+	}
+	return tv, nil
+}
@@ -0,0 +1,109 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+)
+
+// outOfServiceNotices menghitung notifikasi userID yang menyebut TV rusak.
+func outOfServiceNotices(t *testing.T, f *fixture, userID string) int {
+	t.Helper()
+	list, err := f.store.Notifications().ListByUser(context.Background(), userID, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for _, notification := range list {
+		if strings.Contains(notification.Message, "out of service") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSetTVService(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	maintenance := services.NewMaintenanceService(f.store)
+	maintenance.Now = func() time.Time { return f.now }
+
+	// u1 mengundang u2 ke slot 12:00; slot 13:00 sedang ditawarkan ke u2 dari waitlist
+	res := f.book(t, "u1", 1, f.slot(0, 12))
+	if _, err := f.svc.InviteParticipant(ctx, "u1", res.ID, "u2"); err != nil {
+		t.Fatal(err)
+	}
+	held := f.book(t, "u3", 1, f.slot(0, 13))
+	entry, err := f.svc.JoinWaitlist(ctx, "u2", 1, f.slot(0, 13), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.CancelReservation(ctx, services.Actor{UserID: "u3", Role: models.RoleStudent}, held.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	cancelled, err := maintenance.SetTVService(ctx, f.staff, 1, true, "Broken HDMI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancelled != 1 {
+		t.Fatalf("cancelled = %d, want 1", cancelled)
+	}
+
+	got, err := f.store.Waitlist().FindByID(ctx, entry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != models.WaitlistExpired {
+		t.Fatalf("offer status = %s, want %s", got.Status, models.WaitlistExpired)
+	}
+	if _, err := f.svc.AcceptOffer(ctx, "u2", entry.ID); code(err) != "OFFER_NOT_ACTIVE" {
+		t.Fatalf("withdrawn offer accepted: %v", err)
+	}
+	// u1 sebagai peminjam; u2 sebagai pemain undangan dan penerima tawaran
+	if n := outOfServiceNotices(t, f, "u1"); n != 1 {
+		t.Fatalf("u1 received %d out-of-service notices, want 1", n)
+	}
+	if n := outOfServiceNotices(t, f, "u2"); n != 2 {
+		t.Fatalf("u2 received %d out-of-service notices, want 2", n)
+	}
+}
+
+func TestSetTVServiceHalfHourTimezone(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	loc := f.store.AddLocation(models.Location{Name: "Bengaluru", Timezone: "Asia/Kolkata", OpenHour: 8, CloseHour: 22})
+	f.store.AddTV(models.TVInfo{ID: 3, ConsoleType: "PS5", LocationID: loc.ID})
+	if err := f.store.Staff().Assign(ctx, "s1", loc.ID); err != nil {
+		t.Fatal(err)
+	}
+	ist, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	maintenance := services.NewMaintenanceService(f.store)
+	maintenance.Now = func() time.Time { return f.now }
+
+	// Slot 10:00 IST (04:30 UTC) masih berjalan pada 10:50 IST meski jam penuh UTC-nya sudah 05:00
+	f.now = time.Date(2026, time.March, 2, 10, 50, 0, 0, ist)
+	res := f.book(t, "u1", 3, "2026-03-02T04:30:00Z")
+
+	cancelled, err := maintenance.SetTVService(ctx, f.staff, 3, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancelled != 1 {
+		t.Fatalf("cancelled = %d, want 1", cancelled)
+	}
+	got, err := f.store.Reservations().FindByID(ctx, res.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != models.ReservationCancelled {
+		t.Fatalf("reservation status = %s, want %s", got.Status, models.ReservationCancelled)
+	}
+}
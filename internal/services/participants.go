@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"slices"
+	"time"
+)
+
+// MaxCoPlayers adalah jumlah pemain undangan per reservasi, sehingga satu sesi
+// berisi paling banyak empat pemain termasuk peminjam.
+const MaxCoPlayers = 3
+
+// InviteParticipant mengundang userID sebagai pemain pada reservasi booked
+// milik borrowerID. Undangan yang pernah ditolak dapat dikirim ulang.
+func (s *BookingService) InviteParticipant(ctx context.Context, borrowerID string, reservationID uint, userID string) (*models.ReservationParticipant, error) {
+	res, err := s.findReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if res.BorrowerID != borrowerID {
+		return nil, ErrReservationNotFound
+	}
+	if err := s.checkOpenForPlayers(*res); err != nil {
+		return nil, err
+	}
+	if userID == borrowerID {
+		return nil, invalid("userId", "You cannot invite yourself")
+	}
+	if _, err := s.store.Users().FindByID(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	participants, err := s.store.Participants().ListByReservation(ctx, res.ID)
+	if err != nil {
+		return nil, err
+	}
+	var previous *models.ReservationParticipant
+	players := 0
+	for i, p := range participants {
+		if p.UserID == userID {
+			if p.Status != models.ParticipantDeclined {
+				return nil, ErrAlreadyInvited
+			}
+			previous = &participants[i]
+			continue
+		}
+		if p.Status != models.ParticipantDeclined {
+			players++
+		}
+	}
+	if players >= MaxCoPlayers {
+		return nil, ErrTooManyPlayers
+	}
+
+	participant := &models.ReservationParticipant{
+		ReservationID: res.ID,
+		UserID:        userID,
+		Status:        models.ParticipantInvited,
+	}
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		if previous != nil {
+			participant = previous
+			participant.Status = models.ParticipantInvited
+			participant.RespondedAt = nil
+			if err := tx.Participants().Update(ctx, participant); err != nil {
+				return err
+			}
+		} else if err := tx.Participants().Create(ctx, participant); err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("%s invited you to play on TV %d at %s.", borrowerID, res.TVID, res.TimeSlot)
+		return tx.Notifications().Create(ctx, &models.Notification{UserID: userID, Message: msg})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return participant, nil
+}
+
+// Participants mengembalikan pemain undangan sebuah reservasi. Hanya peminjam,
+// pemain undangan, dan staff lokasi TV (atau admin) yang dapat melihatnya.
+func (s *BookingService) Participants(ctx context.Context, actor Actor, reservationID uint) ([]models.ReservationParticipant, error) {
+	res, err := s.findReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	participants, err := s.store.Participants().ListByReservation(ctx, res.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	invited := slices.ContainsFunc(participants, func(p models.ReservationParticipant) bool { return p.UserID == actor.UserID })
+	if res.BorrowerID != actor.UserID && !invited {
+		tv, err := s.store.TVs().FindByID(ctx, res.TVID)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := canManage(ctx, s.store.Staff(), actor, tv.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrReservationNotFound
+		}
+	}
+	return participants, nil
+}
+
+// Invitations mengembalikan undangan userID yang belum dijawab.
+func (s *BookingService) Invitations(ctx context.Context, userID string) ([]models.ReservationParticipant, error) {
+	return s.store.Participants().ListInvitations(ctx, userID)
+}
+
+// RespondInvitation menerima atau menolak undangan milik userID. Undangan hanya
+// dapat dijawab selama reservasinya masih booked.
+func (s *BookingService) RespondInvitation(ctx context.Context, userID string, invitationID uint, accept bool) (*models.ReservationParticipant, error) {
+	participant, err := s.store.Participants().FindByID(ctx, invitationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	if participant.UserID != userID {
+		return nil, ErrInvitationNotFound
+	}
+	if participant.Status != models.ParticipantInvited {
+		return nil, ErrInvitationClosed
+	}
+
+	res, err := s.findReservation(ctx, participant.ReservationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkOpenForPlayers(*res); err != nil {
+		return nil, err
+	}
+
+	now := s.Now()
+	participant.Status = models.ParticipantDeclined
+	answer := "declined"
+	if accept {
+		participant.Status = models.ParticipantAccepted
+		answer = "accepted"
+	}
+	participant.RespondedAt = &now
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Participants().Update(ctx, participant); err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("%s %s your invitation to play on TV %d at %s.", userID, answer, res.TVID, res.TimeSlot)
+		return tx.Notifications().Create(ctx, &models.Notification{UserID: res.BorrowerID, Message: msg})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return participant, nil
+}
+
+// RemoveParticipant menghapus undangan userID dari reservasi. Peminjam dapat
+// menghapus siapa pun, sedangkan pemain undangan hanya dirinya sendiri.
+func (s *BookingService) RemoveParticipant(ctx context.Context, actorID string, reservationID uint, userID string) error {
+	res, err := s.findReservation(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+	if res.BorrowerID != actorID && userID != actorID {
+		return ErrReservationNotFound
+	}
+	if res.Status != models.ReservationBooked {
+		return ErrReservationNotBooked
+	}
+
+	participants, err := s.store.Participants().ListByReservation(ctx, res.ID)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(participants, func(p models.ReservationParticipant) bool { return p.UserID == userID })
+	if i < 0 {
+		return ErrInvitationNotFound
+	}
+	return s.store.Participants().Delete(ctx, participants[i].ID)
+}
+
+// recordAttendance dipanggil di dalam transaksi check-in. attendees adalah
+// pemain undangan yang hadir; nil berarti semua undangan yang sudah diterima.
+// Pemain yang hadir tetapi belum menjawab undangan dianggap menerimanya.
+func recordAttendance(ctx context.Context, tx repository.Store, res models.Reservation, attendees *[]string, now time.Time) ([]models.ReservationParticipant, error) {
+	participants, err := tx.Participants().ListByReservation(ctx, res.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if attendees != nil {
+		for _, id := range *attendees {
+			if id == res.BorrowerID {
+				continue
+			}
+			invited := slices.ContainsFunc(participants, func(p models.ReservationParticipant) bool {
+				return p.UserID == id && p.Status != models.ParticipantDeclined
+			})
+			if !invited {
+				return nil, invalid("attendees", id+" is not invited to this reservation")
+			}
+		}
+	}
+
+	for i := range participants {
+		p := &participants[i]
+		if p.Status == models.ParticipantDeclined {
+			continue
+		}
+		attended := p.Status == models.ParticipantAccepted
+		if attendees != nil {
+			attended = slices.Contains(*attendees, p.UserID)
+		}
+		if attended && p.Status == models.ParticipantInvited {
+			p.Status = models.ParticipantAccepted
+			p.RespondedAt = &now
+		}
+		p.Attended = &attended
+		if err := tx.Participants().Update(ctx, p); err != nil {
+			return nil, err
+		}
+	}
+	return participants, nil
+}
+
+// notifyParticipants mengirim msg ke pemain undangan reservasi yang belum menolak.
+func notifyParticipants(ctx context.Context, tx repository.Store, reservationID uint, msg string) error {
+	participants, err := tx.Participants().ListByReservation(ctx, reservationID)
+	if err != nil {
+		return err
+	}
+	for _, p := range participants {
+		if p.Status == models.ParticipantDeclined {
+			continue
+		}
+		if err := tx.Notifications().Create(ctx, &models.Notification{UserID: p.UserID, Message: msg}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkOpenForPlayers memastikan daftar pemain reservasi masih dapat diubah:
+// reservasi masih booked dan slotnya belum berakhir.
+func (s *BookingService) checkOpenForPlayers(res models.Reservation) error {
+	if res.Status != models.ReservationBooked {
+		return ErrReservationNotBooked
+	}
+	_, end, err := sessionBounds(res)
+	if err != nil {
+		return err
+	}
+	if !s.Now().Before(end) {
+		return ErrReservationNotBooked
+	}
+	return nil
+}
+
+// findReservation mencari reservasi berdasarkan ID.
+func (s *BookingService) findReservation(ctx context.Context, id uint) (*models.Reservation, error) {
+	res, err := s.store.Reservations().FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrReservationNotFound
+		}
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,162 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"playcorner-be/internal/models"
+)
+
+func TestInviteParticipant(t *testing.T) {
+	tests := []struct {
+		name string
+		// invite adalah undangan yang dikirim u1 sebelum undangan yang diuji.
+		invite   []string
+		decline  bool
+		userID   string
+		wantCode string
+	}{
+		{name: "invites another user", userID: "u2"},
+		{name: "rejects inviting yourself", userID: "u1", wantCode: "VALIDATION_FAILED"},
+		{name: "rejects an unknown user", userID: "nobody", wantCode: "USER_NOT_FOUND"},
+		{name: "rejects a second invitation", invite: []string{"u2"}, userID: "u2", wantCode: "ALREADY_INVITED"},
+		{name: "re-invites after a decline", invite: []string{"u2"}, decline: true, userID: "u2"},
+		{name: "rejects a fourth player", invite: []string{"u2", "u3", "u4"}, userID: "u5", wantCode: "TOO_MANY_PLAYERS"},
+		{name: "does not count declined players", invite: []string{"u2", "u3", "u4"}, decline: true, userID: "u5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+			f.store.AddUser(models.User{ID: "u4"})
+			f.store.AddUser(models.User{ID: "u5"})
+			res := f.book(t, "u1", 1, f.slot(0, 12))
+
+			for _, userID := range tt.invite {
+				p, err := f.svc.InviteParticipant(ctx, "u1", res.ID, userID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// Hanya undangan pertama yang ditolak
+				if tt.decline && userID == tt.invite[0] {
+					if _, err := f.svc.RespondInvitation(ctx, userID, p.ID, false); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			p, err := f.svc.InviteParticipant(ctx, "u1", res.ID, tt.userID)
+			if got := code(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			if err == nil && p.Status != models.ParticipantInvited {
+				t.Fatalf("participant status = %s, want invited", p.Status)
+			}
+		})
+	}
+}
+
+func TestRespondInvitation(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	res := f.book(t, "u1", 1, f.slot(0, 12))
+	p, err := f.svc.InviteParticipant(ctx, "u1", res.ID, "u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.svc.RespondInvitation(ctx, "u3", p.ID, true); code(err) != "INVITATION_NOT_FOUND" {
+		t.Fatalf("another user answered the invitation: %v", err)
+	}
+	got, err := f.svc.RespondInvitation(ctx, "u2", p.ID, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != models.ParticipantAccepted || got.RespondedAt == nil {
+		t.Fatalf("participant = %+v, want accepted", got)
+	}
+	if _, err := f.svc.RespondInvitation(ctx, "u2", p.ID, false); code(err) != "INVITATION_CLOSED" {
+		t.Fatalf("invitation answered twice: %v", err)
+	}
+}
+
+func TestCheckInRecordsAttendance(t *testing.T) {
+	tests := []struct {
+		name      string
+		attendees *[]string
+		// want adalah status kehadiran per pemain; pemain yang menolak tidak dicatat.
+		want     map[string]bool
+		wantCode string
+	}{
+		{
+			name: "assumes accepted players attended by default",
+			want: map[string]bool{"u2": true, "u3": false},
+		},
+		{
+			name:      "records the listed attendees",
+			attendees: &[]string{"u1", "u3"},
+			want:      map[string]bool{"u2": false, "u3": true},
+		},
+		{
+			name:      "rejects an attendee who was not invited",
+			attendees: &[]string{"u4"},
+			wantCode:  "VALIDATION_FAILED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+			f.store.AddUser(models.User{ID: "u4"})
+			res := f.book(t, "u1", 1, f.slot(0, 12))
+			// u2 menerima, u3 belum menjawab, u4 menolak
+			for _, userID := range []string{"u2", "u3", "u4"} {
+				p, err := f.svc.InviteParticipant(ctx, "u1", res.ID, userID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if userID == "u3" {
+					continue
+				}
+				if _, err := f.svc.RespondInvitation(ctx, userID, p.ID, userID == "u2"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			f.at(0, 12, 0)
+
+			_, participants, err := f.svc.CheckIn(ctx, f.staff, res.ID, tt.attendees)
+			if got := code(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			if err != nil {
+				// Check-in yang gagal tidak mengubah reservasi
+				stored, err := f.store.Reservations().FindByID(ctx, res.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if stored.Status != models.ReservationBooked {
+					t.Fatalf("reservation = %s after a failed check-in, want booked", stored.Status)
+				}
+				return
+			}
+
+			for _, p := range participants {
+				want, tracked := tt.want[p.UserID]
+				if !tracked {
+					if p.Attended != nil {
+						t.Fatalf("%s declined but attendance was recorded", p.UserID)
+					}
+					continue
+				}
+				if p.Attended == nil || *p.Attended != want {
+					t.Fatalf("%s attended = %v, want %v", p.UserID, p.Attended, want)
+				}
+				if want && p.Status != models.ParticipantAccepted {
+					t.Fatalf("%s attended but status is %s", p.UserID, p.Status)
+				}
+			}
+		})
+	}
+}
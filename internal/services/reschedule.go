@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+)
+
+// RescheduleReservation memindahkan reservasi booked ke slot dan/atau TV lain
+// secara atomik. Aturan ketersediaan, jendela booking, dan kuota sama seperti
+// saat membuat reservasi; jika slot tujuan terpakai, reservasi tetap di slot
+// semula. Slot semula dilepas (dan ditawarkan ke waitlist) setelah pindah.
+func (s *BookingService) RescheduleReservation(ctx context.Context, actor Actor, reservationID uint, body models.RescheduleBody) (*models.Reservation, error) {
+	if body.TVID == 0 && body.Timeslot == "" {
+		return nil, invalid("timeslot", "Provide a new timeslot and/or tvId")
+	}
+
+	res, err := s.findReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	from, err := s.store.TVs().FindByID(ctx, res.TVID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTVNotFound
+		}
+		return nil, err
+	}
+	if res.BorrowerID != actor.UserID {
+		ok, err := canManage(ctx, s.store.Staff(), actor, from.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		// Reservasi orang lain disembunyikan dari mahasiswa
+		if !ok {
+			return nil, ErrReservationNotFound
+		}
+	}
+
+	if res.Status != models.ReservationBooked {
+		return nil, ErrReservationNotBooked
+	}
+	if res.SeriesID != nil {
+		return nil, ErrSeriesOccurrence
+	}
+	start, _, err := sessionBounds(*res)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Now().Before(start) {
+		return nil, ErrRescheduleClosed
+	}
+
+	to := from
+	if body.TVID != 0 && body.TVID != res.TVID {
+		if to, err = s.store.TVs().FindByID(ctx, body.TVID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrTVNotFound
+			}
+			return nil, err
+		}
+	}
+	if to.OutOfService {
+		return nil, ErrTVOutOfService
+	}
+
+	// Slot lama dinyatakan ulang di zona waktu lokasi TV tujuan jika hanya TV yang berubah
+	timeslot := body.Timeslot
+	if timeslot == "" {
+		timeslot = res.TimeSlot
+	}
+	slot, err := normalizeSlot(timeslot, locationOf(*to))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkWindow(slot); err != nil {
+		return nil, err
+	}
+	if to.ID == res.TVID && slot == res.TimeSlot {
+		return nil, invalid("timeslot", "Reservation is already at this TV and timeslot")
+	}
+
+	change := &models.ReservationChange{
+		ReservationID: res.ID,
+		ChangedBy:     actor.UserID,
+		FromTVID:      res.TVID,
+		FromTimeSlot:  res.TimeSlot,
+		ToTVID:        to.ID,
+		ToTimeSlot:    slot,
+	}
+	moved := *res
+	moved.TVID, moved.TimeSlot = to.ID, slot
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := s.checkQuota(ctx, tx, res.BorrowerID, []plannedSlot{{tv: *to, slot: slot}}, res.ID); err != nil {
+			return err
+		}
+		// Kunci TV asal dan tujuan agar pemesanan paralel tidak lolos pemeriksaan slot bersamaan
+		if err := tx.TVs().Lock(ctx, res.TVID, to.ID); err != nil {
+			return err
+		}
+		// Cek apakah slot tujuan sudah dipesan atau sedang ditawarkan ke antrean waitlist
+		free, err := s.slotFree(ctx, tx, *to, slot)
+		if err != nil {
+			return err
+		}
+		if !free {
+			return ErrSlotTaken
+		}
+
+		if err := tx.Reservations().Update(ctx, &moved); err != nil {
+			return slotConflict(err)
+		}
+		if err := tx.ReservationChanges().Create(ctx, change); err != nil {
+			return err
+		}
+		if err := recordAvailability(ctx, tx, models.AvailabilityBooked, *to, slot); err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("The session on TV %d at %s has been moved to TV %d at %s.", change.FromTVID, change.FromTimeSlot, to.ID, slot)
+		if err := notifyParticipants(ctx, tx, res.ID, msg); err != nil {
+			return err
+		}
+		if actor.UserID != res.BorrowerID {
+			if err := tx.Notifications().Create(ctx, &models.Notification{UserID: res.BorrowerID, Message: msg}); err != nil {
+				return err
+			}
+		}
+		return s.releaseSlot(ctx, tx, *from, change.FromTimeSlot)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &moved, nil
+}
+
+// ReservationChanges mengembalikan riwayat perpindahan reservasi untuk
+// peminjamnya atau staff lokasi TV (atau admin).
+func (s *BookingService) ReservationChanges(ctx context.Context, actor Actor, reservationID uint) ([]models.ReservationChange, error) {
+	res, err := s.findReservation(ctx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if res.BorrowerID != actor.UserID {
+		tv, err := s.store.TVs().FindByID(ctx, res.TVID)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := canManage(ctx, s.store.Staff(), actor, tv.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrReservationNotFound
+		}
+	}
+	return s.store.ReservationChanges().ListByReservation(ctx, res.ID)
+}
@@ -0,0 +1,120 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+)
+
+func TestRescheduleReservation(t *testing.T) {
+	tests := []struct {
+		name string
+		// hour adalah jam reservasi u1 di TV 1 yang dipindahkan.
+		hour     int
+		setup    func(t *testing.T, f *fixture)
+		body     func(f *fixture) models.RescheduleBody
+		wantCode string
+		wantTV   int
+		wantHour int
+	}{
+		{
+			name:     "moves to another hour",
+			hour:     12,
+			body:     func(f *fixture) models.RescheduleBody { return models.RescheduleBody{Timeslot: f.slot(0, 14)} },
+			wantTV:   1,
+			wantHour: 14,
+		},
+		{
+			name:     "moves to another TV",
+			hour:     12,
+			body:     func(f *fixture) models.RescheduleBody { return models.RescheduleBody{TVID: 2} },
+			wantTV:   2,
+			wantHour: 12,
+		},
+		{
+			name: "keeps the reservation when the target is taken",
+			hour: 12,
+			setup: func(t *testing.T, f *fixture) {
+				f.book(t, "u2", 1, f.slot(0, 14))
+			},
+			body:     func(f *fixture) models.RescheduleBody { return models.RescheduleBody{Timeslot: f.slot(0, 14)} },
+			wantCode: "SLOT_TAKEN",
+			wantTV:   1,
+			wantHour: 12,
+		},
+		{
+			name: "does not count the moved reservation against the quota",
+			hour: 12,
+			setup: func(t *testing.T, f *fixture) {
+				for _, hour := range []int{13, 14, 15} {
+					f.book(t, "u1", 1, f.slot(0, hour))
+				}
+			},
+			body:     func(f *fixture) models.RescheduleBody { return models.RescheduleBody{Timeslot: f.slot(0, 16)} },
+			wantTV:   1,
+			wantHour: 16,
+		},
+		{
+			name:     "rejects the same TV and slot",
+			hour:     12,
+			body:     func(f *fixture) models.RescheduleBody { return models.RescheduleBody{Timeslot: f.slot(0, 12)} },
+			wantCode: "VALIDATION_FAILED",
+			wantTV:   1,
+			wantHour: 12,
+		},
+		{
+			name:     "rejects a slot beyond the booking window",
+			hour:     12,
+			body:     func(f *fixture) models.RescheduleBody { return models.RescheduleBody{Timeslot: f.slot(8, 12)} },
+			wantCode: "VALIDATION_FAILED",
+			wantTV:   1,
+			wantHour: 12,
+		},
+		{
+			name:     "rejects a slot that has already started",
+			hour:     9,
+			body:     func(f *fixture) models.RescheduleBody { return models.RescheduleBody{Timeslot: f.slot(0, 14)} },
+			wantCode: "RESCHEDULE_CLOSED",
+			wantTV:   1,
+			wantHour: 9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+			res := f.book(t, "u1", 1, f.slot(0, tt.hour))
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+
+			_, err := f.svc.RescheduleReservation(ctx, services.Actor{UserID: "u1", Role: models.RoleStudent}, res.ID, tt.body(f))
+			if got := code(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			got, err := f.store.Reservations().FindByID(ctx, res.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.TVID != tt.wantTV || got.TimeSlot != f.slot(0, tt.wantHour) {
+				t.Fatalf("reservation at TV %d %s, want TV %d %s", got.TVID, got.TimeSlot, tt.wantTV, f.slot(0, tt.wantHour))
+			}
+
+			changes, err := f.svc.ReservationChanges(ctx, f.staff, res.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			moved := tt.wantCode == ""
+			if moved != (len(changes) == 1) {
+				t.Fatalf("got %d recorded changes, moved = %v", len(changes), moved)
+			}
+			// Slot lama harus kembali tersedia setelah dipindahkan
+			if _, err := f.store.Reservations().FindActiveBySlot(ctx, 1, f.slot(0, tt.hour)); moved && err == nil {
+				t.Fatal("original slot is still held")
+			}
+		})
+	}
+}
@@ -0,0 +1,594 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"slices"
+	"time"
+)
+
+// maxSeriesSpan membatasi rentang StartDate sampai EndDate sebuah series.
+const maxSeriesSpan = 366 * 24 * time.Hour
+
+// SeriesDetail adalah series beserta kejadian yang sudah menjadi reservasi dan
+// slot mendatang yang bentrok dengan booking lain.
+type SeriesDetail struct {
+	Series      *models.ReservationSeries `json:"series"`
+	Conflicts   []models.SeriesConflict   `json:"conflicts"`
+	Occurrences []models.SeriesOccurrence `json:"occurrences"`
+}
+
+// CreateSeries mengajukan booking berulang atas nama actor. Series dibuat
+// berstatus pending dan belum memblokir slot apa pun; konflik yang ditemukan
+// dikembalikan agar dapat ditinjau sebelum staff menyetujuinya.
+func (s *BookingService) CreateSeries(ctx context.Context, actor Actor, body models.SeriesBody) (*models.ReservationSeries, []models.SeriesConflict, error) {
+	series := &models.ReservationSeries{
+		OrganizerID:    actor.UserID,
+		Title:          body.Title,
+		TVIDs:          unique(body.TVIDs),
+		Frequency:      body.Frequency,
+		StartDate:      body.StartDate,
+		EndDate:        body.EndDate,
+		StartHour:      body.StartHour,
+		EndHour:        body.EndHour,
+		ExceptionDates: unique(body.ExceptionDates),
+		Overrides:      []models.SeriesOverride{},
+		Status:         models.SeriesPending,
+	}
+	tvs, err := s.validateSeries(ctx, series)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.store.Series().Create(ctx, series); err != nil {
+		return nil, nil, err
+	}
+
+	_, _, conflicts, err := s.planSeries(ctx, s.store, series, tvs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return series, conflicts, nil
+}
+
+// ListSeries mengembalikan series di lokasi yang dikelola staff (semua lokasi
+// untuk admin), atau series yang diajukan sendiri untuk mahasiswa.
+func (s *BookingService) ListSeries(ctx context.Context, actor Actor, status string) ([]models.ReservationSeries, error) {
+	filter := repository.SeriesFilter{Status: status}
+	if actor.Role == models.RoleStaff || actor.Role == models.RoleAdmin {
+		ids, err := managedLocationIDs(ctx, s.store.Staff(), actor)
+		if err != nil {
+			return nil, err
+		}
+		filter.LocationIDs = ids
+	} else {
+		filter.OrganizerID = actor.UserID
+	}
+	return s.store.Series().List(ctx, filter)
+}
+
+// GetSeries mengembalikan detail series untuk penyelenggaranya atau staff
+// lokasinya. Konflik hanya dihitung untuk series yang masih pending atau approved.
+func (s *BookingService) GetSeries(ctx context.Context, actor Actor, id uint) (*SeriesDetail, error) {
+	series, _, err := s.findSeries(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &SeriesDetail{Series: series, Conflicts: []models.SeriesConflict{}}
+	if series.Status == models.SeriesPending || series.Status == models.SeriesApproved {
+		tvs, err := s.seriesTVs(ctx, series)
+		if err != nil {
+			return nil, err
+		}
+		_, _, detail.Conflicts, err = s.planSeries(ctx, s.store, series, tvs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reservations, err := s.store.Reservations().ListBySeries(ctx, series.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	detail.Occurrences = make([]models.SeriesOccurrence, 0, len(reservations))
+	for _, r := range reservations {
+		detail.Occurrences = append(detail.Occurrences, models.SeriesOccurrence{
+			ReservationID: r.ID,
+			TVID:          r.TVID,
+			TimeSlot:      r.TimeSlot,
+			Status:        r.Status,
+		})
+	}
+	return detail, nil
+}
+
+// ApproveSeries menyetujui series pending dan menjabarkan kejadian mendatangnya
+// menjadi reservasi. Jika ada konflik, persetujuan ditolak dengan
+// ErrSeriesConflict kecuali skipConflicts, yang melewati slot yang bentrok.
+func (s *BookingService) ApproveSeries(ctx context.Context, actor Actor, id uint, skipConflicts bool) (*models.ReservationSeries, []models.SeriesConflict, error) {
+	series, manager, err := s.findSeries(ctx, actor, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !manager {
+		return nil, nil, ErrLocationForbidden
+	}
+	if series.Status != models.SeriesPending {
+		return nil, nil, ErrSeriesNotPending
+	}
+	// Jam buka lokasi dapat berubah sejak series diajukan
+	tvs, err := s.validateSeries(ctx, series)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := s.Now()
+	series.Status = models.SeriesApproved
+	series.ReviewedBy = &actor.UserID
+	series.ReviewedAt = &now
+
+	var conflicts []models.SeriesConflict
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		conflicts, err = s.syncSeries(ctx, tx, series, tvs, skipConflicts)
+		if err != nil {
+			return err
+		}
+		if err := tx.Series().Update(ctx, series); err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("Your recurring booking %q has been approved.", series.Title)
+		return tx.Notifications().Create(ctx, &models.Notification{UserID: series.OrganizerID, Message: msg})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return series, conflicts, nil
+}
+
+// RejectSeries menolak series pending.
+func (s *BookingService) RejectSeries(ctx context.Context, actor Actor, id uint) (*models.ReservationSeries, error) {
+	series, manager, err := s.findSeries(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if !manager {
+		return nil, ErrLocationForbidden
+	}
+	if series.Status != models.SeriesPending {
+		return nil, ErrSeriesNotPending
+	}
+
+	now := s.Now()
+	series.Status = models.SeriesRejected
+	series.ReviewedBy = &actor.UserID
+	series.ReviewedAt = &now
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Series().Update(ctx, series); err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("Your recurring booking %q has been rejected.", series.Title)
+		return tx.Notifications().Create(ctx, &models.Notification{UserID: series.OrganizerID, Message: msg})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// UpdateSeries mengubah aturan seluruh series. Penyelenggara hanya dapat
+// mengubah series yang masih pending; series approved hanya dapat diubah staff
+// lokasinya dan kejadian mendatangnya langsung disesuaikan.
+func (s *BookingService) UpdateSeries(ctx context.Context, actor Actor, id uint, body models.SeriesUpdateBody) (*models.ReservationSeries, []models.SeriesConflict, error) {
+	series, err := s.editableSeries(ctx, actor, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if body.Title != nil {
+		series.Title = *body.Title
+	}
+	if body.TVIDs != nil {
+		series.TVIDs = unique(*body.TVIDs)
+	}
+	if body.EndDate != nil {
+		series.EndDate = *body.EndDate
+	}
+	if body.StartHour != nil {
+		series.StartHour = *body.StartHour
+	}
+	if body.EndHour != nil {
+		series.EndHour = *body.EndHour
+	}
+	if body.ExceptionDates != nil {
+		series.ExceptionDates = unique(*body.ExceptionDates)
+	}
+
+	conflicts, err := s.saveSeries(ctx, series, body.SkipConflicts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return series, conflicts, nil
+}
+
+// UpdateOccurrence mengubah satu kejadian series pada tanggal date (zona waktu
+// lokasi): membatalkannya sebagai tanggal pengecualian, atau mengganti jamnya.
+func (s *BookingService) UpdateOccurrence(ctx context.Context, actor Actor, id uint, date string, body models.OccurrenceBody) (*models.ReservationSeries, []models.SeriesConflict, error) {
+	series, err := s.editableSeries(ctx, actor, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !slices.Contains(cadenceDates(series), date) {
+		return nil, nil, invalid("date", "Date is not an occurrence of this series")
+	}
+
+	series.Overrides = slices.DeleteFunc(series.Overrides, func(o models.SeriesOverride) bool { return o.Date == date })
+	if body.Cancelled {
+		if !slices.Contains(series.ExceptionDates, date) {
+			series.ExceptionDates = append(series.ExceptionDates, date)
+		}
+	} else {
+		series.ExceptionDates = slices.DeleteFunc(series.ExceptionDates, func(d string) bool { return d == date })
+		if *body.StartHour != series.StartHour || *body.EndHour != series.EndHour {
+			series.Overrides = append(series.Overrides, models.SeriesOverride{
+				Date:      date,
+				StartHour: *body.StartHour,
+				EndHour:   *body.EndHour,
+			})
+		}
+	}
+
+	conflicts, err := s.saveSeries(ctx, series, body.SkipConflicts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return series, conflicts, nil
+}
+
+// CancelSeries membatalkan series beserta kejadian mendatangnya yang belum
+// check-in. Slot yang dilepas ditawarkan ke antrean waitlist.
+func (s *BookingService) CancelSeries(ctx context.Context, actor Actor, id uint) (*models.ReservationSeries, error) {
+	series, err := s.editableSeries(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	series.Status = models.SeriesCancelled
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		occurrences, err := tx.Reservations().ListBySeries(ctx, series.ID, SlotKey(s.Now()))
+		if err != nil {
+			return err
+		}
+		for _, r := range occurrences {
+			if err := s.cancelOccurrence(ctx, tx, r); err != nil {
+				return err
+			}
+		}
+		return tx.Series().Update(ctx, series)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// saveSeries memvalidasi dan menyimpan perubahan series. Series approved
+// langsung disinkronkan dengan reservasinya dalam transaksi yang sama.
+func (s *BookingService) saveSeries(ctx context.Context, series *models.ReservationSeries, skipConflicts bool) ([]models.SeriesConflict, error) {
+	locationID := series.LocationID
+	tvs, err := s.validateSeries(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+	// Persetujuan staff berlaku untuk satu lokasi, jadi series tidak boleh pindah lokasi
+	if series.LocationID != locationID {
+		return nil, invalid("tvIds", "TVs must be at the series location")
+	}
+
+	if series.Status != models.SeriesApproved {
+		if err := s.store.Series().Update(ctx, series); err != nil {
+			return nil, err
+		}
+		_, _, conflicts, err := s.planSeries(ctx, s.store, series, tvs)
+		return conflicts, err
+	}
+
+	var conflicts []models.SeriesConflict
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		conflicts, err = s.syncSeries(ctx, tx, series, tvs, skipConflicts)
+		if err != nil {
+			return err
+		}
+		return tx.Series().Update(ctx, series)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+// syncSeries menyamakan reservasi mendatang series dengan aturannya: kejadian
+// yang tidak lagi diinginkan dibatalkan dan yang belum ada dibuat. Slot yang
+// bentrok menggagalkan sinkronisasi dengan ErrSeriesConflict kecuali
+// skipConflicts; slot yang dilewati dicoba lagi pada sinkronisasi berikutnya.
+func (s *BookingService) syncSeries(ctx context.Context, tx repository.Store, series *models.ReservationSeries, tvs []models.TVInfo, skipConflicts bool) ([]models.SeriesConflict, error) {
+	create, cancel, conflicts, err := s.planSeries(ctx, tx, series, tvs)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 && !skipConflicts {
+		return nil, ErrSeriesConflict
+	}
+
+	for _, r := range cancel {
+		if err := s.cancelOccurrence(ctx, tx, r); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range create {
+		reservation := &models.Reservation{
+			TVID:       c.tv.ID,
+			BorrowerID: series.OrganizerID,
+			TimeSlot:   c.slot,
+			Status:     models.ReservationBooked,
+			SeriesID:   &series.ID,
+		}
+		if err := tx.Reservations().Create(ctx, reservation); err != nil {
+			return nil, slotConflict(err)
+		}
+		if err := recordAvailability(ctx, tx, models.AvailabilityBooked, c.tv, c.slot); err != nil {
+			return nil, err
+		}
+	}
+	return conflicts, nil
+}
+
+// planSeries membandingkan slot mendatang yang dihasilkan aturan series dengan
+// reservasi series yang sudah ada. Hasilnya adalah slot yang perlu dibuat,
+// reservasi yang perlu dibatalkan, dan slot yang bentrok dengan booking lain.
+func (s *BookingService) planSeries(ctx context.Context, store repository.Store, series *models.ReservationSeries, tvs []models.TVInfo) ([]plannedSlot, []models.Reservation, []models.SeriesConflict, error) {
+	now := s.Now()
+	existing, err := store.Reservations().ListBySeries(ctx, series.ID, SlotKey(now))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	held := map[tvSlot]models.Reservation{}
+	for _, r := range existing {
+		held[tvSlot{r.TVID, r.TimeSlot}] = r
+	}
+
+	tz := timezoneOf(locationOf(tvs[0]))
+	wanted := map[tvSlot]bool{}
+	create := []plannedSlot{}
+	conflicts := []models.SeriesConflict{}
+	for _, start := range seriesStarts(series, tz) {
+		if start.Before(now) {
+			continue
+		}
+		slot := SlotKey(start)
+		for _, tv := range tvs {
+			key := tvSlot{tv.ID, slot}
+			wanted[key] = true
+			if _, ok := held[key]; ok {
+				continue
+			}
+
+			free, err := s.slotFree(ctx, store, tv, slot)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if !free {
+				reason := "booked"
+				if tv.OutOfService {
+					reason = "out_of_service"
+				}
+				conflicts = append(conflicts, models.SeriesConflict{TVID: tv.ID, TimeSlot: slot, Reason: reason})
+				continue
+			}
+			create = append(create, plannedSlot{tv: tv, slot: slot})
+		}
+	}
+
+	cancel := []models.Reservation{}
+	for key, r := range held {
+		if !wanted[key] {
+			cancel = append(cancel, r)
+		}
+	}
+	return create, cancel, conflicts, nil
+}
+
+// cancelOccurrence membatalkan satu kejadian series yang masih booked dan
+// melepas slotnya. Kejadian yang sudah check-in dibiarkan berjalan.
+func (s *BookingService) cancelOccurrence(ctx context.Context, tx repository.Store, r models.Reservation) error {
+	if r.Status != models.ReservationBooked {
+		return nil
+	}
+	tv, err := tx.TVs().FindByID(ctx, r.TVID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Reservations().UpdateStatus(ctx, r.ID, models.ReservationCancelled); err != nil {
+		return err
+	}
+	return s.releaseSlot(ctx, tx, *tv, r.TimeSlot)
+}
+
+// validateSeries memeriksa bahwa semua TV series berada di satu lokasi, jamnya
+// berada dalam jam buka lokasi tersebut, dan rentang tanggalnya masuk akal.
+// LocationID series diisi dari lokasi TV-nya.
+func (s *BookingService) validateSeries(ctx context.Context, series *models.ReservationSeries) ([]models.TVInfo, error) {
+	tvs, err := s.seriesTVs(ctx, series)
+	if err != nil {
+		return nil, err
+	}
+	for _, tv := range tvs[1:] {
+		if tv.LocationID != tvs[0].LocationID {
+			return nil, invalid("tvIds", "All TVs must be at the same location")
+		}
+	}
+	series.LocationID = tvs[0].LocationID
+	loc := locationOf(tvs[0])
+
+	if err := validateSeriesHours("startHour", series.StartHour, series.EndHour, loc); err != nil {
+		return nil, err
+	}
+	for _, o := range series.Overrides {
+		if err := validateSeriesHours("endHour", o.StartHour, o.EndHour, loc); err != nil {
+			return nil, err
+		}
+	}
+
+	start, err := time.Parse(time.DateOnly, series.StartDate)
+	if err != nil {
+		return nil, invalid("startDate", "startDate must be a date (YYYY-MM-DD)")
+	}
+	end, err := time.Parse(time.DateOnly, series.EndDate)
+	if err != nil {
+		return nil, invalid("endDate", "endDate must be a date (YYYY-MM-DD)")
+	}
+	if end.Before(start) {
+		return nil, invalid("endDate", "endDate must not be before startDate")
+	}
+	if end.Sub(start) > maxSeriesSpan {
+		return nil, invalid("endDate", "Series must not span more than one year")
+	}
+	return tvs, nil
+}
+
+// validateSeriesHours memeriksa jam kejadian series terhadap jam buka lokasi.
+func validateSeriesHours(field string, startHour, endHour int, loc models.Location) error {
+	if endHour <= startHour {
+		return invalid("endHour", "endHour must be after startHour")
+	}
+	if startHour < loc.OpenHour || endHour > loc.CloseHour {
+		return invalid(field, "Series hours are outside the location's opening hours")
+	}
+	return nil
+}
+
+// seriesTVs memuat TV series sesuai urutan TVIDs.
+func (s *BookingService) seriesTVs(ctx context.Context, series *models.ReservationSeries) ([]models.TVInfo, error) {
+	if len(series.TVIDs) == 0 {
+		return nil, invalid("tvIds", "tvIds must contain at least 1 items")
+	}
+	tvs := make([]models.TVInfo, 0, len(series.TVIDs))
+	for _, id := range series.TVIDs {
+		tv, err := s.store.TVs().FindByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, ErrTVNotFound
+			}
+			return nil, err
+		}
+		tvs = append(tvs, *tv)
+	}
+	return tvs, nil
+}
+
+// findSeries mencari series yang boleh dilihat actor: miliknya sendiri atau
+// berada di lokasi yang dikelolanya. manager menandakan actor mengelola lokasinya.
+func (s *BookingService) findSeries(ctx context.Context, actor Actor, id uint) (series *models.ReservationSeries, manager bool, err error) {
+	series, err = s.store.Series().FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, false, ErrSeriesNotFound
+		}
+		return nil, false, err
+	}
+	manager, err = canManage(ctx, s.store.Staff(), actor, series.LocationID)
+	if err != nil {
+		return nil, false, err
+	}
+	// Series orang lain disembunyikan dari mahasiswa
+	if !manager && series.OrganizerID != actor.UserID {
+		return nil, false, ErrSeriesNotFound
+	}
+	return series, manager, nil
+}
+
+// editableSeries mencari series yang boleh diubah actor.
+func (s *BookingService) editableSeries(ctx context.Context, actor Actor, id uint) (*models.ReservationSeries, error) {
+	series, manager, err := s.findSeries(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status == models.SeriesRejected || series.Status == models.SeriesCancelled {
+		return nil, ErrSeriesClosed
+	}
+	if series.Status == models.SeriesApproved && !manager {
+		return nil, ErrSeriesForbidden
+	}
+	return series, nil
+}
+
+// seriesStarts menghasilkan waktu mulai setiap slot satu jam dari semua
+// kejadian series, dengan tanggal dan jam dalam zona waktu tz.
+func seriesStarts(series *models.ReservationSeries, tz *time.Location) []time.Time {
+	var starts []time.Time
+	for _, date := range seriesDates(series) {
+		startHour, endHour := seriesHoursOn(series, date)
+		day, err := time.ParseInLocation(time.DateOnly, date, tz)
+		if err != nil {
+			continue
+		}
+		for h := startHour; h < endHour; h++ {
+			starts = append(starts, time.Date(day.Year(), day.Month(), day.Day(), h, 0, 0, 0, tz))
+		}
+	}
+	return starts
+}
+
+// seriesDates mengembalikan tanggal setiap kejadian series tanpa ExceptionDates.
+func seriesDates(series *models.ReservationSeries) []string {
+	return slices.DeleteFunc(cadenceDates(series), func(d string) bool {
+		return slices.Contains(series.ExceptionDates, d)
+	})
+}
+
+// cadenceDates mengembalikan tanggal (YYYY-MM-DD) mulai StartDate setiap satu
+// atau dua minggu sampai EndDate, termasuk tanggal pengecualian.
+func cadenceDates(series *models.ReservationSeries) []string {
+	start, err := time.Parse(time.DateOnly, series.StartDate)
+	if err != nil {
+		return nil
+	}
+	end, err := time.Parse(time.DateOnly, series.EndDate)
+	if err != nil {
+		return nil
+	}
+	step := 7
+	if series.Frequency == models.SeriesBiweekly {
+		step = 14
+	}
+
+	var dates []string
+	for day := start; !day.After(end); day = day.AddDate(0, 0, step) {
+		dates = append(dates, day.Format(time.DateOnly))
+	}
+	return dates
+}
+
+// seriesHoursOn mengembalikan jam mulai dan selesai kejadian series pada date,
+// memperhitungkan Overrides.
+func seriesHoursOn(series *models.ReservationSeries, date string) (startHour, endHour int) {
+	for _, o := range series.Overrides {
+		if o.Date == date {
+			return o.StartHour, o.EndHour
+		}
+	}
+	return series.StartHour, series.EndHour
+}
+
+// unique mengembalikan values tanpa duplikat dengan urutan kemunculan pertama.
+func unique[T comparable](values []T) []T {
+	result := []T{}
+	for _, v := range values {
+		if !slices.Contains(result, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+)
+
+func TestApproveSeriesConflicts(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(t *testing.T, f *fixture)
+		skipConflicts bool
+		wantConflicts []models.SeriesConflict
+		wantCode      string
+		// wantCreated adalah jumlah reservasi series setelah persetujuan.
+		wantCreated int
+	}{
+		{
+			name:        "approves a series without conflicts",
+			wantCreated: 12,
+		},
+		{
+			name: "rejects approval when a slot is booked",
+			setup: func(t *testing.T, f *fixture) {
+				f.book(t, "u2", 1, f.slot(1, 15))
+			},
+			wantConflicts: []models.SeriesConflict{{TVID: 1, Reason: "booked"}},
+			wantCode:      "SERIES_CONFLICT",
+		},
+		{
+			name: "skips booked slots when asked to",
+			setup: func(t *testing.T, f *fixture) {
+				f.book(t, "u2", 1, f.slot(1, 15))
+			},
+			skipConflicts: true,
+			wantConflicts: []models.SeriesConflict{{TVID: 1, Reason: "booked"}},
+			wantCreated:   11,
+		},
+		{
+			name: "reports slots on an out-of-service TV",
+			setup: func(t *testing.T, f *fixture) {
+				if err := f.store.TVs().UpdateService(context.Background(), 2, true, "broken"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			skipConflicts: true,
+			wantConflicts: []models.SeriesConflict{
+				{TVID: 2, Reason: "out_of_service"}, {TVID: 2, Reason: "out_of_service"},
+				{TVID: 2, Reason: "out_of_service"}, {TVID: 2, Reason: "out_of_service"},
+				{TVID: 2, Reason: "out_of_service"}, {TVID: 2, Reason: "out_of_service"},
+			},
+			wantCreated: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+
+			// Setiap Selasa 15:00–17:00 selama tiga minggu pada dua TV
+			series, conflicts, err := f.svc.CreateSeries(ctx, services.Actor{UserID: "u1", Role: models.RoleStudent}, models.SeriesBody{
+				Title:     "Esports practice",
+				TVIDs:     []int{1, 2},
+				Frequency: models.SeriesWeekly,
+				StartDate: "2026-03-03",
+				EndDate:   "2026-03-17",
+				StartHour: 15,
+				EndHour:   17,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(conflicts) != len(tt.wantConflicts) {
+				t.Fatalf("got %d conflicts, want %d: %+v", len(conflicts), len(tt.wantConflicts), conflicts)
+			}
+			for i, c := range conflicts {
+				if c.TVID != tt.wantConflicts[i].TVID || c.Reason != tt.wantConflicts[i].Reason {
+					t.Fatalf("conflict %d = %+v, want TV %d %s", i, c, tt.wantConflicts[i].TVID, tt.wantConflicts[i].Reason)
+				}
+			}
+
+			_, _, err = f.svc.ApproveSeries(ctx, f.staff, series.ID, tt.skipConflicts)
+			if got := code(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			created, err := f.store.Reservations().ListBySeries(ctx, series.ID, f.slot(0, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(created) != tt.wantCreated {
+				t.Fatalf("series holds %d reservations, want %d", len(created), tt.wantCreated)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"playcorner-be/internal/models"
+	"time"
+)
+
+// defaultLocation dipakai untuk TV yang belum ditempatkan di lokasi mana pun,
+// sehingga jadwalnya tetap sama seperti sebelum ada entitas Location.
+var defaultLocation = models.Location{Name: "Default", Timezone: "UTC", OpenHour: 9, CloseHour: 18}
+
+// locationOf mengembalikan lokasi TV yang sudah di-preload, atau defaultLocation.
+func locationOf(tv models.TVInfo) models.Location {
+	if tv.Location != nil {
+		return *tv.Location
+	}
+	return defaultLocation
+}
+
+// timezoneOf memuat zona waktu lokasi, dengan UTC sebagai cadangan.
+func timezoneOf(loc models.Location) *time.Location {
+	tz, err := time.LoadLocation(loc.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return tz
+}
+
+// daySlots menghasilkan waktu mulai setiap slot satu jam pada hari yang sama
+// dengan now (menurut zona waktu lokasi) selama jam buka lokasi.
+func daySlots(loc models.Location, now time.Time) []time.Time {
+	local := now.In(timezoneOf(loc))
+
+	var slots []time.Time
+	for h := loc.OpenHour; h < loc.CloseHour; h++ {
+		slots = append(slots, time.Date(local.Year(), local.Month(), local.Day(), h, 0, 0, 0, local.Location()))
+	}
+	return slots
+}
+
+// slotStart mengembalikan awal slot satu jam yang memuat t menurut jam lokal
+// lokasi, sehingga tetap benar untuk zona waktu dengan offset bukan jam penuh.
+func slotStart(loc models.Location, t time.Time) time.Time {
+	local := t.In(timezoneOf(loc))
+	return time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, local.Location())
+}
+
+// tvSlot mengidentifikasi satu slot pada satu TV.
+type tvSlot struct {
+	tvID int
+	slot string
+}
+
+// plannedSlot adalah slot kanonik yang akan dipesan pada sebuah TV.
+type plannedSlot struct {
+	tv   models.TVInfo
+	slot string
+}
+
+// SlotKey adalah representasi kanonik slot yang disimpan di kolom time_slot (RFC3339, UTC).
+func SlotKey(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// sessionBounds mengembalikan waktu mulai dan berakhirnya sesi sebuah reservasi.
+func sessionBounds(r models.Reservation) (start, end time.Time, err error) {
+	start, err = time.Parse(time.RFC3339, r.TimeSlot)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, start.Add(time.Hour), nil
+}
+
+// normalizeSlot memvalidasi slot waktu dari request terhadap jam buka lokasi
+// dan mengembalikannya dalam bentuk kanonik.
+func normalizeSlot(raw string, loc models.Location) (string, error) {
+	start, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return "", invalid("timeslot", "Timeslot must be an RFC3339 date-time")
+	}
+
+	local := start.In(timezoneOf(loc))
+	if local.Minute() != 0 || local.Second() != 0 || local.Nanosecond() != 0 {
+		return "", invalid("timeslot", "Timeslot must start on the hour")
+	}
+	if local.Hour() < loc.OpenHour || local.Hour() >= loc.CloseHour {
+		return "", invalid("timeslot", "Timeslot is outside the location's opening hours")
+	}
+
+	return SlotKey(start), nil
+}
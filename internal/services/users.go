@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"strconv"
+)
+
+// notificationLimit adalah jumlah notifikasi terbaru yang dikembalikan.
+const notificationLimit = 50
+
+// UserService menyediakan profil, riwayat, dan notifikasi pengguna.
+type UserService struct {
+	store repository.Store
+}
+
+func NewUserService(store repository.Store) *UserService {
+	return &UserService{store: store}
+}
+
+func (s *UserService) Get(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.store.Users().FindByID(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+// Histories mengembalikan satu halaman riwayat reservasi pengguna.
+func (s *UserService) Histories(ctx context.Context, userID string, limit, offset int64) (*models.PagedData, error) {
+	reservations, total, err := s.store.Reservations().ListByUser(ctx, userID, int(limit), int(offset))
+	if err != nil {
+		return nil, err
+	}
+
+	histories := []models.History{}
+	for _, r := range reservations {
+		histories = append(histories, models.History{
+			ID:                  r.ID,
+			TVID:                r.TVID,
+			ReservationDateTime: r.TimeSlot,
+			TVPictURL:           "https://placehold.co/600x400/?text=TV+" + strconv.Itoa(r.TVID),
+			Status:              r.Status,
+			BookingID:           r.BookingID,
+			Role:                historyRole(r, userID),
+			PlayedMinutes:       r.PlayedMinutes,
+			WalkIn:              r.WalkIn,
+		})
+	}
+
+	return &models.PagedData{
+		Offset: offset,
+		Limit:  limit,
+		Total:  total,
+		Data:   histories,
+	}, nil
+}
+
+func (s *UserService) Notifications(ctx context.Context, userID string) ([]models.Notification, error) {
+	return s.store.Notifications().ListByUser(ctx, userID, notificationLimit)
+}
+
+// historyRole membedakan reservasi milik userID dari undangan yang diterimanya.
+func historyRole(r models.Reservation, userID string) string {
+	if r.BorrowerID == userID {
+		return "borrower"
+	}
+	return "participant"
+}
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"time"
+)
+
+// JoinWaitlist memasukkan userID ke antrean slot yang sudah penuh pada TV tvID,
+// atau pada TV mana pun dengan jenis konsol yang sama di lokasi tersebut jika
+// anyTV. Slot yang masih bisa dipesan langsung ditolak dengan ErrSlotAvailable.
+func (s *BookingService) JoinWaitlist(ctx context.Context, userID string, tvID int, timeslot string, anyTV bool) (*models.WaitlistEntry, error) {
+	tv, err := s.store.TVs().FindByID(ctx, tvID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTVNotFound
+		}
+		return nil, err
+	}
+	if tv.OutOfService && !anyTV {
+		return nil, ErrTVOutOfService
+	}
+
+	slot, err := normalizeSlot(timeslot, locationOf(*tv))
+	if err != nil {
+		return nil, err
+	}
+	start, err := time.Parse(time.RFC3339, slot)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Now().Before(start.Add(time.Hour)) {
+		return nil, invalid("timeslot", "Timeslot has already ended")
+	}
+
+	candidates := []models.TVInfo{*tv}
+	if anyTV {
+		candidates, err = s.sameConsoleTVs(ctx, *tv)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entry := &models.WaitlistEntry{
+		UserID:      userID,
+		LocationID:  tv.LocationID,
+		ConsoleType: tv.ConsoleType,
+		TimeSlot:    slot,
+		Status:      models.WaitlistWaiting,
+	}
+	if !anyTV {
+		entry.TVID = &tv.ID
+	}
+	tvIDs := make([]int, 0, len(candidates))
+	for _, c := range candidates {
+		tvIDs = append(tvIDs, c.ID)
+	}
+
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		// Kunci pengguna agar dua permintaan paralel tidak sama-sama lolos
+		// pemeriksaan duplikat, lalu kunci TV agar slot yang dilepas bersamaan
+		// tidak terlewat oleh antrean ini
+		if err := tx.Users().Lock(ctx, userID); err != nil {
+			return err
+		}
+		if err := tx.TVs().Lock(ctx, tvIDs...); err != nil {
+			return err
+		}
+		for _, c := range candidates {
+			free, err := s.slotFree(ctx, tx, c, slot)
+			if err != nil {
+				return err
+			}
+			if free {
+				return ErrSlotAvailable
+			}
+		}
+
+		open, err := tx.Waitlist().ListOpenByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, e := range open {
+			if e.TimeSlot == slot && sameWaitlistTarget(e, *entry) {
+				return ErrAlreadyWaitlisted
+			}
+		}
+		return tx.Waitlist().Create(ctx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Waitlist mengembalikan antrean pengguna yang masih menunggu atau sedang ditawari slot.
+func (s *BookingService) Waitlist(ctx context.Context, userID string) ([]models.WaitlistEntry, error) {
+	return s.store.Waitlist().ListOpenByUser(ctx, userID)
+}
+
+// AcceptOffer menerima tawaran slot dari waitlist dan membuat reservasinya.
+func (s *BookingService) AcceptOffer(ctx context.Context, userID string, entryID uint) (*models.Reservation, error) {
+	entry, err := s.findEntry(ctx, userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != models.WaitlistOffered || entry.OfferedTVID == nil ||
+		entry.OfferExpiresAt == nil || !s.Now().Before(*entry.OfferExpiresAt) {
+		return nil, ErrOfferNotActive
+	}
+
+	tv, err := s.store.TVs().FindByID(ctx, *entry.OfferedTVID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTVNotFound
+		}
+		return nil, err
+	}
+	if tv.OutOfService {
+		return nil, ErrTVOutOfService
+	}
+	reservation := &models.Reservation{
+		TVID:       tv.ID,
+		BorrowerID: userID,
+		TimeSlot:   entry.TimeSlot,
+		Status:     models.ReservationBooked,
+	}
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := s.checkQuota(ctx, tx, userID, []plannedSlot{{tv: *tv, slot: entry.TimeSlot}}, 0); err != nil {
+			return err
+		}
+		// Kunci TV lalu baca ulang tawaran agar tidak bersamaan dengan pemesanan
+		// lain atau scheduler yang meneruskan tawaran ke antrean berikutnya
+		if err := tx.TVs().Lock(ctx, tv.ID); err != nil {
+			return err
+		}
+		current, err := tx.Waitlist().FindByID(ctx, entry.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status != models.WaitlistOffered || current.OfferedTVID == nil || *current.OfferedTVID != tv.ID {
+			return ErrOfferNotActive
+		}
+
+		if _, err := tx.Reservations().FindActiveBySlot(ctx, tv.ID, entry.TimeSlot); err == nil {
+			return ErrSlotTaken
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		if err := tx.Reservations().Create(ctx, reservation); err != nil {
+			return slotConflict(err)
+		}
+
+		entry.Status = models.WaitlistAccepted
+		entry.ReservationID = &reservation.ID
+		if err := tx.Waitlist().Update(ctx, entry); err != nil {
+			return err
+		}
+		return recordAvailability(ctx, tx, models.AvailabilityBooked, *tv, entry.TimeSlot)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// LeaveWaitlist mengeluarkan pengguna dari antrean. Tawaran yang sedang
+// berjalan langsung diteruskan ke antrean berikutnya.
+func (s *BookingService) LeaveWaitlist(ctx context.Context, userID string, entryID uint) error {
+	entry, err := s.findEntry(ctx, userID, entryID)
+	if err != nil {
+		return err
+	}
+	if entry.Status != models.WaitlistWaiting && entry.Status != models.WaitlistOffered {
+		return ErrWaitlistClosed
+	}
+	// Entri hanya dapat ditawari slot pada TV yang ditunggunya, dan tawaran
+	// selalu dibuat di bawah kunci TV tersebut
+	tvIDs := []int{}
+	if entry.TVID != nil {
+		tvIDs = append(tvIDs, *entry.TVID)
+	} else {
+		candidates, err := s.sameConsoleTVs(ctx, models.TVInfo{LocationID: entry.LocationID, ConsoleType: entry.ConsoleType})
+		if err != nil {
+			return err
+		}
+		for _, c := range candidates {
+			tvIDs = append(tvIDs, c.ID)
+		}
+	}
+
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		// Kunci TV lalu baca ulang entri agar tidak bersamaan dengan tawaran
+		// baru, penerimaan tawaran, atau scheduler yang mengakhiri tawaran
+		if err := tx.TVs().Lock(ctx, tvIDs...); err != nil {
+			return err
+		}
+		current, err := tx.Waitlist().FindByID(ctx, entry.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status != models.WaitlistWaiting && current.Status != models.WaitlistOffered {
+			return ErrWaitlistClosed
+		}
+
+		offered := current.Status == models.WaitlistOffered
+		current.Status = models.WaitlistCancelled
+		if err := tx.Waitlist().Update(ctx, current); err != nil {
+			return err
+		}
+		if !offered || current.OfferedTVID == nil {
+			return nil
+		}
+		tv, err := tx.TVs().FindByID(ctx, *current.OfferedTVID)
+		if err != nil {
+			return err
+		}
+		return s.releaseSlot(ctx, tx, *tv, current.TimeSlot)
+	})
+}
+
+// ExpireOffers mengakhiri tawaran waitlist yang tidak diterima tepat waktu dan
+// meneruskan slotnya ke antrean berikutnya, lalu menutup antrean yang slotnya
+// sudah berakhir. Dijalankan berkala oleh scheduler.
+func (s *BookingService) ExpireOffers(ctx context.Context) error {
+	now := s.Now()
+	offers, err := s.store.Waitlist().ListExpiredOffers(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range offers {
+		err := s.store.Transaction(ctx, func(tx repository.Store) error {
+			// Tawaran yang baru saja diterima tidak lagi berstatus offered
+			if err := tx.TVs().Lock(ctx, *entry.OfferedTVID); err != nil {
+				return err
+			}
+			current, err := tx.Waitlist().FindByID(ctx, entry.ID)
+			if err != nil {
+				return err
+			}
+			if current.Status != models.WaitlistOffered {
+				return nil
+			}
+
+			entry.Status = models.WaitlistExpired
+			if err := tx.Waitlist().Update(ctx, &entry); err != nil {
+				return err
+			}
+			msg := fmt.Sprintf("Your waitlist offer for TV %d at %s has expired.", *entry.OfferedTVID, entry.TimeSlot)
+			if err := tx.Notifications().Create(ctx, &models.Notification{UserID: entry.UserID, Message: msg}); err != nil {
+				return err
+			}
+
+			tv, err := tx.TVs().FindByID(ctx, *entry.OfferedTVID)
+			if err != nil {
+				return err
+			}
+			return s.releaseSlot(ctx, tx, *tv, entry.TimeSlot)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = s.store.Waitlist().ExpireWaiting(ctx, SlotKey(now.Add(-time.Hour)))
+	return err
+}
+
+// releaseSlot dipanggil di dalam transaksi saat slot pada tv dilepas. Slot
+// ditawarkan ke antrean waitlist berikutnya; jika tidak ada, slot kembali
+// tersedia untuk semua orang.
+func (s *BookingService) releaseSlot(ctx context.Context, tx repository.Store, tv models.TVInfo, slot string) error {
+	offered, err := s.offerSlot(ctx, tx, tv, slot)
+	if err != nil || offered {
+		return err
+	}
+	return recordAvailability(ctx, tx, models.AvailabilityCancelled, tv, slot)
+}
+
+// offerSlot menahan slot untuk entri waiting tertua selama WaitlistOfferWindow
+// (paling lama sampai slot berakhir) dan memberi tahu pemiliknya.
+func (s *BookingService) offerSlot(ctx context.Context, tx repository.Store, tv models.TVInfo, slot string) (bool, error) {
+	if tv.OutOfService {
+		return false, nil
+	}
+	start, err := time.Parse(time.RFC3339, slot)
+	if err != nil {
+		return false, err
+	}
+	now, end := s.Now(), start.Add(time.Hour)
+	if !now.Before(end) {
+		return false, nil
+	}
+
+	entry, err := tx.Waitlist().NextWaiting(ctx, tv.ID, tv.LocationID, tv.ConsoleType, slot)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	expires := now.Add(s.policy.WaitlistOfferWindow)
+	if expires.After(end) {
+		expires = end
+	}
+	entry.Status = models.WaitlistOffered
+	entry.OfferedTVID = &tv.ID
+	entry.OfferExpiresAt = &expires
+	if err := tx.Waitlist().Update(ctx, entry); err != nil {
+		return false, err
+	}
+
+	msg := fmt.Sprintf("TV %d is now available at %s. Accept the offer before %s or it passes to the next person on the waitlist.",
+		tv.ID, slot, SlotKey(expires))
+	if err := tx.Notifications().Create(ctx, &models.Notification{UserID: entry.UserID, Message: msg}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// slotFree memeriksa apakah slot pada tv dapat dipesan: TV beroperasi, belum
+// ada reservasi aktif, dan slot tidak sedang ditawarkan ke antrean waitlist.
+func (s *BookingService) slotFree(ctx context.Context, store repository.Store, tv models.TVInfo, slot string) (bool, error) {
+	if tv.OutOfService {
+		return false, nil
+	}
+	if _, err := store.Reservations().FindActiveBySlot(ctx, tv.ID, slot); err == nil {
+		return false, nil
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return false, err
+	}
+
+	start, err := time.Parse(time.RFC3339, slot)
+	if err != nil {
+		return false, err
+	}
+	offers, err := store.Waitlist().ListOffers(ctx, tv.ID, slot, SlotKey(start.Add(time.Hour)))
+	if err != nil {
+		return false, err
+	}
+	return len(offers) == 0, nil
+}
+
+// slotConflict menerjemahkan pelanggaran index slot aktif menjadi ErrSlotTaken,
+// yaitu ketika transaksi lain memesan slot yang sama lebih dulu.
+func slotConflict(err error) error {
+	if errors.Is(err, repository.ErrDuplicate) {
+		return ErrSlotTaken
+	}
+	return err
+}
+
+// sameConsoleTVs mengembalikan TV di lokasi tv dengan jenis konsol yang sama.
+func (s *BookingService) sameConsoleTVs(ctx context.Context, tv models.TVInfo) ([]models.TVInfo, error) {
+	tvs, err := s.store.TVs().List(ctx, repository.TVFilter{LocationID: &tv.LocationID})
+	if err != nil {
+		return nil, err
+	}
+	same := []models.TVInfo{}
+	for _, t := range tvs {
+		if t.ConsoleType == tv.ConsoleType {
+			same = append(same, t)
+		}
+	}
+	return same, nil
+}
+
+// findEntry mencari entri waitlist milik userID.
+func (s *BookingService) findEntry(ctx context.Context, userID string, entryID uint) (*models.WaitlistEntry, error) {
+	entry, err := s.store.Waitlist().FindByID(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWaitlistNotFound
+		}
+		return nil, err
+	}
+	if entry.UserID != userID {
+		return nil, ErrWaitlistNotFound
+	}
+	return entry, nil
+}
+
+// sameWaitlistTarget melaporkan apakah dua entri menunggu TV yang sama, atau
+// sama-sama menunggu TV mana pun dengan jenis konsol yang sama di satu lokasi.
+func sameWaitlistTarget(a, b models.WaitlistEntry) bool {
+	if a.TVID != nil || b.TVID != nil {
+		return a.TVID != nil && b.TVID != nil && *a.TVID == *b.TVID
+	}
+	return a.LocationID == b.LocationID && a.ConsoleType == b.ConsoleType
+}
@@ -0,0 +1,251 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+)
+
+func TestWaitlistOfferCascade(t *testing.T) {
+	tests := []struct {
+		name string
+		// run menjalankan langkah setelah u1 membatalkan slot yang diantre u2
+		// lalu u3; entri u2 dan u3 diteruskan dalam urutan tersebut.
+		run func(t *testing.T, f *fixture, first, second *models.WaitlistEntry) error
+		// wantHolder adalah peminjam slot di akhir skenario, kosong jika tidak ada.
+		wantHolder string
+		wantCode   string
+	}{
+		{
+			name: "first in line accepts the offer",
+			run: func(t *testing.T, f *fixture, first, _ *models.WaitlistEntry) error {
+				_, err := f.svc.AcceptOffer(context.Background(), "u2", first.ID)
+				return err
+			},
+			wantHolder: "u2",
+		},
+		{
+			name: "expired offer passes to the next in line",
+			run: func(t *testing.T, f *fixture, first, second *models.WaitlistEntry) error {
+				f.now = f.now.Add(11 * time.Minute)
+				if err := f.svc.ExpireOffers(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := f.svc.AcceptOffer(context.Background(), "u2", first.ID); code(err) != "OFFER_NOT_ACTIVE" {
+					t.Fatalf("expired offer accepted: %v", err)
+				}
+				_, err := f.svc.AcceptOffer(context.Background(), "u3", second.ID)
+				return err
+			},
+			wantHolder: "u3",
+		},
+		{
+			name: "leaving while offered passes to the next in line",
+			run: func(t *testing.T, f *fixture, first, second *models.WaitlistEntry) error {
+				if err := f.svc.LeaveWaitlist(context.Background(), "u2", first.ID); err != nil {
+					t.Fatal(err)
+				}
+				_, err := f.svc.AcceptOffer(context.Background(), "u3", second.ID)
+				return err
+			},
+			wantHolder: "u3",
+		},
+		{
+			name: "waiting entry cannot accept before its offer",
+			run: func(t *testing.T, f *fixture, _, second *models.WaitlistEntry) error {
+				_, err := f.svc.AcceptOffer(context.Background(), "u3", second.ID)
+				return err
+			},
+			wantCode: "OFFER_NOT_ACTIVE",
+		},
+		{
+			name: "offered slot cannot be booked directly",
+			run: func(t *testing.T, f *fixture, _, _ *models.WaitlistEntry) error {
+				_, err := f.svc.CreateReservation(context.Background(), "u3", 1, f.slot(0, 12))
+				return err
+			},
+			wantCode: "SLOT_TAKEN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+			slot := f.slot(0, 12)
+			res := f.book(t, "u1", 1, slot)
+
+			first, err := f.svc.JoinWaitlist(ctx, "u2", 1, slot, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := f.svc.JoinWaitlist(ctx, "u3", 1, slot, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := f.svc.CancelReservation(ctx, services.Actor{UserID: "u1", Role: models.RoleStudent}, res.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			err = tt.run(t, f, first, second)
+			if got := code(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			holder, err := f.store.Reservations().FindActiveBySlot(ctx, 1, slot)
+			switch {
+			case tt.wantHolder == "" && err == nil:
+				t.Fatalf("slot held by %s, want free", holder.BorrowerID)
+			case tt.wantHolder != "" && err != nil:
+				t.Fatalf("slot not held, want %s: %v", tt.wantHolder, err)
+			case tt.wantHolder != "" && holder.BorrowerID != tt.wantHolder:
+				t.Fatalf("slot held by %s, want %s", holder.BorrowerID, tt.wantHolder)
+			}
+		})
+	}
+}
+
+func TestJoinWaitlist(t *testing.T) {
+	tests := []struct {
+		name     string
+		booked   bool
+		twice    bool
+		wantCode string
+	}{
+		{name: "joins a taken slot", booked: true},
+		{name: "rejects a free slot", wantCode: "SLOT_AVAILABLE"},
+		{name: "rejects joining twice", booked: true, twice: true, wantCode: "ALREADY_WAITLISTED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+			slot := f.slot(0, 12)
+			if tt.booked {
+				f.book(t, "u1", 1, slot)
+			}
+			if tt.twice {
+				if _, err := f.svc.JoinWaitlist(ctx, "u2", 1, slot, false); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := f.svc.JoinWaitlist(ctx, "u2", 1, slot, false)
+			if got := code(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestWaitlistConcurrent(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup dijalankan setelah u1 memesan slot dan u2 lalu u3 mengantre,
+		// kemudian run dijalankan empat kali secara paralel. Entri u2 dan u3
+		// diteruskan ke run dalam urutan tersebut.
+		setup    func(f *fixture, res *models.Reservation) error
+		run      func(f *fixture, res *models.Reservation, first, second *models.WaitlistEntry) error
+		wantCode string
+		// wantU2 dan wantU3 adalah status akhir entri u2 dan u3.
+		wantU2, wantU3 string
+	}{
+		{
+			name: "double cancel offers the slot once",
+			run: func(f *fixture, res *models.Reservation, _, _ *models.WaitlistEntry) error {
+				_, err := f.svc.CancelReservation(context.Background(), services.Actor{UserID: "u1", Role: models.RoleStudent}, res.ID)
+				return err
+			},
+			wantCode: "RESERVATION_NOT_BOOKED",
+			wantU2:   models.WaitlistOffered, wantU3: models.WaitlistWaiting,
+		},
+		{
+			name: "double check-in records the session once",
+			setup: func(f *fixture, _ *models.Reservation) error {
+				f.at(0, 11, 50)
+				return nil
+			},
+			run: func(f *fixture, res *models.Reservation, _, _ *models.WaitlistEntry) error {
+				_, _, err := f.svc.CheckIn(context.Background(), f.staff, res.ID, nil)
+				return err
+			},
+			wantCode: "RESERVATION_NOT_BOOKED",
+			wantU2:   models.WaitlistWaiting, wantU3: models.WaitlistWaiting,
+		},
+		{
+			name: "leaving twice while offered passes the offer once",
+			setup: func(f *fixture, res *models.Reservation) error {
+				_, err := f.svc.CancelReservation(context.Background(), services.Actor{UserID: "u1", Role: models.RoleStudent}, res.ID)
+				return err
+			},
+			run: func(f *fixture, _ *models.Reservation, first, _ *models.WaitlistEntry) error {
+				return f.svc.LeaveWaitlist(context.Background(), "u2", first.ID)
+			},
+			wantCode: "WAITLIST_ENTRY_CLOSED",
+			wantU2:   models.WaitlistCancelled, wantU3: models.WaitlistOffered,
+		},
+		{
+			name: "joining twice creates one entry",
+			run: func(f *fixture, _ *models.Reservation, _, _ *models.WaitlistEntry) error {
+				_, err := f.svc.JoinWaitlist(context.Background(), "u1", 2, f.slot(0, 12), false)
+				return err
+			},
+			wantCode: "ALREADY_WAITLISTED",
+			wantU2:   models.WaitlistWaiting, wantU3: models.WaitlistWaiting,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+			slot := f.slot(0, 12)
+			res := f.book(t, "u1", 1, slot)
+			f.book(t, "u3", 2, slot)
+			first, err := f.svc.JoinWaitlist(ctx, "u2", 1, slot, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := f.svc.JoinWaitlist(ctx, "u3", 1, slot, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.setup != nil {
+				if err := tt.setup(f, res); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			errs := concurrently(4, func(int) error { return tt.run(f, res, first, second) })
+			succeeded := 0
+			for _, err := range errs {
+				switch got := code(err); got {
+				case "":
+					succeeded++
+				case tt.wantCode:
+				default:
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if succeeded != 1 {
+				t.Fatalf("%d concurrent calls succeeded, want exactly 1", succeeded)
+			}
+
+			for _, want := range []struct {
+				entry  *models.WaitlistEntry
+				status string
+			}{{first, tt.wantU2}, {second, tt.wantU3}} {
+				got, err := f.store.Waitlist().FindByID(ctx, want.entry.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Status != want.status {
+					t.Fatalf("entry of %s is %s, want %s", got.UserID, got.Status, want.status)
+				}
+			}
+		})
+	}
+}
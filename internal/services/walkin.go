@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/repository"
+	"time"
+)
+
+// StartWalkIn dicatat staff saat mahasiswa (userID) datang langsung dan TV
+// sedang kosong. Sesi dimulai sekarang sampai slot saat ini berakhir, memblokir
+// slot tersebut, dan diakhiri lewat check-out seperti reservasi biasa. Sisa
+// slot yang sesinya sudah check-out lebih awal juga dapat dipakai walk-in.
+func (s *BookingService) StartWalkIn(ctx context.Context, actor Actor, tvID int, userID string) (*models.Reservation, error) {
+	tv, err := s.store.TVs().FindByID(ctx, tvID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTVNotFound
+		}
+		return nil, err
+	}
+	ok, err := canManage(ctx, s.store.Staff(), actor, tv.LocationID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLocationForbidden
+	}
+	if tv.OutOfService {
+		return nil, ErrTVOutOfService
+	}
+	if _, err := s.store.Users().FindByID(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	now := s.Now()
+	loc := locationOf(*tv)
+	slot, err := normalizeSlot(SlotKey(slotStart(loc, now)), loc)
+	if err != nil {
+		return nil, ErrLocationClosed
+	}
+	session := &models.Reservation{
+		TVID:        tv.ID,
+		BorrowerID:  userID,
+		TimeSlot:    slot,
+		Status:      models.ReservationCheckedIn,
+		CheckedInAt: &now,
+		WalkIn:      true,
+	}
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := s.checkQuota(ctx, tx, userID, []plannedSlot{{tv: *tv, slot: slot}}, 0); err != nil {
+			return err
+		}
+		// Kunci TV agar pemesanan paralel tidak lolos pemeriksaan slot bersamaan
+		if err := tx.TVs().Lock(ctx, tv.ID); err != nil {
+			return err
+		}
+		free, err := walkInFree(ctx, tx, *tv, slot)
+		if err != nil {
+			return err
+		}
+		if !free {
+			return ErrTVBusy
+		}
+
+		if err := tx.Reservations().Create(ctx, session); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrTVBusy
+			}
+			return err
+		}
+		return recordAvailability(ctx, tx, models.AvailabilityCheckedIn, *tv, slot)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// walkInFree memastikan slot tidak dipegang reservasi yang belum check-out dan
+// tidak sedang ditawarkan ke antrean waitlist.
+func walkInFree(ctx context.Context, tx repository.Store, tv models.TVInfo, slot string) (bool, error) {
+	start, err := time.Parse(time.RFC3339, slot)
+	if err != nil {
+		return false, err
+	}
+	to := SlotKey(start.Add(time.Hour))
+	reservations, err := tx.Reservations().ListActiveByTV(ctx, tv.ID, slot, to)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range reservations {
+		if r.CheckedOutAt == nil {
+			return false, nil
+		}
+	}
+	offers, err := tx.Waitlist().ListOffers(ctx, tv.ID, slot, to)
+	if err != nil {
+		return false, err
+	}
+	return len(offers) == 0, nil
+}
@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/services"
+)
+
+func TestStartWalkIn(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		setup    func(t *testing.T, f *fixture)
+		wantCode string
+	}{
+		{name: "starts a session on a free TV", userID: "u1"},
+		{
+			name: "rejects a TV booked for the current slot", userID: "u1",
+			setup:    func(t *testing.T, f *fixture) { f.book(t, "u2", 1, f.slot(0, 9)) },
+			wantCode: "TV_BUSY",
+		},
+		{
+			name: "uses the rest of a slot that was checked out early", userID: "u1",
+			setup: func(t *testing.T, f *fixture) {
+				f.at(0, 8, 50)
+				res := f.book(t, "u2", 1, f.slot(0, 9))
+				f.checkIn(t, res)
+				f.at(0, 9, 20)
+				if _, err := f.svc.CheckOut(context.Background(), f.staff, res.ID); err != nil {
+					t.Fatal(err)
+				}
+				f.at(0, 9, 30)
+			},
+		},
+		{
+			name: "rejects a slot offered to the waitlist", userID: "u1",
+			setup: func(t *testing.T, f *fixture) {
+				res := f.book(t, "u2", 1, f.slot(0, 9))
+				if _, err := f.svc.JoinWaitlist(context.Background(), "u3", 1, f.slot(0, 9), false); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := f.svc.CancelReservation(context.Background(), f.staff, res.ID); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantCode: "TV_BUSY",
+		},
+		{
+			name: "rejects a walk-in outside opening hours", userID: "u1",
+			setup:    func(t *testing.T, f *fixture) { f.at(0, 7, 30) },
+			wantCode: "LOCATION_CLOSED",
+		},
+		{
+			name: "counts towards the daily quota", userID: "u1",
+			setup: func(t *testing.T, f *fixture) {
+				for _, hour := range []int{12, 13, 14, 15} {
+					f.book(t, "u1", 2, f.slot(0, hour))
+				}
+			},
+			wantCode: "BOOKING_QUOTA_EXCEEDED",
+		},
+		{name: "rejects an unknown user", userID: "nobody", wantCode: "USER_NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+
+			session, err := f.svc.StartWalkIn(context.Background(), f.staff, 1, tt.userID)
+			if got := code(err); got != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (err: %v)", got, tt.wantCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if !session.WalkIn || session.Status != models.ReservationCheckedIn || session.TimeSlot != f.slot(0, 9) {
+				t.Fatalf("session = %+v, want a checked-in walk-in at %s", session, f.slot(0, 9))
+			}
+		})
+	}
+}
+
+func TestStartWalkInHalfHourTimezone(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	loc := f.store.AddLocation(models.Location{Name: "Bengaluru", Timezone: "Asia/Kolkata", OpenHour: 8, CloseHour: 22})
+	f.store.AddTV(models.TVInfo{ID: 3, ConsoleType: "PS5", LocationID: loc.ID})
+	if err := f.store.Staff().Assign(ctx, "s1", loc.ID); err != nil {
+		t.Fatal(err)
+	}
+	ist, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Slot saat ini dimulai pada jam penuh waktu lokal (04:30 UTC), bukan jam penuh UTC
+	f.now = time.Date(2026, time.March, 2, 10, 20, 0, 0, ist)
+	session, err := f.svc.StartWalkIn(ctx, services.Actor{UserID: "s1", Role: models.RoleStaff}, 3, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.TimeSlot != "2026-03-02T04:30:00Z" {
+		t.Fatalf("walk-in slot = %s, want 2026-03-02T04:30:00Z", session.TimeSlot)
+	}
+}
@@ -0,0 +1,76 @@
+// Package tracing
+package tracing
+
+import (
+	"context"
+	"playcorner-be/internal/config"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// Provider adalah tracer provider aplikasi beserta fungsi untuk mengirim sisa span saat shutdown.
+type Provider struct {
+	trace.TracerProvider
+	Propagator propagation.TextMapPropagator
+
+	shutdown func(ctx context.Context) error
+}
+
+// Setup membuat tracer provider sesuai konfigurasi dan memasangnya sebagai
+// provider global. Dengan exporter "none" span tidak dicatat, tetapi header W3C
+// traceparent tetap diteruskan.
+func Setup(ctx context.Context, cfg config.TracingConfig, env string) (*Provider, error) {
+	propagator := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	otel.SetTextMapPropagator(propagator)
+
+	if cfg.Exporter == "none" {
+		tp := noop.NewTracerProvider()
+		otel.SetTracerProvider(tp)
+		return &Provider{TracerProvider: tp, Propagator: propagator, shutdown: func(context.Context) error { return nil }}, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpointHost(cfg.Endpoint))}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.DeploymentEnvironment(env),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	return &Provider{TracerProvider: tp, Propagator: propagator, shutdown: tp.Shutdown}, nil
+}
+
+// Shutdown mengirim span yang masih tertahan di batcher lalu menghentikan exporter.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.shutdown(ctx)
+}
+
+// endpointHost menerima "host:port" maupun URL lengkap seperti "http://collector:4318".
+func endpointHost(endpoint string) string {
+	endpoint = strings.TrimPrefix(endpoint, "http://")
+	endpoint = strings.TrimPrefix(endpoint, "https://")
+	return strings.TrimSuffix(endpoint, "/")
+}
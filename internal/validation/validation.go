@@ -0,0 +1,112 @@
+// Package validation
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/models"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate memeriksa tag `validate` pada struct request. Nama field pada pesan
+// kesalahan memakai nama JSON-nya agar sama dengan yang dikirim klien.
+var validate = func() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}()
+
+// Struct memvalidasi v berdasarkan tag `validate`-nya. Semua field yang tidak
+// valid, ditambah extra dari pemeriksaan yang tidak dapat dinyatakan lewat tag
+// (misalnya kecocokan dengan path), dikumpulkan dalam satu kesalahan
+// VALIDATION_FAILED.
+func Struct(v any, extra ...models.FieldError) error {
+	var fields []models.FieldError
+	if err := validate.Struct(v); err != nil {
+		var invalid validator.ValidationErrors
+		if !errors.As(err, &invalid) {
+			return err
+		}
+		for _, fe := range invalid {
+			fields = append(fields, models.FieldError{Field: fe.Field(), Message: message(fe)})
+		}
+	}
+
+	fields = append(fields, extra...)
+	if len(fields) == 0 {
+		return nil
+	}
+	return failed(fields...)
+}
+
+// failed membuat kesalahan VALIDATION_FAILED dari daftar field yang tidak valid.
+func failed(fields ...models.FieldError) error {
+	msg := "Request has invalid fields"
+	if len(fields) == 1 {
+		msg = fields[0].Message
+	}
+	return apperr.Validation("VALIDATION_FAILED", msg, fields...)
+}
+
+// message menyusun pesan yang dapat dibaca pengguna untuk satu aturan yang gagal.
+func message(fe validator.FieldError) string {
+	field, param := fe.Field(), fe.Param()
+	isString := fe.Kind() == reflect.String
+	isList := fe.Kind() == reflect.Slice
+
+	switch fe.Tag() {
+	case "required", "required_without":
+		return field + " is required"
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, param)
+	case "min":
+		if isString {
+			return fmt.Sprintf("%s must be at least %s characters", field, param)
+		}
+		if isList {
+			return fmt.Sprintf("%s must contain at least %s items", field, param)
+		}
+		return fmt.Sprintf("%s must be at least %s", field, param)
+	case "max":
+		if isString {
+			return fmt.Sprintf("%s must be at most %s characters", field, param)
+		}
+		if isList {
+			return fmt.Sprintf("%s must contain at most %s items", field, param)
+		}
+		return fmt.Sprintf("%s must be at most %s", field, param)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(param, " ", ", "))
+	case "gtfield":
+		return fmt.Sprintf("%s must be greater than %s", field, lowerFirst(param))
+	case "datetime":
+		if param == time.DateOnly {
+			return field + " must be a date (YYYY-MM-DD)"
+		}
+		return field + " must be an RFC3339 date-time"
+	case "url":
+		return field + " must be a valid URL"
+	case "timezone":
+		return field + " must be a valid IANA time zone"
+	}
+	return field + " is invalid"
+}
+
+// lowerFirst mengubah nama field Go (OpenHour) menjadi nama JSON-nya (openHour).
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
@@ -0,0 +1,113 @@
+package validation_test
+
+import (
+	"errors"
+	"testing"
+
+	"playcorner-be/internal/apperr"
+	"playcorner-be/internal/models"
+	"playcorner-be/internal/validation"
+)
+
+type request struct {
+	Name      string `json:"name" validate:"required,min=3,max=10"`
+	Console   string `json:"console" validate:"omitempty,oneof=PS4 PS5"`
+	Count     int    `json:"count" validate:"gt=0"`
+	OpenHour  int    `json:"openHour" validate:"min=0,max=23"`
+	CloseHour int    `json:"closeHour" validate:"gtfield=OpenHour"`
+	Timeslot  string `json:"timeslot" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Timezone  string `json:"timezone" validate:"omitempty,timezone"`
+	Internal  string `json:"-" validate:"max=1"`
+}
+
+func valid() request {
+	return request{Name: "Budi", Count: 1, OpenHour: 8, CloseHour: 22}
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *request)
+		extra  []models.FieldError
+		// want adalah pesan per field JSON; kosong berarti valid.
+		want map[string]string
+	}{
+		{name: "accepts a valid request", modify: func(r *request) {}},
+		{
+			name:   "reports a missing field",
+			modify: func(r *request) { r.Name = "" },
+			want:   map[string]string{"name": "name is required"},
+		},
+		{
+			name:   "distinguishes string length from numeric bounds",
+			modify: func(r *request) { r.Name = "Al"; r.OpenHour = 24; r.CloseHour = 25 },
+			want: map[string]string{
+				"name":     "name must be at least 3 characters",
+				"openHour": "openHour must be at most 23",
+			},
+		},
+		{
+			name:   "lists the allowed values",
+			modify: func(r *request) { r.Console = "Xbox" },
+			want:   map[string]string{"console": "console must be one of: PS4, PS5"},
+		},
+		{
+			name:   "names the compared field by its JSON name",
+			modify: func(r *request) { r.CloseHour = 8 },
+			want:   map[string]string{"closeHour": "closeHour must be greater than openHour"},
+		},
+		{
+			name:   "explains date-time and time zone formats",
+			modify: func(r *request) { r.Timeslot = "tomorrow"; r.Timezone = "Mars/Olympus" },
+			want: map[string]string{
+				"timeslot": "timeslot must be an RFC3339 date-time",
+				"timezone": "timezone must be a valid IANA time zone",
+			},
+		},
+		{
+			name:   "merges extra field errors",
+			modify: func(r *request) { r.Count = 0 },
+			extra:  []models.FieldError{{Field: "tvId", Message: "tvId must match the path"}},
+			want: map[string]string{
+				"count": "count must be greater than 0",
+				"tvId":  "tvId must match the path",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(&r)
+
+			err := validation.Struct(r, tt.extra...)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			var appErr *apperr.Error
+			if !errors.As(err, &appErr) || appErr.Code != "VALIDATION_FAILED" || appErr.Kind != apperr.KindValidation {
+				t.Fatalf("error = %v, want VALIDATION_FAILED", err)
+			}
+			got := map[string]string{}
+			for _, fe := range appErr.Fields {
+				got[fe.Field] = fe.Message
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("fields = %v, want %v", got, tt.want)
+			}
+			for field, msg := range tt.want {
+				if got[field] != msg {
+					t.Fatalf("field %s = %q, want %q", field, got[field], msg)
+				}
+			}
+			// Satu field memakai pesannya sendiri sebagai pesan utama
+			if len(tt.want) == 1 && appErr.Msg != appErr.Fields[0].Message {
+				t.Fatalf("message = %q, want %q", appErr.Msg, appErr.Fields[0].Message)
+			}
+		})
+	}
+}